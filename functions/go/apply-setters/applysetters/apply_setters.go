@@ -13,6 +13,14 @@ import (
 
 const SetterCommentIdentifier = "# kpt-set: "
 
+const (
+	// FnConfigAPIVersion is the apiVersion of the typed functionConfig
+	FnConfigAPIVersion = "fn.kpt.dev/v1alpha1"
+
+	// FnConfigKind is the kind of the typed functionConfig
+	FnConfigKind = "ApplySetters"
+)
+
 var _ kio.Filter = &ApplySetters{}
 
 // ApplySetters applies the setter values to the resource fields which are tagged
@@ -24,16 +32,79 @@ type ApplySetters struct {
 	// Results are the results of applying setter values
 	Results []*Result
 
+	// FailOnUnresolved if true, reports an error for each field tagged with a
+	// setter comment which references setters with no input values
+	FailOnUnresolved bool
+
+	// Errors are the setter values which failed validation and the fields
+	// left unresolved, resources are not modified if there are any
+	Errors []*Result
+
 	// filePath file path of resource
 	filePath string
+
+	// Sources are the in-package resources providing setter values, in the
+	// increasing order of precedence. Setters provides the values with the
+	// highest precedence.
+	Sources []SetterSource
+
+	// configPath is the file path of the functionConfig
+	configPath string
+
+	// parseEmbedded is true if the current resource opts in to apply the
+	// setters inside the YAML or JSON documents embedded in its string values
+	parseEmbedded bool
+
+	// inEmbedded is true if the setters are being applied inside an embedded document
+	inEmbedded bool
+
+	// Selectors scope the setters to the matching resources and fields,
+	// the setters are applied to all the resources if there are none
+	Selectors []Selector
+
+	// matchedSelectors are the Selectors matching the current resource
+	matchedSelectors []Selector
 }
 
+// Setter holds the input value for a setter along with the optional
+// type and constraints which the value must conform to
 type Setter struct {
 	// Name is the name of the setter
-	Name string
+	Name string `json:"name" yaml:"name"`
 
 	// Value is the input value for setter
-	Value string
+	Value string `json:"value" yaml:"value"`
+
+	// Type is the data type of the value, one of string, integer, number,
+	// boolean, array or object. The value is not type checked if it is empty.
+	Type string `json:"type,omitempty" yaml:"type,omitempty"`
+
+	// Enum is the list of allowed values for the setter
+	Enum []string `json:"enum,omitempty" yaml:"enum,omitempty"`
+
+	// Pattern is the regular expression which the entire value must match
+	Pattern string `json:"pattern,omitempty" yaml:"pattern,omitempty"`
+
+	// Minimum is the inclusive lower bound for the numeric value
+	Minimum *float64 `json:"minimum,omitempty" yaml:"minimum,omitempty"`
+
+	// Maximum is the inclusive upper bound for the numeric value
+	Maximum *float64 `json:"maximum,omitempty" yaml:"maximum,omitempty"`
+
+	// Required if true, the setter must be provided with a non-empty value
+	Required bool `json:"required,omitempty" yaml:"required,omitempty"`
+
+	// Source is the file path of the in-package resource which provided the value,
+	// or FnConfigSource if the value is provided by the functionConfig
+	Source string `json:"-" yaml:"-"`
+
+	// fieldPath is the path of the field holding the value in its source
+	fieldPath string
+
+	// schemaOnly is true if the setter is declared by an in-package source
+	// without a value, e.g. a setters.yaml schema, so that it only provides
+	// the type and constraints for the values from the other sources
+	schemaOnly bool
 }
 
 // Result holds result of search and replace operation
@@ -46,30 +117,57 @@ type Result struct {
 
 	// Value of the matching field
 	Value string
+
+	// Message describes the failure, it is only set for Errors
+	Message string
+
+	// Sources maps the name of each setter used to set the field to the
+	// source which provided its value
+	Sources map[string]string
 }
 
 // Filter implements Set as a yaml.Filter
 func (as *ApplySetters) Filter(nodes []*yaml.RNode) ([]*yaml.RNode, error) {
+	// merge the setter values from in-package sources with the input setters
+	if err := as.resolveSources(nodes); err != nil {
+		return nodes, err
+	}
+	// validate all the setter values before modifying any of the resources
+	as.validateSetters()
+	if len(as.Errors) > 0 {
+		return nodes, errors.Errorf("%d setter value(s) failed validation", len(as.Errors))
+	}
 	for i := range nodes {
 		filePath, _, err := kioutil.GetFileAnnotations(nodes[i])
 		if err != nil {
 			return nodes, err
 		}
 		as.filePath = filePath
+		selected, err := as.selectResource(nodes[i])
+		if err != nil {
+			return nodes, err
+		}
+		if !selected {
+			continue
+		}
+		as.parseEmbedded = embeddedEnabled(nodes[i])
 		err = accept(as, nodes[i])
 		if err != nil {
 			return nil, errors.Wrap(err)
 		}
 	}
+	if len(as.Errors) > 0 {
+		return nodes, errors.Errorf("%d field(s) have unresolved setters", len(as.Errors))
+	}
 	return nodes, nil
 }
 
 /*
 visitMapping takes input mapping node, and performs following steps
 checks if the key node of the input mapping node has line comment with SetterCommentIdentifier
-checks if the value node is of sequence node type
+checks if the value node is of sequence or mapping node type
 if yes to both, resolves the setter value for the setter name in the line comment
-replaces the existing sequence node with the new values provided by user
+replaces the existing sequence or mapping node with the new values provided by user
 
 e.g. for input of Mapping node
 
@@ -82,6 +180,15 @@ For input ApplySetters [name: env, value: "[stage, prod]"], qthe yaml node is tr
 environments: # kpt-set: ${env}
 - stage
 - prod
+
+e.g. for input of Mapping node
+
+nodeSelector: {disktype: hdd} # kpt-set: ${node-selector}
+
+For input ApplySetters [name: node-selector, value: "{disktype: ssd, zone: us-east1-b}"],
+the yaml node is transformed to
+
+nodeSelector: {disktype: ssd, zone: us-east1-b} # kpt-set: ${node-selector}
 */
 func (as *ApplySetters) visitMapping(object *yaml.RNode, path string) error {
 	return object.VisitFields(func(node *yaml.MapNode) error {
@@ -90,11 +197,16 @@ func (as *ApplySetters) visitMapping(object *yaml.RNode, path string) error {
 			return nil
 		}
 
-		// the aim of this method is to apply-setter for sequence nodes
-		if node.Value.YNode().Kind != yaml.SequenceNode {
-			// return if it is not a sequence node
+		// the aim of this method is to apply-setter for sequence and mapping nodes
+		kind := node.Value.YNode().Kind
+		if kind != yaml.SequenceNode && kind != yaml.MappingNode {
+			// return if it is not a sequence or mapping node
 			return nil
 		}
+		setterKind, valueKind := "array", "an array of values"
+		if kind == yaml.MappingNode {
+			setterKind, valueKind = "map", "a map of values"
+		}
 
 		lineComment := node.Key.YNode().LineComment
 		if node.Value.YNode().Style == yaml.FlowStyle {
@@ -109,26 +221,34 @@ func (as *ApplySetters) visitMapping(object *yaml.RNode, path string) error {
 			return nil
 		}
 
+		// add the key to the field path
+		fieldPath := strings.TrimPrefix(fmt.Sprintf("%s.%s", path, node.Key.YNode().Value), ".")
+		if !as.fieldSelected(fieldPath) {
+			return nil
+		}
+		as.checkUnresolved(setterPattern, fieldPath)
+
 		if !shouldSet(setterPattern, as.Setters) {
 			// this means there is no intent from user to modify this setter tagged resources
 			return nil
 		}
 
-		// since this setter pattern is found on sequence node, make sure that it is
+		if as.inEmbedded {
+			return errors.Errorf("%s setters are not supported in embedded documents: %q", setterKind, fieldPath)
+		}
+
+		// since this setter pattern is found on sequence or mapping node, make sure that it is
 		// not interpolation of setters, it should be simple setter e.g. ${environments}
 		if !validArraySetterPattern(setterPattern) {
-			return errors.Errorf("invalid setter pattern for array node: %q", setterPattern)
+			return errors.Errorf("invalid setter pattern for %s node: %q", setterKind, setterPattern)
 		}
 
 		// get the setter value for the setter name in the comment
 		sv := setterValue(as.Setters, setterPattern)
 
-		// add the key to the field path
-		fieldPath := strings.TrimPrefix(fmt.Sprintf("%s.%s", path, node.Key.YNode().Value), ".")
-
 		if sv == "" {
 			node.Value.YNode().Content = []*yaml.Node{}
-			// empty sequence must be FlowStyle e.g. env: [] # kpt-set: ${env}
+			// empty sequence or mapping must be FlowStyle e.g. env: [] # kpt-set: ${env}
 			node.Value.YNode().Style = yaml.FlowStyle
 			// setter pattern comment must be on value node
 			node.Value.YNode().LineComment = lineComment
@@ -137,6 +257,7 @@ func (as *ApplySetters) visitMapping(object *yaml.RNode, path string) error {
 				FilePath:  as.filePath,
 				FieldPath: fieldPath,
 				Value:     sv,
+				Sources:   as.setterSources(setterPattern),
 			})
 			return nil
 		}
@@ -144,17 +265,18 @@ func (as *ApplySetters) visitMapping(object *yaml.RNode, path string) error {
 		// parse the setter value as yaml node
 		rn, err := yaml.Parse(sv)
 		if err != nil {
-			return errors.Errorf("input to array setter must be an array of values, but found %q", sv)
+			return errors.Errorf("input to %s setter must be %s, but found %q", setterKind, valueKind, sv)
 		}
 
-		// the setter value must parse as sequence node
-		if rn.YNode().Kind != yaml.SequenceNode {
-			return errors.Errorf("input to array setter must be an array of values, but found %q", sv)
+		// the setter value must parse as the same kind of node
+		if rn.YNode().Kind != kind {
+			return errors.Errorf("input to %s setter must be %s, but found %q", setterKind, valueKind, sv)
 		}
 
 		node.Value.YNode().Content = rn.YNode().Content
 		node.Key.YNode().LineComment = lineComment
-		// non-empty sequences should be standardized to FoldedStyle
+		node.Value.YNode().LineComment = ""
+		// non-empty sequences and mappings should be standardized to FoldedStyle
 		// env: # kpt-set: ${env}
 		//  - foo
 		//  - bar
@@ -164,6 +286,7 @@ func (as *ApplySetters) visitMapping(object *yaml.RNode, path string) error {
 			FilePath:  as.filePath,
 			FieldPath: fieldPath,
 			Value:     sv,
+			Sources:   as.setterSources(setterPattern),
 		})
 		return nil
 	})
@@ -200,6 +323,17 @@ func (as *ApplySetters) visitScalar(object *yaml.RNode, path string) error {
 		return nil
 	}
 
+	if !as.fieldSelected(path) {
+		return nil
+	}
+
+	if as.parseEmbedded && !as.inEmbedded {
+		// apply the setters inside the embedded document if the value is one
+		if err := as.visitEmbedded(object, path); err != nil {
+			return err
+		}
+	}
+
 	// perform a direct set of the field if it matches
 	setterPattern := extractSetterPattern(object.YNode().LineComment)
 	if setterPattern == "" {
@@ -208,33 +342,39 @@ func (as *ApplySetters) visitScalar(object *yaml.RNode, path string) error {
 	}
 
 	curPattern := setterPattern
+	as.checkUnresolved(setterPattern, strings.TrimPrefix(path, "."))
 	if !shouldSet(setterPattern, as.Setters) {
 		// this means there is no intent from user to modify this setter tagged resources
 		return nil
 	}
 
-	// replace the setter names in comment pattern with provided values
-	for _, setter := range as.Setters {
-		setterPattern = strings.ReplaceAll(
-			setterPattern,
-			fmt.Sprintf("${%s}", setter.Name),
-			fmt.Sprintf("%v", setter.Value),
-		)
-	}
-
-	// replace the remaining setter names in comment pattern with values derived from current
-	// field value, these values are not provided by user
+	// replace the setter references in comment pattern with provided values, and the
+	// remaining ones with values derived from current field value, these values are
+	// not provided by user. The transforms in the references are applied to the values.
 	currentSetterValues := currentSetterValues(curPattern, object.YNode().Value)
-	for setterName, setterValue := range currentSetterValues {
-		setterPattern = strings.ReplaceAll(
-			setterPattern,
-			fmt.Sprintf("${%s}", setterName),
-			fmt.Sprintf("%v", setterValue),
-		)
+	var urs []string
+	var evalErr error
+	setterPattern = setterRefPattern.ReplaceAllStringFunc(curPattern, func(ref string) string {
+		r := parseSetterRef(ref)
+		value, found := setterValue(as.Setters, r.name), hasSetter(as.Setters, r.name)
+		if !found {
+			value, found = currentSetterValues[r.name]
+		}
+		if !found && !r.hasDefault() {
+			urs = append(urs, ref)
+			return ref
+		}
+		ev, err := r.evaluate(value)
+		if err != nil && evalErr == nil {
+			evalErr = errors.WrapPrefixf(err, "failed to evaluate %q", ref)
+		}
+		return ev
+	})
+	if evalErr != nil {
+		return evalErr
 	}
 
 	// check if there are unresolved setters and throw error
-	urs := unresolvedSetters(setterPattern)
 	if len(urs) > 0 {
 		return errors.Errorf("values for setters %v must be provided", urs)
 	}
@@ -244,20 +384,63 @@ func (as *ApplySetters) visitScalar(object *yaml.RNode, path string) error {
 		object.YNode().Style = yaml.DoubleQuotedStyle
 	}
 	object.YNode().Tag = yaml.NodeTagEmpty
+	if validArraySetterPattern(curPattern) {
+		// the field is fully parameterized by a single setter, so the tag
+		// can be derived from the type declared for the setter
+		object.YNode().Tag = nodeTag(setterType(as.Setters, curPattern))
+	}
+	if quoted(curPattern) {
+		object.YNode().Style = yaml.DoubleQuotedStyle
+		object.YNode().Tag = yaml.NodeTagString
+	}
 	as.Results = append(as.Results, &Result{
 		FilePath:  as.filePath,
 		FieldPath: strings.TrimPrefix(path, "."),
 		Value:     object.YNode().Value,
+		Sources:   as.setterSources(curPattern),
 	})
 	return nil
 }
 
+// checkUnresolved records an error for the field if FailOnUnresolved is set and
+// the setter pattern references setters which are not provided, the references
+// with a default value are resolved to the default
+func (as *ApplySetters) checkUnresolved(pattern, fieldPath string) {
+	if !as.FailOnUnresolved {
+		return
+	}
+	var missing []string
+	for _, s := range unresolvedSetters(pattern) {
+		if !hasSetter(as.Setters, s) && !parseSetterRef(s).hasDefault() {
+			missing = append(missing, setterName(s))
+		}
+	}
+	if len(missing) == 0 {
+		return
+	}
+	as.Errors = append(as.Errors, &Result{
+		FilePath:  as.filePath,
+		FieldPath: fieldPath,
+		Message:   fmt.Sprintf("field is parameterized by unresolved setters %v", missing),
+	})
+}
+
+// hasSetter returns true if the setter with the input name is provided
+func hasSetter(setters []Setter, ref string) bool {
+	for _, setter := range setters {
+		if setter.Name == setterName(ref) {
+			return true
+		}
+	}
+	return false
+}
+
 // shouldSet takes the setter pattern comment and setter values map and returns true
 // iff at least one of the setter names in the pattern match with the setter names
 // in input setterValues map
 func shouldSet(pattern string, setters []Setter) bool {
-	for _, s := range setters {
-		if strings.Contains(pattern, fmt.Sprintf("${%s}", s.Name)) {
+	for _, ref := range unresolvedSetters(pattern) {
+		if hasSetter(setters, ref) {
 			return true
 		}
 	}
@@ -311,21 +494,35 @@ func currentSetterValues(pattern, value string) map[string]string {
 			// and expect users to provide all values
 			return make(map[string]string)
 		}
-		res[clean(urs[i])] = setterValues[i]
+		// derive the setter value by reversing the transforms in the setter reference
+		r := parseSetterRef(urs[i])
+		if v, ok := r.reverse(setterValues[i]); ok {
+			res[r.name] = v
+		}
 	}
 	return res
 }
 
 // setterValue returns the value for the setter
-func setterValue(setters []Setter, setterName string) string {
+func setterValue(setters []Setter, ref string) string {
 	for _, setter := range setters {
-		if setter.Name == clean(setterName) {
+		if setter.Name == setterName(ref) {
 			return setter.Value
 		}
 	}
 	return ""
 }
 
+// setterType returns the declared type of the setter
+func setterType(setters []Setter, ref string) string {
+	for _, setter := range setters {
+		if setter.Name == setterName(ref) {
+			return setter.Type
+		}
+	}
+	return ""
+}
+
 // extractSetterPattern extracts the setter pattern from the line comment of the
 // yaml RNode. If the the line comment doesn't contain SetterCommentIdentifier
 // prefix, then it returns empty string
@@ -338,17 +535,21 @@ func extractSetterPattern(lineComment string) string {
 
 // validArraySetterPattern returns true if the array setter pattern is valid
 // pattern must not interpolation of setters, it should be simple setter e.g. ${environments}
+// transforms are not supported for array setters
 func validArraySetterPattern(pattern string) bool {
 	return len(unresolvedSetters(pattern)) == 1 &&
 		strings.HasPrefix(pattern, "${") &&
-		strings.HasSuffix(pattern, "}")
+		strings.HasSuffix(pattern, "}") &&
+		len(parseSetterRef(pattern).transforms) == 0
 }
 
+// setterRefPattern matches the setter references enclosed in ${}
+var setterRefPattern = regexp.MustCompile(`\$\{([^}]*)\}`)
+
 // unresolvedSetters returns the list of values enclosed in ${} present within given
 // pattern e.g. pattern = foo-${image}:${tag}-bar return ["${image}", "${tag}"]
 func unresolvedSetters(pattern string) []string {
-	re := regexp.MustCompile(`\$\{([^}]*)\}`)
-	return re.FindAllString(pattern, -1)
+	return setterRefPattern.FindAllString(pattern, -1)
 }
 
 // clean extracts value enclosed in ${}
@@ -357,9 +558,63 @@ func clean(input string) string {
 	return strings.TrimSuffix(strings.TrimPrefix(input, "${"), "}")
 }
 
-// Decode decodes the input yaml node into Set struct
-func Decode(rn *yaml.RNode, fcd *ApplySetters) {
-	for k, v := range rn.GetDataMap() {
-		fcd.Setters = append(fcd.Setters, Setter{Name: k, Value: v})
+// Decode decodes the input yaml node into Set struct. The setters are read from
+// the setters field of the typed ApplySetters functionConfig, or from the data
+// field of a ConfigMap otherwise.
+func Decode(rn *yaml.RNode, fcd *ApplySetters) error {
+	if rn == nil {
+		return nil
+	}
+	fcd.configPath, _, _ = kioutil.GetFileAnnotations(rn)
+	fc, err := decodeConfig(rn)
+	if err != nil {
+		return err
+	}
+	for i := range fc.Setters {
+		// the setters of the functionConfig always provide a value
+		fc.Setters[i].Source, fc.Setters[i].schemaOnly = FnConfigSource, false
+	}
+	fcd.Setters = append(fcd.Setters, fc.Setters...)
+	fcd.FailOnUnresolved = fc.FailOnUnresolved
+	fcd.Sources = fc.Sources
+	fcd.Selectors = fc.Selectors
+	return nil
+}
+
+// fnConfig holds the fields of the typed ApplySetters functionConfig
+type fnConfig struct {
+	Setters          []Setter       `yaml:"setters"`
+	FailOnUnresolved bool           `yaml:"failOnUnresolved"`
+	Sources          []SetterSource `yaml:"sources"`
+	Selectors        []Selector     `yaml:"selectors"`
+}
+
+// decodeConfig decodes the setters from either an ApplySetters resource
+// or a ConfigMap
+func decodeConfig(rn *yaml.RNode) (fnConfig, error) {
+	var fc fnConfig
+	if rn.GetKind() != FnConfigKind {
+		for k, v := range rn.GetDataMap() {
+			fc.Setters = append(fc.Setters, Setter{Name: k, Value: v, fieldPath: fmt.Sprintf("data.%s", k)})
+		}
+		return fc, nil
+	}
+	if err := yaml.Unmarshal([]byte(rn.MustString()), &fc); err != nil {
+		return fc, errors.WrapPrefixf(err, "failed to decode %s", FnConfigKind)
+	}
+	elements, err := rn.Pipe(yaml.Lookup("setters"))
+	if err != nil {
+		return fc, errors.Wrap(err)
+	}
+	for i, s := range fc.Setters {
+		if s.Name == "" {
+			return fc, errors.Errorf("setter name must not be empty in %s", FnConfigKind)
+		}
+		fc.Setters[i].fieldPath = fmt.Sprintf("setters[name=%s].value", s.Name)
+		if elements != nil && i < len(elements.Content()) &&
+			yaml.NewRNode(elements.Content()[i]).Field("value") == nil {
+			fc.Setters[i].schemaOnly = true
+		}
 	}
+	return fc, nil
 }
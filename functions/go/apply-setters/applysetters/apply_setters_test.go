@@ -431,6 +431,257 @@ env: # kpt-set: ${env}
 roles: # kpt-set: ${roles}
   - dev
   - prod
+`,
+		},
+		{
+			name: "set typed setters",
+			input: `apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: nginx-deployment # kpt-set: ${name}
+spec:
+  replicas: 3 # kpt-set: ${replicas}
+  paused: false # kpt-set: ${paused}
+  template:
+    metadata:
+      labels:
+        version: "1" # kpt-set: ${version}
+`,
+			config: `
+apiVersion: fn.kpt.dev/v1alpha1
+kind: ApplySetters
+metadata:
+  name: my-setters
+setters:
+  - name: name
+    value: my-app
+    pattern: "[a-z-]+"
+  - name: replicas
+    value: "5"
+    type: integer
+    minimum: 1
+    maximum: 10
+  - name: paused
+    value: "true"
+    type: boolean
+  - name: version
+    value: "2"
+    type: string
+`,
+			expectedResources: `apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: my-app # kpt-set: ${name}
+spec:
+  replicas: 5 # kpt-set: ${replicas}
+  paused: true # kpt-set: ${paused}
+  template:
+    metadata:
+      labels:
+        version: "2" # kpt-set: ${version}
+`,
+		},
+		{
+			name: "typed setters validation error",
+			input: `apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: nginx-deployment # kpt-set: ${name}
+spec:
+  replicas: 3 # kpt-set: ${replicas}
+`,
+			config: `
+apiVersion: fn.kpt.dev/v1alpha1
+kind: ApplySetters
+metadata:
+  name: my-setters
+setters:
+  - name: name
+    value: my-app
+  - name: replicas
+    value: "3a"
+    type: integer
+`,
+			expectedResources: `apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: nginx-deployment # kpt-set: ${name}
+spec:
+  replicas: 3 # kpt-set: ${replicas}
+`,
+			errMsg: "1 setter value(s) failed validation",
+		},
+		{
+			name: "required setter without value",
+			input: `apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: nginx-deployment # kpt-set: ${name}
+`,
+			config: `
+apiVersion: fn.kpt.dev/v1alpha1
+kind: ApplySetters
+metadata:
+  name: my-setters
+setters:
+  - name: name
+    value: ""
+    required: true
+`,
+			expectedResources: `apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: nginx-deployment # kpt-set: ${name}
+`,
+			errMsg: "1 setter value(s) failed validation",
+		},
+		{
+			name: "fail on unresolved setters",
+			input: `apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: nginx-deployment # kpt-set: ${name}
+spec:
+  template:
+    spec:
+      containers:
+        - name: nginx
+          image: nginx:1.7.9 # kpt-set: ${image}:${tag}
+      nodeSelector: # kpt-set: ${zones}
+        - us-east1-b
+`,
+			config: `
+apiVersion: fn.kpt.dev/v1alpha1
+kind: ApplySetters
+metadata:
+  name: my-setters
+failOnUnresolved: true
+setters:
+  - name: name
+    value: my-app
+  - name: image
+    value: ubuntu
+`,
+			expectedResources: `apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: nginx-deployment # kpt-set: ${name}
+spec:
+  template:
+    spec:
+      containers:
+        - name: nginx
+          image: nginx:1.7.9 # kpt-set: ${image}:${tag}
+      nodeSelector: # kpt-set: ${zones}
+        - us-east1-b
+`,
+			errMsg: "2 field(s) have unresolved setters",
+		},
+		{
+			name: "apply map setter",
+			config: `
+data:
+  resources: |
+    limits:
+      cpu: 500m
+      memory: 1Gi
+  node-selector: "{disktype: ssd}"
+  tolerations: ""
+`,
+			input: `apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: nginx-deployment
+spec:
+  template:
+    spec:
+      containers:
+        - name: nginx
+          resources: # kpt-set: ${resources}
+            limits:
+              cpu: 100m
+      nodeSelector: {disktype: hdd} # kpt-set: ${node-selector}
+      affinity: # kpt-set: ${tolerations}
+        nodeAffinity: {}
+`,
+			expectedResources: `apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: nginx-deployment
+spec:
+  template:
+    spec:
+      containers:
+        - name: nginx
+          resources: # kpt-set: ${resources}
+            limits:
+              cpu: 500m
+              memory: 1Gi
+      nodeSelector: # kpt-set: ${node-selector}
+        disktype: ssd
+      affinity: {} # kpt-set: ${tolerations}
+`,
+		},
+		{
+			name: "apply map setter with scalar error",
+			config: `
+data:
+  resources: 500m
+`,
+			input: `apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: nginx-deployment
+resources: # kpt-set: ${resources}
+  cpu: 100m
+`,
+			expectedResources: `apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: nginx-deployment
+resources: # kpt-set: ${resources}
+  cpu: 100m
+`,
+			errMsg: `input to map setter must be a map of values, but found "500m"`,
+		},
+		{
+			name: "set setters with transforms",
+			input: `apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: nginx-deployment # kpt-set: ${name|lower|trunc:10}
+  namespace: default # kpt-set: ${env|default:dev}
+spec:
+  template:
+    spec:
+      containers:
+        - name: nginx
+          image: nginx:1.7.9 # kpt-set: ${image|lower}:${tag}
+          resources:
+            limits:
+              cpu: 100 # kpt-set: ${cpu|quote}
+`,
+			config: `
+data:
+  name: My-Application
+  env: ""
+  image: Ubuntu
+  cpu: "500"
+`,
+			expectedResources: `apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: my-applica # kpt-set: ${name|lower|trunc:10}
+  namespace: dev # kpt-set: ${env|default:dev}
+spec:
+  template:
+    spec:
+      containers:
+        - name: nginx
+          image: ubuntu:1.7.9 # kpt-set: ${image|lower}:${tag}
+          resources:
+            limits:
+              cpu: "500" # kpt-set: ${cpu|quote}
 `,
 		},
 	}
@@ -458,7 +709,7 @@ roles: # kpt-set: ${roles}
 			if !assert.NoError(t, err) {
 				t.FailNow()
 			}
-			Decode(node, s)
+			err = Decode(node, s)
 			if !assert.NoError(t, err) {
 				t.FailNow()
 			}
@@ -535,6 +786,23 @@ var resolvePatternCases = []patternTest{
 			"app-image-tag":        "1.0.0",
 		},
 	},
+	{
+		name:    "setter values from pattern with transforms",
+		value:   "my-app:1.0.0",
+		pattern: `${image|lower}:${tag|quote}`,
+		expected: map[string]string{
+			"image": "my-app",
+			"tag":   "1.0.0",
+		},
+	},
+	{
+		name:    "setter values from pattern with transforms not reversible",
+		value:   "My-App:1.0.0",
+		pattern: `${image|lower}:${tag}`,
+		expected: map[string]string{
+			"tag": "1.0.0",
+		},
+	},
 	{
 		name:     "setter values from pattern unresolved",
 		value:    "foo-dev-bar-us-east-1-baz",
@@ -555,6 +823,44 @@ var resolvePatternCases = []patternTest{
 	},
 }
 
+func TestFailOnUnresolvedResults(t *testing.T) {
+	input, err := kyaml.Parse(`apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: my-config
+  annotations:
+    config.kubernetes.io/path: config.yaml
+data:
+  region: us-east1 # kpt-set: ${region}
+  zone: us-east1-b # kpt-set: ${region}-${zone}
+  project: my-project # kpt-set: ${project}
+  env: dev # kpt-set: ${env|default:dev}
+`)
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+	as := &ApplySetters{
+		Setters:          []Setter{{Name: "project", Value: "new-project"}},
+		FailOnUnresolved: true,
+	}
+	_, err = as.Filter([]*kyaml.RNode{input})
+	if !assert.Error(t, err) {
+		t.FailNow()
+	}
+	assert.Equal(t, []*Result{
+		{
+			FilePath:  "config.yaml",
+			FieldPath: "data.region",
+			Message:   "field is parameterized by unresolved setters [region]",
+		},
+		{
+			FilePath:  "config.yaml",
+			FieldPath: "data.zone",
+			Message:   "field is parameterized by unresolved setters [region zone]",
+		},
+	}, as.Errors)
+}
+
 func TestCurrentSetterValues(t *testing.T) {
 	for _, tests := range [][]patternTest{resolvePatternCases} {
 		for i := range tests {
@@ -0,0 +1,149 @@
+package applysetters
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"sigs.k8s.io/kustomize/kyaml/errors"
+	"sigs.k8s.io/kustomize/kyaml/yaml"
+)
+
+// EmbeddedSettersAnnotation opts in a resource to apply the setters tagged inside
+// the YAML or JSON documents embedded in its string field values
+const EmbeddedSettersAnnotation = "kpt.dev/embedded-setters"
+
+// embeddedEnabled returns true if the resource opts in to apply the setters
+// inside its embedded YAML or JSON documents
+func embeddedEnabled(node *yaml.RNode) bool {
+	return node.GetAnnotations()[EmbeddedSettersAnnotation] == "true"
+}
+
+// embeddedScalar is a scalar tagged with setter comment in an embedded document,
+// along with its position and value before the setters are applied
+type embeddedScalar struct {
+	node  *yaml.Node
+	value string
+	style yaml.Style
+}
+
+// visitEmbedded parses the string value of the scalar node as a YAML or JSON
+// document, applies the setters to the scalars tagged with setter comments in the
+// document, and replaces only the changed scalars in the string so that the
+// formatting of the document is kept.
+//
+// e.g. for input ApplySetters [name: replicas, value: 3] the embedded document
+//
+//	app.yaml: |
+//	  replicas: 1 # kpt-set: ${replicas}
+//	  # the log level
+//	  logLevel: info
+//
+// is transformed to
+//
+//	app.yaml: |
+//	  replicas: 3 # kpt-set: ${replicas}
+//	  # the log level
+//	  logLevel: info
+func (as *ApplySetters) visitEmbedded(object *yaml.RNode, path string) error {
+	value := object.YNode().Value
+	if !strings.Contains(value, SetterCommentIdentifier) {
+		return nil
+	}
+	doc, err := yaml.Parse(value)
+	if err != nil || (doc.YNode().Kind != yaml.MappingNode && doc.YNode().Kind != yaml.SequenceNode) {
+		// not an embedded document
+		return nil
+	}
+	scalars := taggedScalars(doc.YNode())
+
+	inner := &ApplySetters{
+		Setters:          as.Setters,
+		FailOnUnresolved: as.FailOnUnresolved,
+		Sources:          as.Sources,
+		filePath:         as.filePath,
+		inEmbedded:       true,
+	}
+	if err := accept(inner, doc); err != nil {
+		return errors.WrapPrefixf(err, "failed to apply setters in embedded document at %s", strings.TrimPrefix(path, "."))
+	}
+	for _, r := range inner.Results {
+		r.FieldPath = embeddedFieldPath(path, r.FieldPath)
+		as.Results = append(as.Results, r)
+	}
+	for _, r := range inner.Errors {
+		r.FieldPath = embeddedFieldPath(path, r.FieldPath)
+		as.Errors = append(as.Errors, r)
+	}
+
+	lines := strings.Split(value, "\n")
+	flow := doc.YNode().Style&yaml.FlowStyle != 0
+	for _, s := range scalars {
+		if s.node.Value == s.value && s.node.Style == s.style {
+			continue
+		}
+		if err := spliceScalar(lines, s, flow); err != nil {
+			return errors.WrapPrefixf(err, "failed to apply setters in embedded document at %s", strings.TrimPrefix(path, "."))
+		}
+	}
+	object.YNode().Value = strings.Join(lines, "\n")
+	return nil
+}
+
+// taggedScalars returns the scalars tagged with setter comments in the document
+func taggedScalars(node *yaml.Node) []*embeddedScalar {
+	var res []*embeddedScalar
+	if node.Kind == yaml.ScalarNode && extractSetterPattern(node.LineComment) != "" {
+		res = append(res, &embeddedScalar{node: node, value: node.Value, style: node.Style})
+	}
+	for _, c := range node.Content {
+		res = append(res, taggedScalars(c)...)
+	}
+	return res
+}
+
+// spliceScalar replaces the text of the scalar on its line in the document with
+// the new value, the rest of the line including the setter comment is kept
+func spliceScalar(lines []string, s *embeddedScalar, flow bool) error {
+	if s.style&(yaml.LiteralStyle|yaml.FoldedStyle) != 0 || s.node.Line < 1 || s.node.Line > len(lines) {
+		return errors.Errorf("setters can only be applied to single line scalars")
+	}
+	text := lines[s.node.Line-1]
+	i := strings.LastIndex(text, SetterCommentIdentifier)
+	if i < 0 {
+		return errors.Errorf("setter comment must be on the same line as the value %q", s.value)
+	}
+	line := []rune(text)
+	start, end := s.node.Column-1, len([]rune(text[:i]))
+	if start < 0 || start > end {
+		return errors.Errorf("unable to locate the value %q", s.value)
+	}
+	token := strings.TrimRight(string(line[start:end]), " \t")
+	if flow {
+		// keep the separator following the value in JSON documents
+		// e.g. "name": "foo", # kpt-set: ${name}
+		token = strings.TrimSuffix(token, ",")
+	}
+	rest := string(line[start+len([]rune(token)):])
+	lines[s.node.Line-1] = string(line[:start]) + renderScalar(s.node.Value, s.node.Style) + rest
+	return nil
+}
+
+// renderScalar renders the value in the input style
+func renderScalar(value string, style yaml.Style) string {
+	switch {
+	case style&yaml.DoubleQuotedStyle != 0:
+		return strconv.Quote(value)
+	case style&yaml.SingleQuotedStyle != 0:
+		return "'" + strings.ReplaceAll(value, "'", "''") + "'"
+	case value == "":
+		return `""`
+	}
+	return value
+}
+
+// embeddedFieldPath returns the path of the field in the embedded document
+// e.g. data.app.yaml:replicas
+func embeddedFieldPath(path, innerPath string) string {
+	return fmt.Sprintf("%s:%s", strings.TrimPrefix(path, "."), strings.TrimPrefix(innerPath, "."))
+}
@@ -0,0 +1,160 @@
+package applysetters
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	kyaml "sigs.k8s.io/kustomize/kyaml/yaml"
+)
+
+func TestApplySettersEmbedded(t *testing.T) {
+	var tests = []struct {
+		name              string
+		setters           []Setter
+		input             string
+		expectedResources string
+		expectedPaths     []string
+		errMsg            string
+	}{
+		{
+			name: "embedded yaml",
+			setters: []Setter{
+				{Name: "replicas", Value: "3"},
+				{Name: "level", Value: "debug"},
+				{Name: "host", Value: "db.prod"},
+			},
+			input: `apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: app-config
+  annotations:
+    kpt.dev/embedded-setters: "true"
+data:
+  app.yaml: |
+    # the number of workers
+    replicas:   1 # kpt-set: ${replicas}
+    logging:
+      level: 'info' # kpt-set: ${level}
+    db:
+      host: "db.dev"    # kpt-set: ${host}
+      port: 5432
+  env: dev # kpt-set: ${env}
+`,
+			expectedResources: `apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: app-config
+  annotations:
+    kpt.dev/embedded-setters: "true"
+data:
+  app.yaml: |
+    # the number of workers
+    replicas:   3 # kpt-set: ${replicas}
+    logging:
+      level: 'debug' # kpt-set: ${level}
+    db:
+      host: "db.prod"    # kpt-set: ${host}
+      port: 5432
+  env: dev # kpt-set: ${env}
+`,
+			expectedPaths: []string{"data.app.yaml:replicas", "data.app.yaml:logging.level", "data.app.yaml:db.host"},
+		},
+		{
+			name: "embedded json",
+			setters: []Setter{
+				{Name: "image", Value: "ubuntu"},
+				{Name: "replicas", Value: "3"},
+			},
+			input: `apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: app-config
+  annotations:
+    kpt.dev/embedded-setters: "true"
+data:
+  values.json: |
+    {
+      "image": "nginx:1.7.9", # kpt-set: ${image}:${tag}
+      "replicas": 1 # kpt-set: ${replicas}
+    }
+`,
+			expectedResources: `apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: app-config
+  annotations:
+    kpt.dev/embedded-setters: "true"
+data:
+  values.json: |
+    {
+      "image": "ubuntu:1.7.9", # kpt-set: ${image}:${tag}
+      "replicas": 3 # kpt-set: ${replicas}
+    }
+`,
+			expectedPaths: []string{"data.values.json:image", "data.values.json:replicas"},
+		},
+		{
+			name:    "not opted in",
+			setters: []Setter{{Name: "replicas", Value: "3"}},
+			input: `apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: app-config
+data:
+  app.yaml: |
+    replicas: 1 # kpt-set: ${replicas}
+`,
+			expectedResources: `apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: app-config
+data:
+  app.yaml: |
+    replicas: 1 # kpt-set: ${replicas}
+`,
+		},
+		{
+			name:    "array setters are not supported",
+			setters: []Setter{{Name: "env", Value: "[dev]"}},
+			input: `apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: app-config
+  annotations:
+    kpt.dev/embedded-setters: "true"
+data:
+  app.yaml: |
+    envs: # kpt-set: ${env}
+      - prod
+`,
+			errMsg: `array setters are not supported in embedded documents: "envs"`,
+		},
+	}
+	for i := range tests {
+		test := tests[i]
+		t.Run(test.name, func(t *testing.T) {
+			node, err := kyaml.Parse(test.input)
+			if !assert.NoError(t, err) {
+				t.FailNow()
+			}
+			as := &ApplySetters{Setters: test.setters}
+			_, err = as.Filter([]*kyaml.RNode{node})
+			if test.errMsg != "" {
+				if !assert.Error(t, err) {
+					t.FailNow()
+				}
+				assert.Contains(t, err.Error(), test.errMsg)
+				return
+			}
+			if !assert.NoError(t, err) {
+				t.FailNow()
+			}
+			assert.Equal(t, test.expectedResources, node.MustString())
+			var paths []string
+			for _, r := range as.Results {
+				paths = append(paths, r.FieldPath)
+			}
+			assert.Equal(t, test.expectedPaths, paths)
+		})
+	}
+}
@@ -0,0 +1,130 @@
+package applysetters
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// transformFuncs are the functions which can be applied to a setter value
+// within a setter reference e.g. ${name|lower|trunc:63}
+var transformFuncs = map[string]func(value, arg string) (string, error){
+	"lower": func(value, _ string) (string, error) {
+		return strings.ToLower(value), nil
+	},
+	"upper": func(value, _ string) (string, error) {
+		return strings.ToUpper(value), nil
+	},
+	"default": func(value, arg string) (string, error) {
+		if value == "" {
+			return arg, nil
+		}
+		return value, nil
+	},
+	"trunc": func(value, arg string) (string, error) {
+		n, err := strconv.Atoi(arg)
+		if err != nil || n < 0 {
+			return "", fmt.Errorf("trunc requires a non-negative integer argument, but found %q", arg)
+		}
+		// truncate by runes to not split multi-byte characters
+		if runes := []rune(value); len(runes) > n {
+			return string(runes[:n]), nil
+		}
+		return value, nil
+	},
+	// quote renders the field as a double quoted string, the value is unchanged
+	"quote": func(value, _ string) (string, error) {
+		return value, nil
+	},
+}
+
+// transform is a single function applied to the setter value
+type transform struct {
+	// name is the name of the transform function
+	name string
+
+	// arg is the argument to the transform function, e.g. 63 in trunc:63
+	arg string
+}
+
+// setterRef is a setter reference within the setter pattern along with the
+// transforms to be applied to the setter value in order
+// e.g. ${name|lower|trunc:63}
+type setterRef struct {
+	// name is the name of the referenced setter
+	name string
+
+	// transforms are applied to the setter value from left to right
+	transforms []transform
+}
+
+// parseSetterRef parses the setter reference enclosed in ${}. If any of the
+// segments separated by '|' is not a known transform, the entire reference is
+// treated as the setter name so that setter names containing '|' keep working
+func parseSetterRef(ref string) setterRef {
+	segments := strings.Split(clean(ref), "|")
+	r := setterRef{name: segments[0]}
+	for _, segment := range segments[1:] {
+		name, arg := segment, ""
+		if i := strings.Index(segment, ":"); i >= 0 {
+			name, arg = segment[:i], segment[i+1:]
+		}
+		if _, ok := transformFuncs[name]; !ok {
+			return setterRef{name: clean(ref)}
+		}
+		r.transforms = append(r.transforms, transform{name: name, arg: arg})
+	}
+	return r
+}
+
+// setterName returns the name of the setter referenced by the input setter
+// reference e.g. ${name|lower} returns name
+func setterName(ref string) string {
+	return parseSetterRef(ref).name
+}
+
+// quoted returns true if any of the setter references in the pattern
+// renders the field as a double quoted string
+func quoted(pattern string) bool {
+	for _, ref := range unresolvedSetters(pattern) {
+		for _, t := range parseSetterRef(ref).transforms {
+			if t.name == "quote" {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// hasDefault returns true if one of the transforms provides a default value
+func (r setterRef) hasDefault() bool {
+	for _, t := range r.transforms {
+		if t.name == "default" {
+			return true
+		}
+	}
+	return false
+}
+
+// evaluate applies the transforms of the setter reference to the input value
+func (r setterRef) evaluate(value string) (string, error) {
+	var err error
+	for _, t := range r.transforms {
+		value, err = transformFuncs[t.name](value, t.arg)
+		if err != nil {
+			return "", err
+		}
+	}
+	return value, nil
+}
+
+// reverse derives the setter value from the part of the field value which is
+// rendered by the setter reference. All the transforms are idempotent, so the
+// rendered value is a valid setter value iff it renders back to itself, e.g.
+// my-app is derived for ${name|lower} but My-App can't be derived.
+func (r setterRef) reverse(rendered string) (string, bool) {
+	if ev, err := r.evaluate(rendered); err != nil || ev != rendered {
+		return "", false
+	}
+	return rendered, true
+}
@@ -0,0 +1,180 @@
+package applysetters
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseSetterRef(t *testing.T) {
+	var tests = []struct {
+		name     string
+		ref      string
+		expected setterRef
+	}{
+		{
+			name:     "plain setter",
+			ref:      "${name}",
+			expected: setterRef{name: "name"},
+		},
+		{
+			name: "transforms with args",
+			ref:  "${name|lower|trunc:63|default:my-app}",
+			expected: setterRef{name: "name", transforms: []transform{
+				{name: "lower"},
+				{name: "trunc", arg: "63"},
+				{name: "default", arg: "my-app"},
+			}},
+		},
+		{
+			name:     "default containing separator",
+			ref:      "${url|default:http://localhost}",
+			expected: setterRef{name: "url", transforms: []transform{{name: "default", arg: "http://localhost"}}},
+		},
+		{
+			name:     "unknown transform is part of the name",
+			ref:      `${image-~!@#$%^&*()<>?"|}`,
+			expected: setterRef{name: `image-~!@#$%^&*()<>?"|`},
+		},
+	}
+	for i := range tests {
+		test := tests[i]
+		t.Run(test.name, func(t *testing.T) {
+			assert.Equal(t, test.expected, parseSetterRef(test.ref))
+		})
+	}
+}
+
+func TestEvaluateSetterRef(t *testing.T) {
+	var tests = []struct {
+		name     string
+		ref      string
+		value    string
+		expected string
+		errMsg   string
+	}{
+		{
+			name:     "lower",
+			ref:      "${name|lower}",
+			value:    "My-App",
+			expected: "my-app",
+		},
+		{
+			name:     "upper",
+			ref:      "${name|upper}",
+			value:    "My-App",
+			expected: "MY-APP",
+		},
+		{
+			name:     "default for empty value",
+			ref:      "${env|default:dev}",
+			value:    "",
+			expected: "dev",
+		},
+		{
+			name:     "default for non-empty value",
+			ref:      "${env|default:dev}",
+			value:    "prod",
+			expected: "prod",
+		},
+		{
+			name:     "trunc",
+			ref:      "${name|trunc:5}",
+			value:    "my-application",
+			expected: "my-ap",
+		},
+		{
+			name:     "trunc non-ASCII",
+			ref:      "${name|trunc:4}",
+			value:    "café-app",
+			expected: "café",
+		},
+		{
+			name:   "trunc with invalid arg",
+			ref:    "${name|trunc:five}",
+			value:  "my-application",
+			errMsg: `trunc requires a non-negative integer argument, but found "five"`,
+		},
+		{
+			name:     "quote",
+			ref:      "${cpu|quote}",
+			value:    "500m",
+			expected: "500m",
+		},
+		{
+			name:     "chained",
+			ref:      "${name|lower|trunc:6}",
+			value:    "My-Application",
+			expected: "my-app",
+		},
+	}
+	for i := range tests {
+		test := tests[i]
+		t.Run(test.name, func(t *testing.T) {
+			actual, err := parseSetterRef(test.ref).evaluate(test.value)
+			if test.errMsg != "" {
+				if !assert.Error(t, err) {
+					t.FailNow()
+				}
+				assert.Contains(t, err.Error(), test.errMsg)
+				return
+			}
+			if !assert.NoError(t, err) {
+				t.FailNow()
+			}
+			assert.Equal(t, test.expected, actual)
+		})
+	}
+}
+
+func TestReverseSetterRef(t *testing.T) {
+	var tests = []struct {
+		name     string
+		ref      string
+		rendered string
+		expected string
+		ok       bool
+	}{
+		{
+			name:     "quote",
+			ref:      "${cpu|quote}",
+			rendered: "500m",
+			expected: "500m",
+			ok:       true,
+		},
+		{
+			name:     "default",
+			ref:      "${env|default:dev}",
+			rendered: "dev",
+			expected: "dev",
+			ok:       true,
+		},
+		{
+			name:     "lower",
+			ref:      "${name|lower}",
+			rendered: "my-app",
+			expected: "my-app",
+			ok:       true,
+		},
+		{
+			name:     "lower with upper case value",
+			ref:      "${name|lower}",
+			rendered: "My-App",
+		},
+		{
+			name:     "trunc",
+			ref:      "${name|trunc:63}",
+			rendered: "my-app",
+			expected: "my-app",
+			ok:       true,
+		},
+	}
+	for i := range tests {
+		test := tests[i]
+		t.Run(test.name, func(t *testing.T) {
+			actual, ok := parseSetterRef(test.ref).reverse(test.rendered)
+			assert.Equal(t, test.ok, ok)
+			assert.Equal(t, test.expected, actual)
+		})
+	}
+}
@@ -0,0 +1,34 @@
+package applysetters
+
+import (
+	"github.com/GoogleContainerTools/kpt-functions-catalog/thirdparty/kyaml/selector"
+	"sigs.k8s.io/kustomize/kyaml/yaml"
+)
+
+// Selector selects the resources and the fields within them in which the
+// setters are applied. All the non-empty criteria must match.
+type Selector = selector.Selector
+
+// selectResource sets the selectors matching the resource to be used for the
+// fields of the resource and returns true if the resource is selected
+func (as *ApplySetters) selectResource(node *yaml.RNode) (bool, error) {
+	as.matchedSelectors = nil
+	if len(as.Selectors) == 0 {
+		return true, nil
+	}
+	matched, err := selector.MatchingResource(as.Selectors, node, as.filePath)
+	if err != nil {
+		return false, err
+	}
+	as.matchedSelectors = matched
+	return len(as.matchedSelectors) > 0, nil
+}
+
+// fieldSelected returns true if the setters must be applied to the field
+// in the input path of the current resource
+func (as *ApplySetters) fieldSelected(path string) bool {
+	if len(as.Selectors) == 0 {
+		return true
+	}
+	return selector.AnyMatchesField(as.matchedSelectors, path)
+}
@@ -0,0 +1,152 @@
+package applysetters
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"sigs.k8s.io/kustomize/kyaml/kio"
+)
+
+func TestApplySettersSelectors(t *testing.T) {
+	input := `apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: nginx
+  namespace: default # kpt-set: ${namespace}
+  labels:
+    tier: web
+  annotations:
+    config.kubernetes.io/path: app/deployment.yaml
+spec:
+  replicas: 1 # kpt-set: ${replicas}
+  template:
+    spec:
+      containers:
+        - name: nginx
+          image: nginx:1.7.1 # kpt-set: nginx:${tag}
+---
+apiVersion: v1
+kind: Service
+metadata:
+  name: nginx
+  namespace: default # kpt-set: ${namespace}
+  annotations:
+    config.kubernetes.io/path: app/service.yaml
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: unrelated
+  namespace: default # kpt-set: ${namespace}
+  annotations:
+    config.kubernetes.io/path: other/cm.yaml
+`
+	var tests = []struct {
+		name          string
+		selectors     []Selector
+		expectedPaths []string
+		errMsg        string
+	}{
+		{
+			name: "no selectors",
+			expectedPaths: []string{
+				"app/deployment.yaml:metadata.namespace",
+				"app/deployment.yaml:spec.replicas",
+				"app/deployment.yaml:spec.template.spec.containers[0].image",
+				"app/service.yaml:metadata.namespace",
+				"other/cm.yaml:metadata.namespace",
+			},
+		},
+		{
+			name:      "group and kind",
+			selectors: []Selector{{Group: "apps", Kind: "Deployment"}},
+			expectedPaths: []string{
+				"app/deployment.yaml:metadata.namespace",
+				"app/deployment.yaml:spec.replicas",
+				"app/deployment.yaml:spec.template.spec.containers[0].image",
+			},
+		},
+		{
+			name:      "version and name",
+			selectors: []Selector{{Version: "v1", Name: "nginx", Namespace: "default"}},
+			expectedPaths: []string{
+				"app/deployment.yaml:metadata.namespace",
+				"app/deployment.yaml:spec.replicas",
+				"app/deployment.yaml:spec.template.spec.containers[0].image",
+				"app/service.yaml:metadata.namespace",
+			},
+		},
+		{
+			name:      "file path glob and labels",
+			selectors: []Selector{{Path: "app/**", Labels: map[string]string{"tier": "web"}}},
+			expectedPaths: []string{
+				"app/deployment.yaml:metadata.namespace",
+				"app/deployment.yaml:spec.replicas",
+				"app/deployment.yaml:spec.template.spec.containers[0].image",
+			},
+		},
+		{
+			name: "field path patterns",
+			selectors: []Selector{
+				{Kind: "Deployment", FieldPath: "spec.**.containers[*].image"},
+				{Path: "app/*.yaml", FieldPath: "metadata.namespace"},
+			},
+			expectedPaths: []string{
+				"app/deployment.yaml:metadata.namespace",
+				"app/deployment.yaml:spec.template.spec.containers[0].image",
+				"app/service.yaml:metadata.namespace",
+			},
+		},
+		{
+			name:      "invalid path pattern",
+			selectors: []Selector{{Path: "app/[*.yaml"}},
+			errMsg:    `invalid path pattern "app/[*.yaml"`,
+		},
+	}
+	for i := range tests {
+		test := tests[i]
+		t.Run(test.name, func(t *testing.T) {
+			nodes, err := (&kio.ByteReader{
+				Reader:                strings.NewReader(input),
+				OmitReaderAnnotations: true,
+			}).Read()
+			if !assert.NoError(t, err) {
+				t.FailNow()
+			}
+			as := &ApplySetters{
+				Setters: []Setter{
+					{Name: "namespace", Value: "prod"},
+					{Name: "replicas", Value: "3"},
+					{Name: "tag", Value: "1.8.0"},
+				},
+				Selectors: test.selectors,
+			}
+			_, err = as.Filter(nodes)
+			if test.errMsg != "" {
+				if !assert.Error(t, err) {
+					t.FailNow()
+				}
+				assert.Contains(t, err.Error(), test.errMsg)
+				return
+			}
+			if !assert.NoError(t, err) {
+				t.FailNow()
+			}
+			var paths []string
+			for _, r := range as.Results {
+				paths = append(paths, r.FilePath+":"+r.FieldPath)
+			}
+			assert.Equal(t, test.expectedPaths, paths)
+
+			// the fields which are not selected are not modified
+			var out bytes.Buffer
+			if !assert.NoError(t, kio.ByteWriter{Writer: &out}.Write(nodes)) {
+				t.FailNow()
+			}
+			assert.Equal(t, len(test.expectedPaths), strings.Count(out.String(), "prod")+
+				strings.Count(out.String(), "replicas: 3")+strings.Count(out.String(), "nginx:1.8.0"))
+		})
+	}
+}
@@ -0,0 +1,160 @@
+package applysetters
+
+import (
+	"fmt"
+	"sort"
+
+	"sigs.k8s.io/kustomize/kyaml/errors"
+	"sigs.k8s.io/kustomize/kyaml/fn/framework"
+	"sigs.k8s.io/kustomize/kyaml/kio/kioutil"
+	"sigs.k8s.io/kustomize/kyaml/yaml"
+)
+
+// FnConfigSource is the source of the setter values provided by the functionConfig
+const FnConfigSource = "functionConfig"
+
+// SetterSource selects the in-package ConfigMap or ApplySetters resources which
+// provide setter values, e.g. the package defaults or an environment overlay
+type SetterSource struct {
+	// Path is the file path of the resource relative to the package
+	Path string `json:"path,omitempty" yaml:"path,omitempty"`
+
+	// Selector selects the resources by labels, names or kinds. The matching
+	// resources are applied in the order of their file paths.
+	Selector *framework.Selector `json:"selector,omitempty" yaml:"selector,omitempty"`
+}
+
+// resources returns the resources selected by the source
+func (src SetterSource) resources(nodes []*yaml.RNode) ([]*yaml.RNode, error) {
+	if src.Path == "" && src.Selector == nil {
+		return nil, errors.Errorf("setter source must specify either path or selector")
+	}
+	var matched []*yaml.RNode
+	for _, node := range nodes {
+		filePath, _, err := kioutil.GetFileAnnotations(node)
+		if err != nil {
+			return nil, err
+		}
+		if src.Path != "" && filePath != src.Path {
+			continue
+		}
+		kind := node.GetKind()
+		if kind != "ConfigMap" && kind != FnConfigKind {
+			continue
+		}
+		matched = append(matched, node)
+	}
+	if src.Selector != nil {
+		var err error
+		matched, err = src.Selector.Filter(matched)
+		if err != nil {
+			return nil, err
+		}
+	}
+	sort.SliceStable(matched, func(i, j int) bool {
+		pi, _, _ := kioutil.GetFileAnnotations(matched[i])
+		pj, _, _ := kioutil.GetFileAnnotations(matched[j])
+		return pi < pj
+	})
+	return matched, nil
+}
+
+// resolveSources merges the setter values from Sources in the order of their
+// precedence, followed by the setters provided by the functionConfig. Each
+// setter records the source which provided its value.
+func (as *ApplySetters) resolveSources(nodes []*yaml.RNode) error {
+	if len(as.Sources) == 0 {
+		return nil
+	}
+	var merged []Setter
+	for i, src := range as.Sources {
+		rns, err := src.resources(nodes)
+		if err != nil {
+			return errors.WrapPrefixf(err, "failed to resolve setter source %d", i)
+		}
+		if len(rns) == 0 {
+			return errors.Errorf("setter source %d doesn't match any ConfigMap or %s resources", i, FnConfigKind)
+		}
+		for _, rn := range rns {
+			fc, err := decodeConfig(rn)
+			if err != nil {
+				return err
+			}
+			filePath, _, _ := kioutil.GetFileAnnotations(rn)
+			if filePath == "" {
+				filePath = fmt.Sprintf("%s/%s", rn.GetKind(), rn.GetName())
+			}
+			for _, s := range fc.Setters {
+				s.Source = filePath
+				merged = mergeSetter(merged, s)
+			}
+		}
+	}
+	for _, s := range as.Setters {
+		merged = mergeSetter(merged, s)
+	}
+	as.Setters = nil
+	for _, s := range merged {
+		// the setters declared only by a schema have no value to apply,
+		// unless they are required in which case the validation fails
+		if s.schemaOnly && !s.Required {
+			continue
+		}
+		as.Setters = append(as.Setters, s)
+	}
+	return nil
+}
+
+// mergeSetter overrides the value of the setter with the same name in the input
+// setters, or adds the setter if it is not present. The constraints declared
+// by the overridden setter are retained unless the setter declares its own.
+// A setter declared only by a schema overrides the constraints but not the value.
+func mergeSetter(setters []Setter, s Setter) []Setter {
+	for i := range setters {
+		if setters[i].Name != s.Name {
+			continue
+		}
+		cur := setters[i]
+		if !s.schemaOnly {
+			cur.Value, cur.Source, cur.fieldPath = s.Value, s.Source, s.fieldPath
+			cur.schemaOnly = false
+		}
+		if s.Type != "" {
+			cur.Type = s.Type
+		}
+		if len(s.Enum) > 0 {
+			cur.Enum = s.Enum
+		}
+		if s.Pattern != "" {
+			cur.Pattern = s.Pattern
+		}
+		if s.Minimum != nil {
+			cur.Minimum = s.Minimum
+		}
+		if s.Maximum != nil {
+			cur.Maximum = s.Maximum
+		}
+		cur.Required = cur.Required || s.Required
+		setters[i] = cur
+		return setters
+	}
+	return append(setters, s)
+}
+
+// setterSources returns the sources of the values of the provided setters
+// referenced in the setter pattern, if the values are layered from Sources
+func (as *ApplySetters) setterSources(pattern string) map[string]string {
+	if len(as.Sources) == 0 {
+		return nil
+	}
+	res := make(map[string]string)
+	for _, ref := range unresolvedSetters(pattern) {
+		name := setterName(ref)
+		for _, s := range as.Setters {
+			if s.Name == name {
+				res[name] = s.Source
+			}
+		}
+	}
+	return res
+}
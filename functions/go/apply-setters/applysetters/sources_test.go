@@ -0,0 +1,312 @@
+package applysetters
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"sigs.k8s.io/kustomize/kyaml/kio"
+	kyaml "sigs.k8s.io/kustomize/kyaml/yaml"
+)
+
+func TestApplySettersSources(t *testing.T) {
+	var tests = []struct {
+		name              string
+		config            string
+		input             string
+		expectedResources string
+		expectedResults   []*Result
+		errMsg            string
+	}{
+		{
+			name: "layered sources by path and selector",
+			config: `
+apiVersion: fn.kpt.dev/v1alpha1
+kind: ApplySetters
+metadata:
+  name: my-setters
+sources:
+  - path: setters.yaml
+  - selector:
+      labels:
+        env: prod
+setters:
+  - name: tag
+    value: 1.8.0
+`,
+			input: `apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: defaults
+  annotations:
+    config.kubernetes.io/path: setters.yaml
+data:
+  replicas: "1"
+  image: nginx
+  tag: 1.7.9
+---
+apiVersion: fn.kpt.dev/v1alpha1
+kind: ApplySetters
+metadata:
+  name: prod
+  labels:
+    env: prod
+  annotations:
+    config.kubernetes.io/path: overlays/prod/setters.yaml
+setters:
+  - name: replicas
+    value: "5"
+    type: integer
+  - name: tag
+    value: 1.7.10
+---
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: nginx-deployment
+  annotations:
+    config.kubernetes.io/path: deployment.yaml
+spec:
+  replicas: 3 # kpt-set: ${replicas}
+  template:
+    spec:
+      containers:
+        - name: nginx
+          image: nginx:1.7.9 # kpt-set: ${image}:${tag}
+`,
+			expectedResources: `apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: nginx-deployment
+  annotations:
+    config.kubernetes.io/path: deployment.yaml
+spec:
+  replicas: 5 # kpt-set: ${replicas}
+  template:
+    spec:
+      containers:
+        - name: nginx
+          image: nginx:1.8.0 # kpt-set: ${image}:${tag}
+`,
+			expectedResults: []*Result{
+				{
+					FilePath:  "deployment.yaml",
+					FieldPath: "spec.replicas",
+					Value:     "5",
+					Sources:   map[string]string{"replicas": "overlays/prod/setters.yaml"},
+				},
+				{
+					FilePath:  "deployment.yaml",
+					FieldPath: "spec.template.spec.containers[0].image",
+					Value:     "nginx:1.8.0",
+					Sources:   map[string]string{"image": "setters.yaml", "tag": FnConfigSource},
+				},
+			},
+		},
+		{
+			name: "constraints from lower precedence source",
+			config: `
+apiVersion: fn.kpt.dev/v1alpha1
+kind: ApplySetters
+metadata:
+  name: my-setters
+sources:
+  - path: setters.yaml
+setters:
+  - name: replicas
+    value: three
+`,
+			input: `apiVersion: fn.kpt.dev/v1alpha1
+kind: ApplySetters
+metadata:
+  name: defaults
+  annotations:
+    config.kubernetes.io/path: setters.yaml
+setters:
+  - name: replicas
+    value: "1"
+    type: integer
+---
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: nginx-deployment
+  annotations:
+    config.kubernetes.io/path: deployment.yaml
+spec:
+  replicas: 3 # kpt-set: ${replicas}
+`,
+			errMsg: "1 setter value(s) failed validation",
+		},
+		{
+			name: "types declared by setters.yaml schema",
+			config: `
+apiVersion: fn.kpt.dev/v1alpha1
+kind: ApplySetters
+metadata:
+  name: my-setters
+sources:
+  - path: setters.yaml
+setters:
+  - name: replicas
+    value: "5"
+`,
+			input: `apiVersion: fn.kpt.dev/v1alpha1
+kind: ApplySetters
+metadata:
+  name: schema
+  annotations:
+    config.kubernetes.io/path: setters.yaml
+setters:
+  - name: replicas
+    type: integer
+    maximum: 10
+  - name: env
+    enum: [dev, prod]
+---
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: nginx-deployment
+  annotations:
+    config.kubernetes.io/path: deployment.yaml
+  labels:
+    env: dev # kpt-set: ${env}
+spec:
+  replicas: 3 # kpt-set: ${replicas}
+`,
+			expectedResources: `apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: nginx-deployment
+  annotations:
+    config.kubernetes.io/path: deployment.yaml
+  labels:
+    env: dev # kpt-set: ${env}
+spec:
+  replicas: 5 # kpt-set: ${replicas}
+`,
+			expectedResults: []*Result{
+				{
+					FilePath:  "deployment.yaml",
+					FieldPath: "spec.replicas",
+					Value:     "5",
+					Sources:   map[string]string{"replicas": FnConfigSource},
+				},
+			},
+		},
+		{
+			name: "value violates setters.yaml schema",
+			config: `
+apiVersion: fn.kpt.dev/v1alpha1
+kind: ApplySetters
+metadata:
+  name: my-setters
+sources:
+  - path: setters.yaml
+setters:
+  - name: replicas
+    value: "50"
+`,
+			input: `apiVersion: fn.kpt.dev/v1alpha1
+kind: ApplySetters
+metadata:
+  name: schema
+  annotations:
+    config.kubernetes.io/path: setters.yaml
+setters:
+  - name: replicas
+    type: integer
+    maximum: 10
+---
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: nginx-deployment
+  annotations:
+    config.kubernetes.io/path: deployment.yaml
+spec:
+  replicas: 3 # kpt-set: ${replicas}
+`,
+			errMsg: "1 setter value(s) failed validation",
+		},
+		{
+			name: "required by setters.yaml schema",
+			config: `
+apiVersion: fn.kpt.dev/v1alpha1
+kind: ApplySetters
+metadata:
+  name: my-setters
+sources:
+  - path: setters.yaml
+`,
+			input: `apiVersion: fn.kpt.dev/v1alpha1
+kind: ApplySetters
+metadata:
+  name: schema
+  annotations:
+    config.kubernetes.io/path: setters.yaml
+setters:
+  - name: project-id
+    required: true
+---
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: nginx-deployment
+  annotations:
+    config.kubernetes.io/path: deployment.yaml
+`,
+			errMsg: "1 setter value(s) failed validation",
+		},
+		{
+			name: "source without matches",
+			config: `
+apiVersion: fn.kpt.dev/v1alpha1
+kind: ApplySetters
+metadata:
+  name: my-setters
+sources:
+  - path: missing.yaml
+`,
+			input: `apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: nginx-deployment
+  annotations:
+    config.kubernetes.io/path: deployment.yaml
+`,
+			errMsg: "setter source 0 doesn't match any ConfigMap or ApplySetters resources",
+		},
+	}
+	for i := range tests {
+		test := tests[i]
+		t.Run(test.name, func(t *testing.T) {
+			as := &ApplySetters{}
+			err := Decode(kyaml.MustParse(test.config), as)
+			if !assert.NoError(t, err) {
+				t.FailNow()
+			}
+			nodes, err := (&kio.ByteReader{
+				Reader:                strings.NewReader(test.input),
+				OmitReaderAnnotations: true,
+			}).Read()
+			if !assert.NoError(t, err) {
+				t.FailNow()
+			}
+			_, err = as.Filter(nodes)
+			if test.errMsg != "" {
+				if !assert.Error(t, err) {
+					t.FailNow()
+				}
+				assert.Contains(t, err.Error(), test.errMsg)
+				return
+			}
+			if !assert.NoError(t, err) {
+				t.FailNow()
+			}
+			assert.Equal(t, test.expectedResources, nodes[len(nodes)-1].MustString())
+			assert.Equal(t, test.expectedResults, as.Results)
+		})
+	}
+}
@@ -0,0 +1,135 @@
+package applysetters
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"sigs.k8s.io/kustomize/kyaml/yaml"
+)
+
+// data types which can be declared for a setter value
+const (
+	StringType  = "string"
+	IntegerType = "integer"
+	NumberType  = "number"
+	BooleanType = "boolean"
+	ArrayType   = "array"
+	ObjectType  = "object"
+)
+
+// validateSetters checks the value of each setter against the type and
+// constraints declared for it and records a Result in as.Errors for every
+// setter value which doesn't conform
+func (as *ApplySetters) validateSetters() {
+	for _, setter := range as.Setters {
+		if err := validateSetter(setter); err != nil {
+			filePath := setter.Source
+			if filePath == FnConfigSource || filePath == "" {
+				filePath = as.configPath
+			}
+			as.Errors = append(as.Errors, &Result{
+				FilePath:  filePath,
+				FieldPath: setter.fieldPath,
+				Value:     setter.Value,
+				Message:   fmt.Sprintf("invalid value %q for setter %q: %s", setter.Value, setter.Name, err.Error()),
+			})
+		}
+	}
+}
+
+// validateSetter returns an error if the setter value is required but empty, or
+// doesn't conform to the type, enum, pattern or range declared for the setter
+func validateSetter(setter Setter) error {
+	v := setter.Value
+	if setter.Required && v == "" {
+		return fmt.Errorf("value is required")
+	}
+	switch setter.Type {
+	case "", StringType:
+	case IntegerType:
+		if _, err := strconv.ParseInt(v, 10, 64); err != nil {
+			return fmt.Errorf("must be an integer")
+		}
+	case NumberType:
+		if _, err := strconv.ParseFloat(v, 64); err != nil {
+			return fmt.Errorf("must be a number")
+		}
+	case BooleanType:
+		// only the canonical literals, as the field is tagged as !!bool
+		if v != "true" && v != "false" {
+			return fmt.Errorf("must be a boolean, either true or false")
+		}
+	case ArrayType:
+		rn, err := yaml.Parse(v)
+		if v != "" && (err != nil || rn.YNode().Kind != yaml.SequenceNode) {
+			return fmt.Errorf("must be an array of values")
+		}
+	case ObjectType:
+		rn, err := yaml.Parse(v)
+		if v != "" && (err != nil || rn.YNode().Kind != yaml.MappingNode) {
+			return fmt.Errorf("must be a map of values")
+		}
+	default:
+		return fmt.Errorf("unknown type %q, must be one of %s", setter.Type,
+			strings.Join([]string{StringType, IntegerType, NumberType, BooleanType, ArrayType, ObjectType}, ", "))
+	}
+
+	if len(setter.Enum) > 0 && !contains(setter.Enum, v) {
+		return fmt.Errorf("must be one of [%s]", strings.Join(setter.Enum, ", "))
+	}
+
+	if setter.Pattern != "" {
+		// match the entire value similar to the pattern keyword in OpenAPI schema
+		re, err := regexp.Compile(fmt.Sprintf("^(?:%s)$", setter.Pattern))
+		if err != nil {
+			return fmt.Errorf("invalid pattern %q: %s", setter.Pattern, err.Error())
+		}
+		if !re.MatchString(v) {
+			return fmt.Errorf("must match pattern %q", setter.Pattern)
+		}
+	}
+
+	if setter.Minimum == nil && setter.Maximum == nil {
+		return nil
+	}
+	n, err := strconv.ParseFloat(v, 64)
+	if err != nil {
+		return fmt.Errorf("must be numeric to compare with minimum and maximum")
+	}
+	if setter.Minimum != nil && n < *setter.Minimum {
+		return fmt.Errorf("must be greater than or equal to %v", *setter.Minimum)
+	}
+	if setter.Maximum != nil && n > *setter.Maximum {
+		return fmt.Errorf("must be less than or equal to %v", *setter.Maximum)
+	}
+	return nil
+}
+
+// nodeTag returns the yaml tag which must be set on a field which is fully
+// parameterized by a setter of the input type, empty string lets the yaml
+// encoder resolve the tag from the value
+func nodeTag(setterType string) string {
+	switch setterType {
+	case StringType:
+		return yaml.NodeTagString
+	case IntegerType:
+		return yaml.NodeTagInt
+	case NumberType:
+		return yaml.NodeTagFloat
+	case BooleanType:
+		return yaml.NodeTagBool
+	}
+	return yaml.NodeTagEmpty
+}
+
+// contains returns true if the value is present in the input list
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
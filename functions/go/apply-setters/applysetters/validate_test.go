@@ -0,0 +1,162 @@
+package applysetters
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateSetter(t *testing.T) {
+	one := float64(1)
+	ten := float64(10)
+	var tests = []struct {
+		name   string
+		setter Setter
+		errMsg string
+	}{
+		{
+			name:   "untyped",
+			setter: Setter{Name: "foo", Value: "bar"},
+		},
+		{
+			name:   "integer",
+			setter: Setter{Name: "replicas", Value: "3", Type: IntegerType},
+		},
+		{
+			name:   "invalid integer",
+			setter: Setter{Name: "replicas", Value: "three", Type: IntegerType},
+			errMsg: "must be an integer",
+		},
+		{
+			name:   "number",
+			setter: Setter{Name: "ratio", Value: "0.5", Type: NumberType},
+		},
+		{
+			name:   "invalid number",
+			setter: Setter{Name: "ratio", Value: "half", Type: NumberType},
+			errMsg: "must be a number",
+		},
+		{
+			name:   "boolean",
+			setter: Setter{Name: "enabled", Value: "false", Type: BooleanType},
+		},
+		{
+			name:   "invalid boolean",
+			setter: Setter{Name: "enabled", Value: "yes please", Type: BooleanType},
+			errMsg: "must be a boolean",
+		},
+		{
+			name:   "non-canonical boolean 1",
+			setter: Setter{Name: "enabled", Value: "1", Type: BooleanType},
+			errMsg: "must be a boolean, either true or false",
+		},
+		{
+			name:   "non-canonical boolean t",
+			setter: Setter{Name: "enabled", Value: "t", Type: BooleanType},
+			errMsg: "must be a boolean, either true or false",
+		},
+		{
+			name:   "non-canonical boolean TRUE",
+			setter: Setter{Name: "enabled", Value: "TRUE", Type: BooleanType},
+			errMsg: "must be a boolean, either true or false",
+		},
+		{
+			name:   "non-canonical boolean F",
+			setter: Setter{Name: "enabled", Value: "F", Type: BooleanType},
+			errMsg: "must be a boolean, either true or false",
+		},
+		{
+			name:   "array",
+			setter: Setter{Name: "env", Value: "[dev, prod]", Type: ArrayType},
+		},
+		{
+			name:   "invalid array",
+			setter: Setter{Name: "env", Value: "dev", Type: ArrayType},
+			errMsg: "must be an array of values",
+		},
+		{
+			name:   "object",
+			setter: Setter{Name: "resources", Value: "{cpu: 100m}", Type: ObjectType},
+		},
+		{
+			name:   "invalid object",
+			setter: Setter{Name: "resources", Value: "[cpu]", Type: ObjectType},
+			errMsg: "must be a map of values",
+		},
+		{
+			name:   "unknown type",
+			setter: Setter{Name: "env", Value: "dev", Type: "list"},
+			errMsg: `unknown type "list"`,
+		},
+		{
+			name:   "enum",
+			setter: Setter{Name: "env", Value: "dev", Enum: []string{"dev", "prod"}},
+		},
+		{
+			name:   "invalid enum",
+			setter: Setter{Name: "env", Value: "stage", Enum: []string{"dev", "prod"}},
+			errMsg: "must be one of [dev, prod]",
+		},
+		{
+			name:   "pattern",
+			setter: Setter{Name: "name", Value: "my-app", Pattern: "[a-z-]+"},
+		},
+		{
+			name:   "pattern matches entire value",
+			setter: Setter{Name: "name", Value: "My-app", Pattern: "[a-z-]+"},
+			errMsg: `must match pattern "[a-z-]+"`,
+		},
+		{
+			name:   "invalid pattern",
+			setter: Setter{Name: "name", Value: "my-app", Pattern: "[a-z"},
+			errMsg: `invalid pattern "[a-z"`,
+		},
+		{
+			name:   "in range",
+			setter: Setter{Name: "replicas", Value: "10", Type: IntegerType, Minimum: &one, Maximum: &ten},
+		},
+		{
+			name:   "less than minimum",
+			setter: Setter{Name: "replicas", Value: "0", Type: IntegerType, Minimum: &one},
+			errMsg: "must be greater than or equal to 1",
+		},
+		{
+			name:   "greater than maximum",
+			setter: Setter{Name: "replicas", Value: "11", Type: IntegerType, Maximum: &ten},
+			errMsg: "must be less than or equal to 10",
+		},
+	}
+	for i := range tests {
+		test := tests[i]
+		t.Run(test.name, func(t *testing.T) {
+			err := validateSetter(test.setter)
+			if test.errMsg == "" {
+				assert.NoError(t, err)
+				return
+			}
+			if !assert.Error(t, err) {
+				t.FailNow()
+			}
+			assert.Contains(t, err.Error(), test.errMsg)
+		})
+	}
+}
+
+func TestValidateSetters(t *testing.T) {
+	as := &ApplySetters{
+		Setters: []Setter{
+			{Name: "replicas", Value: "3a", Type: IntegerType, Source: FnConfigSource, fieldPath: "data.replicas"},
+			{Name: "env", Value: "dev"},
+		},
+		configPath: "setters.yaml",
+	}
+	as.validateSetters()
+	assert.Equal(t, []*Result{
+		{
+			FilePath:  "setters.yaml",
+			FieldPath: "data.replicas",
+			Value:     "3a",
+			Message:   `invalid value "3a" for setter "replicas": must be an integer`,
+		},
+	}, as.Errors)
+}
@@ -19,7 +19,20 @@ valid, adheres to the KRM schema, and can be consumed by other tools.
 A setter comment can be derived by replacing all the instances of setter values 
 in the field value, with the corresponding setter names along with 'kpt-set:' prefix.
 
-  e.g. image: gcr.io/nginx:1.16.1 # kpt-set: gcr.io/${image}:${tag}`
+  e.g. image: gcr.io/nginx:1.16.1 # kpt-set: gcr.io/${image}:${tag}
+
+**Setter Transforms**: A setter reference in the setter comment can apply a chain of
+transforms, separated by ` + "`" + `|` + "`" + `, to the setter value before it is rendered in the field.
+
+| Transform          | Description                                                  |
+|--------------------|--------------------------------------------------------------|
+| ` + "`" + `lower` + "`" + `            | converts the value to lower case                             |
+| ` + "`" + `upper` + "`" + `            | converts the value to upper case                             |
+| ` + "`" + `default:<value>` + "`" + `  | uses ` + "`" + `<value>` + "`" + ` if the setter value is empty                  |
+| ` + "`" + `trunc:<length>` + "`" + `   | truncates the value to at most ` + "`" + `<length>` + "`" + ` characters         |
+| ` + "`" + `quote` + "`" + `            | renders the field as a double quoted string                  |
+
+  e.g. name: my-app-dev # kpt-set: ${app|lower|trunc:63}-${env|default:dev}`
 var ApplySettersLong = `
 We use ConfigMap to configure the ` + "`" + `apply-setters` + "`" + ` function. The desired setter
 values are provided as key-value pairs using ` + "`" + `data` + "`" + ` field where key is the name of the
@@ -33,9 +46,132 @@ setter and value is the new desired value for the setter.
     setter_name1: setter_value1
     setter_name2: setter_value2
 
+Alternatively, the setters can be declared using the ` + "`" + `ApplySetters` + "`" + ` custom resource.
+Along with the value, each setter can optionally declare the ` + "`" + `type` + "`" + ` of the value
+(` + "`" + `string` + "`" + `, ` + "`" + `integer` + "`" + `, ` + "`" + `number` + "`" + `, ` + "`" + `boolean` + "`" + `, ` + "`" + `array` + "`" + ` or ` + "`" + `object` + "`" + `), the list of allowed values
+using ` + "`" + `enum` + "`" + `, a regular expression using ` + "`" + `pattern` + "`" + ` which must match the entire value,
+and the inclusive bounds of a numeric value using ` + "`" + `minimum` + "`" + ` and ` + "`" + `maximum` + "`" + `.
+
+  apiVersion: fn.kpt.dev/v1alpha1
+  kind: ApplySetters
+  metadata:
+    name: apply-setters-func-config
+  setters:
+    - name: replicas
+      value: "3"
+      type: integer
+      minimum: 1
+      maximum: 10
+    - name: env
+      value: dev
+      enum: [dev, staging, prod]
+
+The value of a ` + "`" + `boolean` + "`" + ` setter must be either ` + "`" + `true` + "`" + ` or ` + "`" + `false` + "`" + `.
+
+If any of the setter values doesn't conform to its declaration, the function
+reports an error result for each of the invalid setter values and none of the
+resources are modified. If a field is fully parameterized by a setter with a
+declared ` + "`" + `type` + "`" + `, the field value is rendered with the corresponding YAML type,
+e.g. the value ` + "`" + `"3"` + "`" + ` of a ` + "`" + `string` + "`" + ` setter remains a quoted string.
+
+A setter can be marked with ` + "`" + `required: true` + "`" + `, in which case its value must not
+be empty. Setting ` + "`" + `failOnUnresolved: true` + "`" + ` makes the function report an error,
+along with the file and field path, for every field tagged with a setter comment
+which references a setter that is not provided. This can be used to ensure that
+no placeholder values are left in a hydrated package.
+
+  apiVersion: fn.kpt.dev/v1alpha1
+  kind: ApplySetters
+  metadata:
+    name: apply-setters-func-config
+  failOnUnresolved: true
+  setters:
+    - name: project-id
+      value: my-project
+      required: true
+
+The setter values can also be layered from ` + "`" + `ConfigMap` + "`" + ` or ` + "`" + `ApplySetters` + "`" + ` resources
+in the package, e.g. package defaults and an environment overlay, using ` + "`" + `sources` + "`" + `.
+Each source selects resources either by the file ` + "`" + `path` + "`" + ` or using a ` + "`" + `selector` + "`" + `
+with ` + "`" + `labels` + "`" + `, ` + "`" + `names` + "`" + ` or ` + "`" + `kinds` + "`" + `. The sources are listed in the increasing order
+of precedence and the ` + "`" + `setters` + "`" + ` in the ` + "`" + `functionConfig` + "`" + ` take the highest precedence.
+The types and constraints declared for a setter by a lower precedence source are
+retained, and the result for each field records the source of each setter value.
+A setter declared by an ` + "`" + `ApplySetters` + "`" + ` source without a ` + "`" + `value` + "`" + `, e.g. in a
+` + "`" + `setters.yaml` + "`" + ` schema of the package, only declares the type and constraints of
+the value provided by the other sources, and isn't applied otherwise.
+
+  apiVersion: fn.kpt.dev/v1alpha1
+  kind: ApplySetters
+  metadata:
+    name: apply-setters-func-config
+  sources:
+    - path: setters.yaml # package defaults
+    - selector: # environment overlay
+        labels:
+          env: prod
+  setters:
+    - name: tag
+      value: 1.16.2
+
+  # setters.yaml
+  apiVersion: fn.kpt.dev/v1alpha1
+  kind: ApplySetters
+  metadata:
+    name: setters-schema
+  setters:
+    - name: replicas
+      type: integer
+      minimum: 1
+    - name: tag
+      pattern: '[0-9]+\.[0-9]+\.[0-9]+'
+
+The setters can be scoped to a subset of the resources and fields using
+` + "`" + `selectors` + "`" + ` in the ` + "`" + `ApplySetters` + "`" + ` function config. A resource is selected if all
+the non-empty criteria of any of the selectors match it: ` + "`" + `group` + "`" + `, ` + "`" + `version` + "`" + `, ` + "`" + `kind` + "`" + `,
+` + "`" + `name` + "`" + `, ` + "`" + `namespace` + "`" + `, ` + "`" + `labels` + "`" + ` and a glob pattern ` + "`" + `path` + "`" + ` of the file, e.g. ` + "`" + `app/**` + "`" + `.
+The ` + "`" + `fieldPath` + "`" + ` pattern of the matching selectors further restricts the fields,
+where ` + "`" + `*` + "`" + ` matches any single path element and ` + "`" + `**` + "`" + ` matches 0 or more elements,
+e.g. ` + "`" + `spec.**.containers[*].image` + "`" + `.
+
+  apiVersion: fn.kpt.dev/v1alpha1
+  kind: ApplySetters
+  metadata:
+    name: apply-setters-func-config
+  setters:
+    - name: namespace
+      value: prod
+  selectors:
+    - kind: Deployment
+      fieldPath: metadata.namespace
+    - path: app/**
+      labels:
+        tier: web
+
+The setter comments inside YAML or JSON documents embedded in string field
+values, e.g. the application config in the ` + "`" + `data` + "`" + ` of a ` + "`" + `ConfigMap` + "`" + `, are applied
+if the resource is annotated with ` + "`" + `kpt.dev/embedded-setters: "true"` + "`" + `. Only the
+tagged scalar values are replaced in the string, so the formatting and comments of
+the embedded document are kept. The field path of such a field is reported as the
+path of the string field followed by the path within the document, e.g.
+` + "`" + `data.app.yaml:logging.level` + "`" + `.
+
+  apiVersion: v1
+  kind: ConfigMap
+  metadata:
+    name: app-config
+    annotations:
+      kpt.dev/embedded-setters: "true"
+  data:
+    app.yaml: |
+      logging:
+        level: info # kpt-set: ${log-level}
+
 ` + "`" + `apply-setters` + "`" + ` function performs the following steps when invoked:
-1. Searches for the field values tagged by setter comments.
-2. Updates the field value fully or partially with the corresponding input setter values.
+1. Merges the setter values from the sources and validates them against the declared types and constraints.
+2. Searches for the field values tagged by setter comments.
+3. Updates the field value fully or partially with the corresponding input setter values.
+4. Reports the fields with unresolved setters if ` + "`" + `failOnUnresolved` + "`" + ` is set.
 `
 var ApplySettersExamples = `
 Setting scalar values:
@@ -147,4 +283,39 @@ Modified resource looks like the following:
   environments: # kpt-set: ${env}
     - prod
     - dev
+
+Setting map values:
+
+Similar to the array values, the map values must be wrapped into string, and
+the entire map value of the field tagged with the setter comment is replaced.
+
+  apiVersion: v1
+  kind: Pod
+  metadata:
+    name: foo
+  spec:
+    nodeSelector: # kpt-set: ${node-selector}
+      disktype: hdd
+
+Declare the desired map values, wrapped into string.
+
+  apiVersion: v1
+  kind: ConfigMap
+  metadata:
+    name: apply-setters-fn-config
+  data:
+    node-selector: |
+      disktype: ssd
+      zone: us-east1-b
+
+Modified resource looks like the following:
+
+  apiVersion: v1
+  kind: Pod
+  metadata:
+    name: foo
+  spec:
+    nodeSelector: # kpt-set: ${node-selector}
+      disktype: ssd
+      zone: us-east1-b
 `
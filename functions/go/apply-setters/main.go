@@ -3,6 +3,8 @@ package main
 import (
 	"fmt"
 	"os"
+	"sort"
+	"strings"
 
 	"github.com/GoogleContainerTools/kpt-functions-catalog/functions/go/apply-setters/applysetters"
 	"github.com/GoogleContainerTools/kpt-functions-catalog/functions/go/apply-setters/generated"
@@ -34,7 +36,7 @@ func (asp *ApplySettersProcessor) Process(resourceList *framework.ResourceList)
 	}
 	items, err := run(resourceList)
 	if err != nil {
-		resourceList.Result.Items = getErrorItem(err.Error())
+		resourceList.Result.Items = append(items, getErrorItem(err.Error())...)
 		return err
 	}
 	resourceList.Result.Items = items
@@ -48,7 +50,7 @@ func run(resourceList *framework.ResourceList) ([]framework.ResultItem, error) {
 	}
 	_, err = s.Filter(resourceList.Items)
 	if err != nil {
-		return nil, err
+		return errorsToItems(s), err
 	}
 	resultItems, err := resultsToItems(s)
 	if err != nil {
@@ -60,8 +62,8 @@ func run(resourceList *framework.ResourceList) ([]framework.ResultItem, error) {
 // getSetters retrieve the setters from input config
 func getSetters(fc *kyaml.RNode) (applysetters.ApplySetters, error) {
 	var fcd applysetters.ApplySetters
-	applysetters.Decode(fc, &fcd)
-	return fcd, nil
+	err := applysetters.Decode(fc, &fcd)
+	return fcd, err
 }
 
 // resultsToItems converts the Search and Replace results to
@@ -76,7 +78,7 @@ func resultsToItems(sr applysetters.ApplySetters) ([]framework.ResultItem, error
 	}
 	for _, res := range sr.Results {
 		items = append(items, framework.ResultItem{
-			Message: fmt.Sprintf("set field value to %q", res.Value),
+			Message: fmt.Sprintf("set field value to %q%s", res.Value, sourcesMessage(res.Sources)),
 			Field:   framework.Field{Path: res.FieldPath},
 			File:    framework.File{Path: res.FilePath},
 		})
@@ -84,6 +86,39 @@ func resultsToItems(sr applysetters.ApplySetters) ([]framework.ResultItem, error
 	return items, nil
 }
 
+// sourcesMessage describes the sources of the setter values used to set the field
+// e.g. " using tag from overlays/prod/setters.yaml"
+func sourcesMessage(sources map[string]string) string {
+	if len(sources) == 0 {
+		return ""
+	}
+	var names []string
+	for name := range sources {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	var parts []string
+	for _, name := range names {
+		parts = append(parts, fmt.Sprintf("%s from %s", name, sources[name]))
+	}
+	return fmt.Sprintf(" using %s", strings.Join(parts, ", "))
+}
+
+// errorsToItems converts the apply-setters errors to
+// equivalent items([]framework.Item)
+func errorsToItems(sr applysetters.ApplySetters) []framework.ResultItem {
+	var items []framework.ResultItem
+	for _, res := range sr.Errors {
+		items = append(items, framework.ResultItem{
+			Message:  res.Message,
+			Severity: framework.Error,
+			Field:    framework.Field{Path: res.FieldPath},
+			File:     framework.File{Path: res.FilePath},
+		})
+	}
+	return items
+}
+
 // getErrorItem returns the item for input error message
 func getErrorItem(errMsg string) []framework.ResultItem {
 	return []framework.ResultItem{
@@ -2,6 +2,7 @@ package createsetters
 
 import (
 	"fmt"
+	"reflect"
 	"sort"
 	"strings"
 
@@ -13,6 +14,14 @@ import (
 
 var _ kio.Filter = &CreateSetters{}
 
+const (
+	// FnConfigAPIVersion is the apiVersion of the typed functionConfig
+	FnConfigAPIVersion = "fn.kpt.dev/v1alpha1"
+
+	// FnConfigKind is the kind of the typed functionConfig
+	FnConfigKind = "CreateSetters"
+)
+
 // CreateSetters creates a comment for the resource fields which
 // contain the same value as setter value
 type CreateSetters struct {
@@ -26,11 +35,36 @@ type CreateSetters struct {
 	// ArraySetters holds the user provided values for array setters
 	ArraySetters []ArraySetter
 
+	// MapSetters holds the user provided values for map setters
+	MapSetters []MapSetter
+
 	// Results are the results of adding setter comments
 	Results []*Result
 
+	// Discover is true if the setters must be proposed for the values repeated
+	// across the resources instead of adding the setter comments
+	Discover bool
+
+	// MinCount is the minimum number of fields which must have the same value
+	// for the value to be proposed as a setter, defaults to DefaultMinCount
+	MinCount int
+
+	// Candidates are the setters proposed in the discovery mode
+	Candidates []*Candidate
+
+	// Selectors scope the setters to the matching resources and fields,
+	// the setters are created in all the resources if there are none
+	Selectors []Selector
+
+	// matchedSelectors are the Selectors matching the current resource
+	matchedSelectors []Selector
+
 	// filePath file path of resource
 	filePath string
+
+	// parseEmbedded is true if the setter comments must be added in the
+	// embedded documents of the current resource
+	parseEmbedded bool
 }
 
 // ScalarSetter stores name and value of the map setter
@@ -51,6 +85,15 @@ type ArraySetter struct {
 	Values []string
 }
 
+// MapSetter stores name and value of the map setter
+type MapSetter struct {
+	// Name is the name of the setter
+	Name string
+
+	// Value is the map value of the field to which setter comment is added.
+	Value map[string]interface{}
+}
+
 // Result holds result of create-setters operation
 type Result struct {
 	// FilePath is the file path of the matching value
@@ -84,6 +127,9 @@ func (a CompareSetters) Swap(i, j int) {
 
 // Filter implements CreatSetters as a yaml.Filter
 func (cs *CreateSetters) Filter(nodes []*yaml.RNode) ([]*yaml.RNode, error) {
+	if cs.Discover {
+		return cs.discover(nodes)
+	}
 	cs.preProcessScalarSetters()
 	for i := range nodes {
 		filePath, _, err := kioutil.GetFileAnnotations(nodes[i])
@@ -91,6 +137,14 @@ func (cs *CreateSetters) Filter(nodes []*yaml.RNode) ([]*yaml.RNode, error) {
 			return nodes, err
 		}
 		cs.filePath = filePath
+		selected, err := cs.selectResource(nodes[i])
+		if err != nil {
+			return nodes, err
+		}
+		if !selected {
+			continue
+		}
+		cs.parseEmbedded = nodes[i].GetAnnotations()[EmbeddedSettersAnnotation] == "true"
 		err = accept(cs, nodes[i])
 		if err != nil {
 			return nil, errors.Wrap(err)
@@ -118,6 +172,9 @@ func (cs *CreateSetters) preProcessScalarSetters() {
 
 /**
 visitMapping takes the mapping node and performs following steps,
+checks if it is a mapping node and if its value matches any of the MapSetters
+adds the linecomment if they are equal, see visitMapValue
+
 checks if it is a sequence node
 checks if all the values in the node match any of the ArraySetters
 adds the linecomment if they are equal
@@ -161,15 +218,22 @@ func (cs *CreateSetters) visitMapping(object *yaml.RNode, path string) error {
 			// don't do IsNilOrEmpty check as empty sequences are allowed
 			return nil
 		}
+		// add the key to the field path
+		fieldPath := strings.TrimPrefix(fmt.Sprintf("%s.%s", path, node.Key.YNode().Value), ".")
+		if !cs.fieldSelected(fieldPath) {
+			return nil
+		}
+
+		if node.Value.YNode().Kind == yaml.MappingNode {
+			return cs.visitMapValue(node, path)
+		}
+
 		// the aim of this method is to create-setter for sequence nodes
 		if node.Value.YNode().Kind != yaml.SequenceNode {
 			// return if it is not a sequence node
 			return nil
 		}
 
-		// add the key to the field path
-		fieldPath := strings.TrimPrefix(fmt.Sprintf("%s.%s", path, node.Key.YNode().Value), ".")
-
 		elements, err := node.Value.Elements()
 		if err != nil {
 			return errors.Wrap(err)
@@ -217,6 +281,66 @@ func (cs *CreateSetters) visitMapping(object *yaml.RNode, path string) error {
 	})
 }
 
+/**
+visitMapValue checks if the mapping value of the field is equal to
+the value of any of the MapSetters and adds the linecomment if it is
+
+e.g. for input of Mapping node
+
+nodeSelector:
+  disktype: ssd
+
+For input CreateSetters [Name: node-selector, Value: {disktype: ssd}], yaml node is transformed to
+
+nodeSelector: # kpt-set: ${node-selector}
+  disktype: ssd
+*/
+func (cs *CreateSetters) visitMapValue(node *yaml.MapNode, path string) error {
+	if len(cs.MapSetters) == 0 {
+		return nil
+	}
+	var nodeValue map[string]interface{}
+	if err := node.Value.YNode().Decode(&nodeValue); err != nil {
+		return errors.Wrap(err)
+	}
+	if nodeValue == nil {
+		nodeValue = map[string]interface{}{}
+	}
+	for _, mapSetter := range cs.MapSetters {
+		if !reflect.DeepEqual(nodeValue, mapSetter.Value) {
+			continue
+		}
+		// the comment is added to the key for the FoldedStyle value node
+		// and to the value for the FlowStyle value node e.g. labels: {} # kpt-set: ${labels}
+		nodeToAddComment := node.Key
+		if node.Value.YNode().Style == yaml.FlowStyle {
+			nodeToAddComment = node.Value
+		}
+		nodeToAddComment.YNode().LineComment = fmt.Sprintf("kpt-set: ${%s}", mapSetter.Name)
+		cs.Results = append(cs.Results, &Result{
+			FilePath:  cs.filePath,
+			FieldPath: strings.TrimPrefix(fmt.Sprintf("%s.%s", path, node.Key.YNode().Value), "."),
+			Value:     flowStyleString(node.Value.YNode()),
+			Comment:   nodeToAddComment.YNode().LineComment,
+		})
+		return nil
+	}
+	return nil
+}
+
+// flowStyleString returns the single line flow style representation of the
+// node, as list-setters renders the values of map setters e.g. {disktype: ssd}
+func flowStyleString(node *yaml.Node) string {
+	n := *node
+	n.Style = yaml.FlowStyle
+	n.LineComment = ""
+	s, err := yaml.NewRNode(&n).String()
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(s)
+}
+
 /**
 visitScalar accepts the input scalar node and performs following steps,
 checks if it is a scalar node
@@ -243,8 +367,16 @@ func (cs *CreateSetters) visitScalar(object *yaml.RNode, path string) error {
 		return nil
 	}
 
+	if !cs.fieldSelected(path) {
+		return nil
+	}
+
 	// doesn't add the comment to the nodes with multiple line values
+	// but to the embedded document in the value if the resource opts in
 	if hasMultipleLines(object.YNode().Value) {
+		if cs.parseEmbedded {
+			return cs.visitEmbedded(object, path)
+		}
 		return nil
 	}
 
@@ -341,7 +473,7 @@ func getLineComment(nodeValue string, replacer *strings.Replacer) (string, bool)
 
 /**
 Decode decodes the input yaml node into CreatSetters struct
-places the setter either in ScalarSetters or ArraySetters
+places the setter either in ScalarSetters, ArraySetters or MapSetters
 sorts the ScalarSetters using CompareSetters
 
 e.g.for input ScalarSetters
@@ -357,20 +489,35 @@ ScalarSetters array is transformed to
     [[name: ubuntu, value: nginx-abc], [name: image, value: nginx]]
 */
 func Decode(rn *yaml.RNode, fcd *CreateSetters) error {
-	if len(rn.GetDataMap()) == 0 {
+	setters, err := decodeConfig(rn, fcd)
+	if err != nil {
+		return err
+	}
+	if len(setters) == 0 && !fcd.Discover {
 		return fmt.Errorf("config map cannot be empty")
 	}
-	for k, v := range rn.GetDataMap() {
+	for k, v := range setters {
 		parsedInput, err := yaml.Parse(v)
 		if err != nil {
 			return fmt.Errorf("parsing error")
 		}
 		// checks if the value is SequenceNode
 		// adds to the ArraySetters if it is a SequenceNode
+		// adds to the MapSetters if it is a MappingNode
 		// adds to the ScalarSetters if it is a ScalarNode
-		if parsedInput.YNode().Kind == yaml.SequenceNode {
+		switch parsedInput.YNode().Kind {
+		case yaml.SequenceNode:
 			fcd.ArraySetters = append(fcd.ArraySetters, ArraySetter{Name: k, Values: getArraySetter(parsedInput)})
-		} else if parsedInput.YNode().Kind == yaml.ScalarNode {
+		case yaml.MappingNode:
+			var value map[string]interface{}
+			if err := parsedInput.YNode().Decode(&value); err != nil {
+				return fmt.Errorf("parsing error")
+			}
+			if value == nil {
+				value = map[string]interface{}{}
+			}
+			fcd.MapSetters = append(fcd.MapSetters, MapSetter{Name: k, Value: value})
+		case yaml.ScalarNode:
 			fcd.ScalarSetters = append(fcd.ScalarSetters, ScalarSetter{Name: k, Value: v})
 		}
 	}
@@ -380,3 +527,38 @@ func Decode(rn *yaml.RNode, fcd *CreateSetters) error {
 	sort.Sort(CompareSetters(fcd.ScalarSetters))
 	return nil
 }
+
+// fnConfig holds the fields of the typed CreateSetters functionConfig
+type fnConfig struct {
+	Setters []struct {
+		Name  string `yaml:"name"`
+		Value string `yaml:"value"`
+	} `yaml:"setters"`
+	Discover  bool       `yaml:"discover"`
+	MinCount  int        `yaml:"minCount"`
+	Selectors []Selector `yaml:"selectors"`
+}
+
+// decodeConfig returns the setter names to values from either a CreateSetters
+// resource or a ConfigMap, and sets the discovery options and the selectors of
+// the CreateSetters
+func decodeConfig(rn *yaml.RNode, fcd *CreateSetters) (map[string]string, error) {
+	if rn.GetKind() != FnConfigKind {
+		return rn.GetDataMap(), nil
+	}
+	var fc fnConfig
+	if err := yaml.Unmarshal([]byte(rn.MustString()), &fc); err != nil {
+		return nil, errors.WrapPrefixf(err, "failed to decode %s", FnConfigKind)
+	}
+	setters := make(map[string]string)
+	for _, s := range fc.Setters {
+		if s.Name == "" {
+			return nil, errors.Errorf("setter name must not be empty in %s", FnConfigKind)
+		}
+		setters[s.Name] = s.Value
+	}
+	fcd.Discover = fc.Discover
+	fcd.MinCount = fc.MinCount
+	fcd.Selectors = fc.Selectors
+	return setters, nil
+}
@@ -18,6 +18,7 @@ func TestCreateSettersFilter(t *testing.T) {
 		config            string
 		input             string
 		expectedResources string
+		expectedValues    map[string]string
 		errMsg            string
 	}{
 		{
@@ -307,6 +308,59 @@ metadata:
   name: [] # kpt-set: ${image}
 `,
 		},
+		{
+			name: "map setters",
+			config: `
+data:
+  resources: |
+    limits:
+      cpu: 500m
+      memory: 1Gi
+  node-selector: "{disktype: ssd}"
+`,
+			input: `apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: nginx-deployment
+spec:
+  template:
+    spec:
+      containers:
+        - name: nginx
+          resources:
+            limits:
+              memory: 1Gi
+              cpu: 500m
+        - name: sidecar
+          resources:
+            limits:
+              cpu: 100m
+      nodeSelector: {disktype: ssd}
+`,
+			expectedResources: `apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: nginx-deployment
+spec:
+  template:
+    spec:
+      containers:
+        - name: nginx
+          resources: # kpt-set: ${resources}
+            limits:
+              memory: 1Gi
+              cpu: 500m
+        - name: sidecar
+          resources:
+            limits:
+              cpu: 100m
+      nodeSelector: {disktype: ssd} # kpt-set: ${node-selector}
+`,
+			expectedValues: map[string]string{
+				"spec.template.spec.containers[0].resources": "{limits: {memory: 1Gi, cpu: 500m}}",
+				"spec.template.spec.nodeSelector":            "{disktype: ssd}",
+			},
+		},
 		{
 			name: "Empty data map",
 			config: `
@@ -518,6 +572,74 @@ metadata:
   name: nginx-development # kpt-set: nginx-${app}
 spec:
   image: dev # kpt-set: ${role}
+`,
+		},
+		{
+			name: "embedded documents",
+			config: `
+data:
+  image: nginx
+  tag: 1.7.1
+  level: debug
+`,
+			input: `apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: app-config
+  annotations:
+    kpt.dev/embedded-setters: "true"
+data:
+  app.yaml: |
+    # the image of the app
+    image:   "nginx:1.7.1"
+    logging: {level: debug}
+    levels: [debug, info]
+    level: debug # the log level
+  values.json: |
+    {
+      "image": "nginx"
+    }
+`,
+			expectedResources: `apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: app-config
+  annotations:
+    kpt.dev/embedded-setters: "true"
+data:
+  app.yaml: |
+    # the image of the app
+    image:   "nginx:1.7.1" # kpt-set: ${image}:${tag}
+    logging: {level: debug}
+    levels: [debug, info]
+    level: debug # the log level
+  values.json: |
+    {
+      "image": "nginx"
+    }
+`,
+		},
+		{
+			name: "embedded documents not opted in",
+			config: `
+data:
+  image: nginx
+`,
+			input: `apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: app-config
+data:
+  app.yaml: |
+    image: nginx
+`,
+			expectedResources: `apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: app-config
+data:
+  app.yaml: |
+    image: nginx
 `,
 		},
 	}
@@ -592,6 +714,14 @@ spec:
 				string(actualResources)) {
 				t.FailNow()
 			}
+
+			if test.expectedValues != nil {
+				values := make(map[string]string)
+				for _, result := range s.Results {
+					values[result.FieldPath] = result.Value
+				}
+				assert.Equal(t, test.expectedValues, values)
+			}
 		})
 	}
 }
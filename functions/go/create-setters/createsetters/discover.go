@@ -0,0 +1,258 @@
+package createsetters
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"sigs.k8s.io/kustomize/kyaml/errors"
+	"sigs.k8s.io/kustomize/kyaml/kio/filters"
+	"sigs.k8s.io/kustomize/kyaml/kio/kioutil"
+	"sigs.k8s.io/kustomize/kyaml/yaml"
+)
+
+const (
+	// DefaultMinCount is the default minimum number of fields which must
+	// have the same value for the value to be proposed as a setter
+	DefaultMinCount = 2
+
+	// SettersFile is the path of the ConfigMap with the proposed setters
+	SettersFile = "setters.yaml"
+
+	// SettersConfigMapName is the name of the ConfigMap with the proposed setters
+	SettersConfigMapName = "setters"
+)
+
+// ignoredKeys are the fields whose values are not proposed as setters as they
+// are either part of the resource type or take a small set of well known values
+var ignoredKeys = map[string]bool{
+	"apiVersion":      true,
+	"kind":            true,
+	"protocol":        true,
+	"imagePullPolicy": true,
+	"restartPolicy":   true,
+}
+
+// Candidate is a setter proposed for a value which is repeated across the fields
+// of the resources
+type Candidate struct {
+	// Name is the proposed name of the setter
+	Name string
+
+	// Value is the value of the setter
+	Value string
+
+	// Fields are the fields which have the value
+	Fields []*Result
+}
+
+// occurrence is a field value which can be parameterized by a setter
+type occurrence struct {
+	// key is the name of the field, used to propose the setter name
+	key string
+
+	// result holds the file path, field path and value of the field
+	result *Result
+}
+
+// discover finds the values which are repeated in at least minCount fields
+// of the resources and proposes setters for them. The resources are not
+// modified, instead the proposed setters are returned in a ConfigMap which
+// can be used as the functionConfig of create-setters and apply-setters
+func (cs *CreateSetters) discover(nodes []*yaml.RNode) ([]*yaml.RNode, error) {
+	minCount := cs.MinCount
+	if minCount <= 0 {
+		minCount = DefaultMinCount
+	}
+
+	occurrences := make(map[string][]*occurrence)
+	var values []string
+	for i := range nodes {
+		if nodes[i].GetKind() == "Kptfile" || nodes[i].GetAnnotations()[filters.LocalConfigAnnotation] != "" {
+			// the values in package metadata and local config are not parameterized
+			continue
+		}
+		filePath, _, err := kioutil.GetFileAnnotations(nodes[i])
+		if err != nil {
+			return nodes, err
+		}
+		cs.filePath = filePath
+		selected, err := cs.selectResource(nodes[i])
+		if err != nil {
+			return nodes, err
+		}
+		if !selected {
+			continue
+		}
+		collectValues(nodes[i].YNode(), "", "", func(key, path, value string) {
+			if !cs.fieldSelected(path) {
+				return
+			}
+			if _, ok := occurrences[value]; !ok {
+				values = append(values, value)
+			}
+			occurrences[value] = append(occurrences[value], &occurrence{
+				key:    key,
+				result: &Result{FilePath: filePath, FieldPath: strings.TrimPrefix(path, "."), Value: value},
+			})
+		})
+	}
+
+	var candidates []*Candidate
+	keys := make(map[*Candidate]string)
+	for _, value := range values {
+		if len(occurrences[value]) < minCount {
+			continue
+		}
+		c := &Candidate{Value: value}
+		for _, o := range occurrences[value] {
+			c.Fields = append(c.Fields, o.result)
+		}
+		keys[c] = mostCommonKey(occurrences[value])
+		candidates = append(candidates, c)
+	}
+	// the values repeated in more fields are more likely to be setters,
+	// and get the names without suffixes
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return len(candidates[i].Fields) > len(candidates[j].Fields)
+	})
+	names := make(map[string]bool)
+	for _, c := range candidates {
+		c.Name = uniqueName(setterNameFromKey(keys[c]), names)
+	}
+	cs.Candidates = candidates
+	if len(candidates) == 0 {
+		return nodes, nil
+	}
+	return addSettersConfigMap(nodes, candidates)
+}
+
+// collectValues calls fn for each string value in the node which can be
+// parameterized by a setter, the fields which already have a setter comment
+// and the multi-line values are skipped. The repository and the tag of the
+// image fields are collected as separate values.
+func collectValues(node *yaml.Node, key, path string, fn func(key, path, value string)) {
+	switch node.Kind {
+	case yaml.MappingNode:
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			k, v := node.Content[i], node.Content[i+1]
+			if ignoredKeys[k.Value] || strings.Contains(k.LineComment, "kpt-set:") ||
+				strings.Contains(v.LineComment, "kpt-set:") || isInternalAnnotation(path, k.Value) {
+				continue
+			}
+			collectValues(v, k.Value, path+"."+k.Value, fn)
+		}
+	case yaml.SequenceNode:
+		for i, c := range node.Content {
+			collectValues(c, key, path+fmt.Sprintf("[%d]", i), fn)
+		}
+	case yaml.ScalarNode:
+		v := node.Value
+		if node.ShortTag() != yaml.NodeTagString || len(v) < 2 || hasMultipleLines(v) {
+			return
+		}
+		if key == "image" {
+			if repo, tag := splitImage(v); tag != "" {
+				fn("image", path, repo)
+				fn("tag", path, tag)
+				return
+			}
+		}
+		fn(key, path, v)
+	}
+}
+
+// isInternalAnnotation returns true if the key is an annotation recorded by
+// the kpt and kustomize tools e.g. config.kubernetes.io/path
+func isInternalAnnotation(path, key string) bool {
+	return path == ".metadata.annotations" &&
+		(strings.Contains(key, "config.kubernetes.io/") || strings.Contains(key, "config.k8s.io/"))
+}
+
+// splitImage splits the image into the repository and the tag,
+// the tag is empty for the images without tag or with digest
+func splitImage(image string) (string, string) {
+	if strings.Contains(image, "@") {
+		return image, ""
+	}
+	i := strings.LastIndex(image, ":")
+	if i < 0 || strings.Contains(image[i+1:], "/") {
+		// the colon separates the registry port
+		return image, ""
+	}
+	return image[:i], image[i+1:]
+}
+
+// mostCommonKey returns the field name which is used for most of the
+// occurrences of the value, the first one wins in case of a tie
+func mostCommonKey(occurrences []*occurrence) string {
+	counts := make(map[string]int)
+	var key string
+	for _, o := range occurrences {
+		counts[o.key]++
+		if counts[o.key] > counts[key] {
+			key = o.key
+		}
+	}
+	return key
+}
+
+var (
+	camelCaseBoundary = regexp.MustCompile(`([a-z0-9])([A-Z])`)
+	invalidNameChars  = regexp.MustCompile(`[^a-z0-9]+`)
+)
+
+// setterNameFromKey derives the setter name from the field name
+// e.g. cnrm.cloud.google.com/project-id returns project-id and
+// projectID returns project-id
+func setterNameFromKey(key string) string {
+	key = key[strings.LastIndex(key, "/")+1:]
+	key = camelCaseBoundary.ReplaceAllString(key, "$1-$2")
+	key = strings.Trim(invalidNameChars.ReplaceAllString(strings.ToLower(key), "-"), "-")
+	if key == "" {
+		return "value"
+	}
+	return key
+}
+
+// uniqueName returns the name with a numeric suffix if it is already used
+func uniqueName(name string, used map[string]bool) string {
+	unique := name
+	for i := 2; used[unique]; i++ {
+		unique = fmt.Sprintf("%s-%d", name, i)
+	}
+	used[unique] = true
+	return unique
+}
+
+// addSettersConfigMap adds the proposed setters to the local config ConfigMap
+// in SettersFile, the data of the ConfigMap is replaced if it already exists
+func addSettersConfigMap(nodes []*yaml.RNode, candidates []*Candidate) ([]*yaml.RNode, error) {
+	data := make(map[string]string)
+	for _, c := range candidates {
+		data[c.Name] = c.Value
+	}
+	for _, node := range nodes {
+		if node.GetKind() == "ConfigMap" && node.GetName() == SettersConfigMapName &&
+			node.GetAnnotations()[kioutil.PathAnnotation] == SettersFile {
+			node.SetDataMap(data)
+			return nodes, nil
+		}
+	}
+	cm := yaml.MustParse(`
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: setters
+data: {}
+`)
+	if err := cm.SetAnnotations(map[string]string{
+		filters.LocalConfigAnnotation: "true",
+		kioutil.PathAnnotation:        SettersFile,
+	}); err != nil {
+		return nodes, errors.Wrap(err)
+	}
+	cm.SetDataMap(data)
+	return append(nodes, cm), nil
+}
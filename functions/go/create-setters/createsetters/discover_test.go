@@ -0,0 +1,192 @@
+package createsetters
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"sigs.k8s.io/kustomize/kyaml/kio"
+)
+
+func TestDiscover(t *testing.T) {
+	var tests = []struct {
+		name               string
+		minCount           int
+		input              string
+		expectedCandidates []Candidate
+		expectedResources  string
+	}{
+		{
+			name: "repeated values",
+			input: `apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: nginx-deployment
+  namespace: prod
+  annotations:
+    config.kubernetes.io/path: deployment.yaml
+spec:
+  replicas: 3
+  template:
+    spec:
+      containers:
+        - name: nginx
+          image: gcr.io/nginx:1.16.1
+          imagePullPolicy: Always
+        - name: sidecar
+          image: gcr.io/sidecar:1.16.1
+          imagePullPolicy: Always
+---
+apiVersion: v1
+kind: Service
+metadata:
+  name: nginx-service
+  namespace: prod
+  annotations:
+    config.kubernetes.io/path: service.yaml
+    cnrm.cloud.google.com/project-id: my-project
+spec:
+  type: ClusterIP
+---
+apiVersion: storage.cnrm.cloud.google.com/v1beta1
+kind: StorageBucket
+metadata:
+  name: my-project-bucket
+  namespace: prod # kpt-set: ${namespace}
+  annotations:
+    config.kubernetes.io/path: bucket.yaml
+    cnrm.cloud.google.com/project-id: my-project
+`,
+			expectedCandidates: []Candidate{
+				{Name: "namespace", Value: "prod", Fields: []*Result{
+					{FilePath: "deployment.yaml", FieldPath: "metadata.namespace", Value: "prod"},
+					{FilePath: "service.yaml", FieldPath: "metadata.namespace", Value: "prod"},
+				}},
+				{Name: "tag", Value: "1.16.1", Fields: []*Result{
+					{FilePath: "deployment.yaml", FieldPath: "spec.template.spec.containers[0].image", Value: "1.16.1"},
+					{FilePath: "deployment.yaml", FieldPath: "spec.template.spec.containers[1].image", Value: "1.16.1"},
+				}},
+				{Name: "project-id", Value: "my-project", Fields: []*Result{
+					{FilePath: "service.yaml", FieldPath: "metadata.annotations.cnrm.cloud.google.com/project-id", Value: "my-project"},
+					{FilePath: "bucket.yaml", FieldPath: "metadata.annotations.cnrm.cloud.google.com/project-id", Value: "my-project"},
+				}},
+			},
+			expectedResources: `apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: setters
+  annotations:
+    config.kubernetes.io/local-config: "true"
+    config.kubernetes.io/path: setters.yaml
+data:
+  namespace: prod
+  project-id: my-project
+  tag: 1.16.1
+`,
+		},
+		{
+			name:     "min count",
+			minCount: 3,
+			input: `apiVersion: v1
+kind: Service
+metadata:
+  name: nginx-service
+  namespace: prod
+  annotations:
+    config.kubernetes.io/path: service.yaml
+---
+apiVersion: v1
+kind: ServiceAccount
+metadata:
+  name: nginx-sa
+  namespace: prod
+  annotations:
+    config.kubernetes.io/path: sa.yaml
+`,
+		},
+		{
+			name: "name conflicts",
+			input: `apiVersion: v1
+kind: Service
+metadata:
+  name: nginx
+  labels:
+    app: nginx
+    tier: web
+  annotations:
+    config.kubernetes.io/path: service.yaml
+spec:
+  clusterName: web
+`,
+			expectedCandidates: []Candidate{
+				{Name: "name", Value: "nginx", Fields: []*Result{
+					{FilePath: "service.yaml", FieldPath: "metadata.name", Value: "nginx"},
+					{FilePath: "service.yaml", FieldPath: "metadata.labels.app", Value: "nginx"},
+				}},
+				{Name: "tier", Value: "web", Fields: []*Result{
+					{FilePath: "service.yaml", FieldPath: "metadata.labels.tier", Value: "web"},
+					{FilePath: "service.yaml", FieldPath: "spec.clusterName", Value: "web"},
+				}},
+			},
+			expectedResources: `apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: setters
+  annotations:
+    config.kubernetes.io/local-config: "true"
+    config.kubernetes.io/path: setters.yaml
+data:
+  name: nginx
+  tier: web
+`,
+		},
+	}
+	for i := range tests {
+		test := tests[i]
+		t.Run(test.name, func(t *testing.T) {
+			nodes, err := (&kio.ByteReader{
+				Reader:                strings.NewReader(test.input),
+				OmitReaderAnnotations: true,
+			}).Read()
+			if !assert.NoError(t, err) {
+				t.FailNow()
+			}
+			input := len(nodes)
+			cs := &CreateSetters{Discover: true, MinCount: test.minCount}
+			out, err := cs.Filter(nodes)
+			if !assert.NoError(t, err) {
+				t.FailNow()
+			}
+			var candidates []Candidate
+			for _, c := range cs.Candidates {
+				candidates = append(candidates, *c)
+			}
+			assert.Equal(t, test.expectedCandidates, candidates)
+
+			// only the ConfigMap with proposed setters is added
+			var actual bytes.Buffer
+			err = kio.ByteWriter{Writer: &actual}.Write(out[input:])
+			if !assert.NoError(t, err) {
+				t.FailNow()
+			}
+			assert.Equal(t, test.expectedResources, actual.String())
+		})
+	}
+}
+
+func TestSetterNameFromKey(t *testing.T) {
+	var tests = []struct {
+		key      string
+		expected string
+	}{
+		{key: "namespace", expected: "namespace"},
+		{key: "cnrm.cloud.google.com/project-id", expected: "project-id"},
+		{key: "projectID", expected: "project-id"},
+		{key: "service_account", expected: "service-account"},
+		{key: "", expected: "value"},
+	}
+	for _, test := range tests {
+		assert.Equal(t, test.expected, setterNameFromKey(test.key))
+	}
+}
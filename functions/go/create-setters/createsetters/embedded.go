@@ -0,0 +1,97 @@
+package createsetters
+
+import (
+	"fmt"
+	"strings"
+
+	"sigs.k8s.io/kustomize/kyaml/yaml"
+)
+
+// EmbeddedSettersAnnotation opts in a resource to add the setter comments inside
+// the YAML documents embedded in its string field values
+const EmbeddedSettersAnnotation = "kpt.dev/embedded-setters"
+
+// embeddedScalar is a scalar in an embedded document along with its field path
+// in the document
+type embeddedScalar struct {
+	node *yaml.Node
+	path string
+}
+
+// visitEmbedded parses the string value of the scalar node as a YAML document,
+// and adds the setter comments to the end of the lines of the scalars in the
+// document which match the setter values, so that the formatting of the document
+// is kept. Only the scalars of block style documents which are the only values on
+// their lines and don't have a line comment are parameterized.
+//
+// e.g. for input CreateSetters [[name: image, value: nginx], [name: tag, value: 1.7.1]]
+// the embedded document
+//
+//	app.yaml: |
+//	  image: nginx:1.7.1
+//
+// is transformed to
+//
+//	app.yaml: |
+//	  image: nginx:1.7.1 # kpt-set: ${image}:${tag}
+func (cs *CreateSetters) visitEmbedded(object *yaml.RNode, path string) error {
+	value := object.YNode().Value
+	doc, err := yaml.Parse(value)
+	if err != nil || (doc.YNode().Kind != yaml.MappingNode && doc.YNode().Kind != yaml.SequenceNode) {
+		// not an embedded document
+		return nil
+	}
+	var scalars []embeddedScalar
+	blockScalars(doc.YNode(), "", &scalars)
+
+	// the number of scalars on each line of the document
+	perLine := make(map[int]int)
+	for _, s := range scalars {
+		perLine[s.node.Line]++
+	}
+
+	lines := strings.Split(value, "\n")
+	for _, s := range scalars {
+		if perLine[s.node.Line] > 1 || s.node.Line > len(lines) || s.node.LineComment != "" ||
+			s.node.Style&(yaml.LiteralStyle|yaml.FoldedStyle) != 0 || hasMultipleLines(s.node.Value) {
+			continue
+		}
+		linecomment, valueMatch := getLineComment(s.node.Value, cs.replacer)
+		if !valueMatch {
+			continue
+		}
+		comment := fmt.Sprintf("kpt-set: %s", linecomment)
+		lines[s.node.Line-1] = fmt.Sprintf("%s # %s", strings.TrimRight(lines[s.node.Line-1], " \t"), comment)
+		cs.Results = append(cs.Results, &Result{
+			FilePath:  cs.filePath,
+			FieldPath: fmt.Sprintf("%s:%s", strings.TrimPrefix(path, "."), strings.TrimPrefix(s.path, ".")),
+			Value:     s.node.Value,
+			Comment:   comment,
+		})
+	}
+	object.YNode().Value = strings.Join(lines, "\n")
+	return nil
+}
+
+// blockScalars appends the scalars of the block style collections in the node
+// to res, the scalars within flow style collections are skipped
+func blockScalars(node *yaml.Node, path string, res *[]embeddedScalar) {
+	switch node.Kind {
+	case yaml.MappingNode:
+		if node.Style&yaml.FlowStyle != 0 {
+			return
+		}
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			blockScalars(node.Content[i+1], path+"."+node.Content[i].Value, res)
+		}
+	case yaml.SequenceNode:
+		if node.Style&yaml.FlowStyle != 0 {
+			return
+		}
+		for i, c := range node.Content {
+			blockScalars(c, path+fmt.Sprintf("[%d]", i), res)
+		}
+	case yaml.ScalarNode:
+		*res = append(*res, embeddedScalar{node: node, path: path})
+	}
+}
@@ -0,0 +1,34 @@
+package createsetters
+
+import (
+	"github.com/GoogleContainerTools/kpt-functions-catalog/thirdparty/kyaml/selector"
+	"sigs.k8s.io/kustomize/kyaml/yaml"
+)
+
+// Selector selects the resources and the fields within them in which the
+// setters are created. All the non-empty criteria must match.
+type Selector = selector.Selector
+
+// selectResource sets the selectors matching the resource to be used for the
+// fields of the resource and returns true if the resource is selected
+func (cs *CreateSetters) selectResource(node *yaml.RNode) (bool, error) {
+	cs.matchedSelectors = nil
+	if len(cs.Selectors) == 0 {
+		return true, nil
+	}
+	matched, err := selector.MatchingResource(cs.Selectors, node, cs.filePath)
+	if err != nil {
+		return false, err
+	}
+	cs.matchedSelectors = matched
+	return len(cs.matchedSelectors) > 0, nil
+}
+
+// fieldSelected returns true if the setters must be created for the field
+// in the input path of the current resource
+func (cs *CreateSetters) fieldSelected(path string) bool {
+	if len(cs.Selectors) == 0 {
+		return true
+	}
+	return selector.AnyMatchesField(cs.matchedSelectors, path)
+}
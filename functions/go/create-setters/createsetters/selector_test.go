@@ -0,0 +1,163 @@
+package createsetters
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"sigs.k8s.io/kustomize/kyaml/kio"
+	kyaml "sigs.k8s.io/kustomize/kyaml/yaml"
+)
+
+func TestCreateSettersSelectors(t *testing.T) {
+	input := `apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: nginx
+  namespace: default
+  annotations:
+    config.kubernetes.io/path: app/deployment.yaml
+spec:
+  template:
+    spec:
+      serviceAccountName: default
+      containers:
+        - name: nginx
+          image: nginx:1.7.1
+---
+apiVersion: v1
+kind: Service
+metadata:
+  name: nginx
+  namespace: default
+  annotations:
+    config.kubernetes.io/path: app/service.yaml
+`
+	var tests = []struct {
+		name              string
+		config            string
+		expectedResources string
+		errMsg            string
+	}{
+		{
+			name: "field path pattern",
+			config: `apiVersion: fn.kpt.dev/v1alpha1
+kind: CreateSetters
+metadata:
+  name: create-setters-fn-config
+setters:
+  - name: namespace
+    value: default
+selectors:
+  - fieldPath: metadata.namespace
+`,
+			expectedResources: `apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: nginx
+  namespace: default # kpt-set: ${namespace}
+  annotations:
+    config.kubernetes.io/path: app/deployment.yaml
+spec:
+  template:
+    spec:
+      serviceAccountName: default
+      containers:
+        - name: nginx
+          image: nginx:1.7.1
+---
+apiVersion: v1
+kind: Service
+metadata:
+  name: nginx
+  namespace: default # kpt-set: ${namespace}
+  annotations:
+    config.kubernetes.io/path: app/service.yaml
+`,
+		},
+		{
+			name: "resource selectors",
+			config: `apiVersion: fn.kpt.dev/v1alpha1
+kind: CreateSetters
+metadata:
+  name: create-setters-fn-config
+setters:
+  - name: app
+    value: nginx
+selectors:
+  - kind: Service
+  - group: apps
+    path: app/*.yaml
+    fieldPath: spec.**.image
+`,
+			expectedResources: `apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: nginx
+  namespace: default
+  annotations:
+    config.kubernetes.io/path: app/deployment.yaml
+spec:
+  template:
+    spec:
+      serviceAccountName: default
+      containers:
+        - name: nginx
+          image: nginx:1.7.1 # kpt-set: ${app}:1.7.1
+---
+apiVersion: v1
+kind: Service
+metadata:
+  name: nginx # kpt-set: ${app}
+  namespace: default
+  annotations:
+    config.kubernetes.io/path: app/service.yaml
+`,
+		},
+		{
+			name: "empty setter name",
+			config: `apiVersion: fn.kpt.dev/v1alpha1
+kind: CreateSetters
+metadata:
+  name: create-setters-fn-config
+setters:
+  - value: nginx
+`,
+			errMsg: "setter name must not be empty in CreateSetters",
+		},
+	}
+	for i := range tests {
+		test := tests[i]
+		t.Run(test.name, func(t *testing.T) {
+			cs := &CreateSetters{}
+			err := Decode(kyaml.MustParse(test.config), cs)
+			if test.errMsg != "" {
+				if !assert.Error(t, err) {
+					t.FailNow()
+				}
+				assert.Contains(t, err.Error(), test.errMsg)
+				return
+			}
+			if !assert.NoError(t, err) {
+				t.FailNow()
+			}
+			nodes, err := (&kio.ByteReader{
+				Reader:                strings.NewReader(input),
+				OmitReaderAnnotations: true,
+			}).Read()
+			if !assert.NoError(t, err) {
+				t.FailNow()
+			}
+			_, err = cs.Filter(nodes)
+			if !assert.NoError(t, err) {
+				t.FailNow()
+			}
+			var out bytes.Buffer
+			if !assert.NoError(t, kio.ByteWriter{Writer: &out}.Write(nodes)) {
+				t.FailNow()
+			}
+			assert.Equal(t, test.expectedResources, out.String())
+		})
+	}
+}
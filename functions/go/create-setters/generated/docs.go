@@ -17,16 +17,84 @@ Here, the key is the name of the setter, and value is the field value to be para
     setter_name1: setter_value1
     setter_name2: setter_value2
 
+Alternatively, the setters can be declared using the ` + "`" + `CreateSetters` + "`" + ` custom resource.
+
+  apiVersion: fn.kpt.dev/v1alpha1
+  kind: CreateSetters
+  metadata:
+    name: create-setters-fn-config
+  setters:
+    - name: setter_name1
+      value: setter_value1
+
+The setter comments can be scoped to a subset of the resources and fields using
+` + "`" + `selectors` + "`" + ` in the ` + "`" + `CreateSetters` + "`" + ` function config. A resource is selected if all
+the non-empty criteria of any of the selectors match it: ` + "`" + `group` + "`" + `, ` + "`" + `version` + "`" + `, ` + "`" + `kind` + "`" + `,
+` + "`" + `name` + "`" + `, ` + "`" + `namespace` + "`" + `, ` + "`" + `labels` + "`" + ` and a glob pattern ` + "`" + `path` + "`" + ` of the file, e.g. ` + "`" + `app/**` + "`" + `.
+The ` + "`" + `fieldPath` + "`" + ` pattern of the matching selectors further restricts the fields,
+where ` + "`" + `*` + "`" + ` matches any single path element and ` + "`" + `**` + "`" + ` matches 0 or more elements,
+e.g. ` + "`" + `spec.**.containers[*].image` + "`" + `.
+
+  apiVersion: fn.kpt.dev/v1alpha1
+  kind: CreateSetters
+  metadata:
+    name: create-setters-func-config
+  setters:
+    - name: namespace
+      value: default
+  selectors:
+    - kind: Deployment
+      fieldPath: metadata.namespace
+    - path: app/**
+      labels:
+        tier: web
+
+This avoids tagging unrelated fields which happen to have the same value as a setter,
+e.g. ` + "`" + `serviceAccountName: default` + "`" + ` for the ` + "`" + `namespace` + "`" + ` setter above. The selectors
+also scope the values considered in the discovery mode described below.
+
 ` + "`" + `create-setters` + "`" + ` function performs the following steps:
-1. Segregates the input setters into scalar-setters and array-setters.
+1. Segregates the input setters into scalar-setters, array-setters and map-setters.
 2. Searches for the resource field values to be parameterized.
 3. Checks if there is any match considering the following cases.,
    - For a scalar node, performs substring match with scalar setters.
    - For an array node, checks if all values match with any of the array setters.
+   - For a map node, checks if the entire map is equal to any of the map setters.
 4. Adds comments to the fields matching the setter values using setter names as parameters.
 
+The scalar values inside YAML documents embedded in string field values, e.g. the
+application config in the ` + "`" + `data` + "`" + ` of a ` + "`" + `ConfigMap` + "`" + `, are parameterized if the resource
+is annotated with ` + "`" + `kpt.dev/embedded-setters: "true"` + "`" + `. The setter comments are added
+to the end of the lines of the matching values, so the formatting of the embedded
+document is kept. The values within flow style collections, including JSON documents,
+and the values which already have a comment are not parameterized.
+
 >? If this function adds setter comments to the fields for which you didn't intend to parameterize,
 you can simply review and delete/modify those comments manually.
+
+Discovering setters:
+
+If the setters are not known upfront, e.g. when a package is created from existing
+resources, ` + "`" + `discover: true` + "`" + ` makes the function propose setters for the values which
+are repeated across the fields of the resources, instead of adding setter comments.
+A value is proposed if it is used by at least ` + "`" + `minCount` + "`" + ` fields, which defaults to 2.
+
+  apiVersion: fn.kpt.dev/v1alpha1
+  kind: CreateSetters
+  metadata:
+    name: create-setters-fn-config
+  discover: true
+  minCount: 3
+
+The name of each proposed setter is derived from the field name, e.g. ` + "`" + `namespace` + "`" + `
+or ` + "`" + `project-id` + "`" + ` for the ` + "`" + `cnrm.cloud.google.com/project-id` + "`" + ` annotation, and the
+repository and the tag of the ` + "`" + `image` + "`" + ` fields are proposed as separate setters. The
+proposed setters are reported as info results, and added to the ` + "`" + `ConfigMap` + "`" + ` named
+` + "`" + `setters` + "`" + ` in ` + "`" + `setters.yaml` + "`" + ` with the ` + "`" + `config.kubernetes.io/local-config` + "`" + ` annotation.
+None of the other resources are modified. After reviewing the proposed names and
+values, ` + "`" + `setters.yaml` + "`" + ` can be used as the function config to create the setters.
+
+  $ kpt fn eval --image gcr.io/kpt-fn/create-setters:unstable --fn-config ./setters.yaml
 `
 var CreateSettersExamples = `
 ### Setting comments for scalar nodes
@@ -155,4 +223,36 @@ Modified resource looks like the following:
 Explanation for the changes:
 - As all the array values of ` + "`" + `environments` + "`" + ` field match the setter values of ` + "`" + `env` + "`" + `, ` + "`" + `# kpt-set: ${env}` + "`" + ` comment is added.
 Here, the comment is added to the ` + "`" + `environments` + "`" + ` field as it is an array node, and the intent is to paremeterize entire array.
+
+### Setting comments for map nodes
+
+Fields with map values can be parameterized in the same way, the map value
+must be wrapped into string.
+
+  # create-setters-fn-config.yaml
+  apiVersion: v1
+  kind: ConfigMap
+  metadata:
+    name: create-setters-fn-config
+  data:
+    resources: |
+      limits:
+        cpu: 500m
+        memory: 1Gi
+
+Fields whose entire map value is equal to the setter value, irrespective of the
+order of the keys, are tagged with the setter comment.
+
+  # resources.yaml
+  apiVersion: v1
+  kind: Pod
+  metadata:
+    name: foo
+  spec:
+    containers:
+      - name: nginx
+        resources: # kpt-set: ${resources}
+          limits:
+            memory: 1Gi
+            cpu: 500m
 `
@@ -46,10 +46,13 @@ func run(resourceList *framework.ResourceList) ([]framework.ResultItem, error) {
 	if err != nil {
 		return nil, err
 	}
-	_, err = s.Filter(resourceList.Items)
+	resourceList.Items, err = s.Filter(resourceList.Items)
 	if err != nil {
 		return nil, err
 	}
+	if s.Discover {
+		return candidatesToItems(s), nil
+	}
 	resultItems, err := resultsToItems(s)
 	if err != nil {
 		return nil, err
@@ -81,6 +84,30 @@ func resultsToItems(sr createsetters.CreateSetters) ([]framework.ResultItem, err
 	return items, nil
 }
 
+// candidatesToItems converts the setters proposed in the discovery mode to
+// equivalent info items([]framework.Item)
+func candidatesToItems(sr createsetters.CreateSetters) []framework.ResultItem {
+	if len(sr.Candidates) == 0 {
+		return []framework.ResultItem{
+			{
+				Message:  "no repeated values found to propose setters",
+				Severity: framework.Info,
+			},
+		}
+	}
+	var items []framework.ResultItem
+	for _, c := range sr.Candidates {
+		items = append(items, framework.ResultItem{
+			Message: fmt.Sprintf("Proposed setter %q for value %q used by %d fields, added to %s",
+				c.Name, c.Value, len(c.Fields), createsetters.SettersFile),
+			Severity: framework.Info,
+			Field:    framework.Field{Path: c.Fields[0].FieldPath},
+			File:     framework.File{Path: c.Fields[0].FilePath},
+		})
+	}
+	return items
+}
+
 // getErrorItem returns the item for input error message
 func getErrorItem(errMsg string) []framework.ResultItem {
 	return []framework.ResultItem{
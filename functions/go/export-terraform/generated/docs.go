@@ -16,4 +16,8 @@ The function executes as follows:
 ` + "`" + `export-terraform` + "`" + ` function can be executed imperatively as follows:
 
   $ kpt fn eval -i gcr.io/kpt-fn/export-terraform:unstable
+
+To fail when any resource or reference is dropped:
+
+  $ kpt fn eval -i gcr.io/kpt-fn/export-terraform:unstable -- strict=true
 `
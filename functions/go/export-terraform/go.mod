@@ -7,6 +7,7 @@ require (
 	github.com/stretchr/testify v1.7.0
 	k8s.io/api v0.23.1
 	k8s.io/apimachinery v0.23.1
+	sigs.k8s.io/kustomize/kyaml v0.13.1
 )
 
 require (
@@ -38,6 +39,5 @@ require (
 	k8s.io/kube-openapi v0.0.0-20211115234752-e816edb12b65 // indirect
 	k8s.io/utils v0.0.0-20210930125809-cb0fa318a74b // indirect
 	sigs.k8s.io/json v0.0.0-20211020170558-c049b76a60c6 // indirect
-	sigs.k8s.io/kustomize/kyaml v0.13.1 // indirect
 	sigs.k8s.io/structured-merge-diff/v4 v4.1.2 // indirect
 )
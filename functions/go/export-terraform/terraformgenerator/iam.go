@@ -60,7 +60,7 @@ func (resource *terraformResource) GetIAMBindings() map[string]*iamBinding {
 		switch child.Kind {
 		case "IAMPolicyMember":
 			role := child.GetStringFromObject("spec", "role")
-			member := child.GetStringFromObject("spec", "member")
+			member := child.GetStringValue("spec", "member")
 			policy.addBinding(role, member)
 		case "IAMPartialPolicy", "IAMPolicy":
 			var bindings []iamBinding
@@ -71,7 +71,7 @@ func (resource *terraformResource) GetIAMBindings() map[string]*iamBinding {
 			}
 			for _, binding := range bindings {
 				for _, member := range binding.Members {
-					policy.addBinding(binding.Role, member.Member)
+					policy.addBinding(binding.Role, hclString(member.Member))
 				}
 			}
 		}
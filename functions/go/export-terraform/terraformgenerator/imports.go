@@ -0,0 +1,170 @@
+// Copyright 2022 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package terraformgenerator
+
+import (
+	"fmt"
+	"sort"
+)
+
+type resourceImport struct {
+	Kind    string // The Kubernetes Kind of the resource
+	Name    string // The name of the resource (from metadata.name)
+	Address string // The address of the resource in the Terraform configuration
+	ID      string // The ID to import the resource with, if it can be imported
+}
+
+// GetImports returns the Terraform addresses of the exported resources and
+// the IDs to import the existing resources with
+func (rs *terraformResources) GetImports() []*resourceImport {
+	var imports []*resourceImport
+	for _, resources := range rs.getGrouped() {
+		for _, resource := range resources {
+			if !resource.ShouldCreate() || resource.dropReason() != "" {
+				continue
+			}
+			address, id := resource.getImport()
+			if address == "" {
+				continue
+			}
+			imports = append(imports, &resourceImport{
+				Kind:    resource.Kind,
+				Name:    resource.Name,
+				Address: address,
+				ID:      id,
+			})
+		}
+	}
+	sort.Slice(imports, func(i, j int) bool {
+		return imports[i].Address < imports[j].Address
+	})
+	return imports
+}
+
+// getImport returns the Terraform address the templates render the resource
+// at, and the ID to import it with. The ID is empty when the resource can't
+// be imported, such as the IAM bindings, or when its ID is unknown.
+func (resource *terraformResource) getImport() (string, string) {
+	name := resource.GetResourceName()
+	project := resource.getProjectName()
+	switch resource.Kind {
+	case "Folder":
+		address := fmt.Sprintf("google_folder.%s", name)
+		if folderID := resource.GetStringFromObject("status", "folderId"); folderID != "" {
+			return address, fmt.Sprintf("folders/%s", folderID)
+		}
+		return address, ""
+	case "Project":
+		return fmt.Sprintf("module.%s.module.project-factory.google_project.main", name), name
+	case "ComputeNetwork":
+		return fmt.Sprintf("module.%s.module.vpc.google_compute_network.network", name),
+			fmt.Sprintf("projects/%s/global/networks/%s", project, name)
+	case "ComputeSubnetwork":
+		network := resource.References["ComputeNetwork"]
+		region := resource.GetStringFromObject("spec", "region")
+		return fmt.Sprintf("module.%s.module.subnets.google_compute_subnetwork.subnetwork[%q]", network.GetResourceName(), region+"/"+name),
+			fmt.Sprintf("projects/%s/regions/%s/subnetworks/%s", network.getProjectName(), region, name)
+	case "ComputeRoute":
+		network := resource.References["ComputeNetwork"]
+		return fmt.Sprintf("module.%s.module.routes.google_compute_route.route[%q]", network.GetResourceName(), name),
+			fmt.Sprintf("projects/%s/global/routes/%s", network.getProjectName(), name)
+	case "ComputeFirewall":
+		network := resource.References["ComputeNetwork"]
+		return fmt.Sprintf("google_compute_firewall.%s", name),
+			fmt.Sprintf("projects/%s/global/firewalls/%s", network.getProjectName(), name)
+	case "ComputeRouter":
+		network := resource.References["ComputeNetwork"]
+		return fmt.Sprintf("google_compute_router.%s", name),
+			fmt.Sprintf("projects/%s/regions/%s/routers/%s", network.getProjectName(), resource.GetStringFromObject("spec", "region"), name)
+	case "ComputeRouterNAT":
+		router := resource.References["ComputeRouter"]
+		network := router.References["ComputeNetwork"]
+		return fmt.Sprintf("google_compute_router_nat.%s", name),
+			fmt.Sprintf("projects/%s/regions/%s/routers/%s/%s", network.getProjectName(), resource.GetStringFromObject("spec", "region"), router.GetResourceName(), name)
+	case "ComputeAddress":
+		for _, child := range resource.Children {
+			if child.References["ComputeAddress"] != resource {
+				continue
+			}
+			network := child.References["ComputeNetwork"]
+			if child.Kind == "ComputeRouterNAT" {
+				network = child.References["ComputeRouter"].References["ComputeNetwork"]
+				return fmt.Sprintf("google_compute_address.%s", name),
+					fmt.Sprintf("projects/%s/regions/%s/addresses/%s", network.getProjectName(), resource.GetStringFromObject("spec", "location"), name)
+			}
+			if child.Kind == "ServiceNetworkingConnection" {
+				return fmt.Sprintf("google_compute_global_address.%s", name),
+					fmt.Sprintf("projects/%s/global/addresses/%s", network.getProjectName(), name)
+			}
+		}
+	case "ServiceNetworkingConnection":
+		network := resource.References["ComputeNetwork"]
+		return fmt.Sprintf("google_service_networking_connection.%s", name),
+			fmt.Sprintf("projects/%s/global/networks/%s:servicenetworking.googleapis.com", network.getProjectName(), network.GetResourceName())
+	case "IAMServiceAccount":
+		email := resource.GetStringFromObject("status", "email")
+		if email == "" {
+			email = fmt.Sprintf("%s@%s.iam.gserviceaccount.com", resource.GetResourceID(), project)
+		}
+		return fmt.Sprintf("google_service_account.%s", name), fmt.Sprintf("projects/%s/serviceAccounts/%s", project, email)
+	case "KMSKeyRing":
+		return fmt.Sprintf("google_kms_key_ring.%s", name),
+			fmt.Sprintf("projects/%s/locations/%s/keyRings/%s", project, resource.GetStringFromObject("spec", "location"), resource.GetResourceID())
+	case "KMSCryptoKey":
+		keyRing := resource.References["KMSKeyRing"]
+		_, keyRingID := keyRing.getImport()
+		return fmt.Sprintf("google_kms_crypto_key.%s", name), fmt.Sprintf("%s/cryptoKeys/%s", keyRingID, resource.GetResourceID())
+	case "ContainerCluster":
+		return fmt.Sprintf("google_container_cluster.%s", name),
+			fmt.Sprintf("projects/%s/locations/%s/clusters/%s", project, resource.GetStringFromObject("spec", "location"), resource.GetResourceID())
+	case "ContainerNodePool":
+		cluster := resource.References["ContainerCluster"]
+		_, clusterID := cluster.getImport()
+		return fmt.Sprintf("google_container_node_pool.%s", name), fmt.Sprintf("%s/nodePools/%s", clusterID, resource.GetResourceID())
+	case "SQLInstance":
+		return fmt.Sprintf("google_sql_database_instance.%s", name), fmt.Sprintf("projects/%s/instances/%s", project, resource.GetResourceID())
+	case "SQLDatabase":
+		instance := resource.References["SQLInstance"]
+		_, instanceID := instance.getImport()
+		return fmt.Sprintf("google_sql_database.%s", name), fmt.Sprintf("%s/databases/%s", instanceID, resource.GetResourceID())
+	case "SQLUser":
+		instance := resource.References["SQLInstance"]
+		id := fmt.Sprintf("%s/%s", instance.getProjectName(), instance.GetResourceID())
+		if host := resource.GetStringFromObject("spec", "host"); host != "" {
+			id = fmt.Sprintf("%s/%s", id, host)
+		}
+		return fmt.Sprintf("google_sql_user.%s", name), fmt.Sprintf("%s/%s", id, resource.GetResourceID())
+	case "LoggingLogSink":
+		return fmt.Sprintf("module.logsink-%s", name), ""
+	case "BigQueryDataset", "PubSubTopic", "StorageBucket", "LoggingLogBucket":
+		return fmt.Sprintf("module.%s-destination", name), ""
+	case "IAMPolicyMember", "IAMPartialPolicy", "IAMPolicy":
+		return fmt.Sprintf("module.%s-iam", resource.Parent.GetResourceName()), ""
+	case "IAMAuditConfig":
+		return "google_organization_iam_audit_config.org_config", ""
+	}
+	return "", ""
+}
+
+// getProjectName returns the ID of the project of the resource in GCP
+func (resource *terraformResource) getProjectName() string {
+	if resource.Parent == nil || resource.Parent.Kind != "Project" {
+		return ""
+	}
+	if resource.Parent.ShouldCreate() {
+		return resource.Parent.GetResourceName()
+	}
+	return resource.Parent.Name
+}
@@ -22,12 +22,16 @@ import (
 	"strings"
 
 	sdk "github.com/GoogleContainerTools/kpt-functions-catalog/thirdparty/kyaml/fnsdk"
+	"sigs.k8s.io/kustomize/kyaml/yaml"
 )
 
 type terraformResources struct {
 	resources map[string]*terraformResource
 	grouped   map[string][]*terraformResource
 	Variables map[string]*variable
+	Imports   bool                                 // Whether to import the existing resources into Terraform
+	packages  map[string]map[string]string         // The descriptions of the setters of each kpt package, by package directory
+	setters   map[string]map[string]*packageSetter // The setters of each kpt package, by package directory and setter name
 }
 
 func (rs *terraformResources) GetVersion() string {
@@ -51,6 +55,7 @@ func (rs *terraformResources) getResourceRef(kind string, name string, item *sdk
 	}
 	if item != nil {
 		resourceRef.Item = item
+		resourceRef.setters = findSetterFields(item)
 
 		// attach parents
 		err := resourceRef.attachReferences()
@@ -94,6 +99,8 @@ type terraformResource struct {
 	resources  *terraformResources           // A back-reference to the bundle of resources this resource is part of
 	variable   *variable                     // If this resource is defined by a variable, a reference to the associated variable
 	References map[string]*terraformResource // A map of resources this resource references, by the kind of reference
+	namedRefs  []*terraformResource          // The resources referenced by name, which are expected in the package
+	setters    map[string]setterField        // The fields of the resource tagged with setter comments, by fieldKey
 }
 
 // Return if the resource itself should be created
@@ -160,6 +167,87 @@ func (resource *terraformResource) GetStringsFromObject(path ...string) []string
 	return strVals
 }
 
+// Retrieve a map of strings from the resource, such as labels
+func (resource *terraformResource) GetStringMapFromObject(path ...string) map[string]string {
+	var strMap map[string]string
+	found, err := resource.Item.Get(&strMap, path...)
+	if err != nil || !found {
+		return nil
+	}
+	return strMap
+}
+
+// Retrieve a string from the resource as a Terraform expression, which refers
+// to the variables of the setters of the field, or is empty if it isn't set
+func (resource *terraformResource) GetStringValue(path ...string) string {
+	value := resource.GetStringFromObject(path...)
+	if value == "" {
+		return ""
+	}
+	if expr, ok := resource.setterExpression(value, path...); ok {
+		return expr
+	}
+	return hclString(value)
+}
+
+// Retrieve a string from the resource as the content of a Terraform string
+// template, to be embedded in a string literal
+func (resource *terraformResource) GetStringTemplate(path ...string) string {
+	value := resource.GetStringFromObject(path...)
+	if template, ok := resource.setterTemplate(value, path...); ok {
+		return template
+	}
+	return hclEscape(value)
+}
+
+// Return if the resource has a value at a given path
+func (resource *terraformResource) Has(path ...string) bool {
+	var value yaml.RNode
+	found, err := resource.Item.Get(&value, path...)
+	return err == nil && found && !value.IsNilOrEmpty()
+}
+
+// Return the name of the resource in GCP, which is the resourceID if set
+func (resource *terraformResource) GetResourceID() string {
+	if resourceID := resource.GetStringFromObject("spec", "resourceID"); resourceID != "" {
+		return resourceID
+	}
+	return resource.Item.Name()
+}
+
+// Return the name of the resource in GCP as a Terraform expression
+func (resource *terraformResource) GetResourceIDValue() string {
+	resourceID := resource.GetResourceID()
+	if expr, ok := resource.setterExpression(resourceID, "spec", "resourceID"); ok {
+		return expr
+	}
+	if expr, ok := resource.setterExpression(resourceID, "metadata", "name"); ok {
+		return expr
+	}
+	return hclString(resourceID)
+}
+
+// Return the variable defining the resource or its value, if there is one
+func (resource *terraformResource) GetVariable() *variable {
+	return resource.variable
+}
+
+// Return the Terraform expression for the ID of the project of the resource,
+// which is the output of the project module if the project is in the package
+func (resource *terraformResource) GetProjectID() string {
+	if resource.Parent == nil || resource.Parent.Kind != "Project" {
+		sdk.Logf("Failed to fetch project for %s/%s", resource.Kind, resource.Name)
+		return ""
+	}
+	if resource.Parent.ShouldCreate() {
+		return fmt.Sprintf("module.%s.project_id", resource.Parent.GetResourceName())
+	}
+	if expr, ok := resource.setterExpression(resource.Parent.Name, "metadata", "annotations", "cnrm.cloud.google.com/project-id"); ok {
+		return expr
+	}
+	return hclString(resource.Parent.Name)
+}
+
 // Return if the resource itself should be created
 func (resource *terraformResource) GetOrganization() *terraformResource {
 	if resource.Parent.Kind == "Organization" {
@@ -178,6 +266,15 @@ type referencePath struct {
 	kind            string // If kind is unset, it will be auto-detected
 	path            []string
 	customRetriever func(*sdk.KubeObject) string // custom func to retrieve ref from KubeObject
+	external        bool                         // Whether the ref may be outside of the package, even if it isn't an external ref
+}
+
+// isExternal returns whether the path references a resource outside of the package
+func (path referencePath) isExternal() bool {
+	if path.external {
+		return true
+	}
+	return path.customRetriever == nil && len(path.path) > 0 && path.path[len(path.path)-1] == "external"
 }
 
 // singleComputeAddressRetriever creates a customRetriever for a compute address ref
@@ -209,6 +306,7 @@ func logBucketNameRetriever(path []string) func(*sdk.KubeObject) string {
 // Attach parents and other references to a resource
 func (resource *terraformResource) attachReferences() error {
 	resource.References = make(map[string]*terraformResource)
+	resource.namedRefs = nil
 	paths := []referencePath{
 		{kind: "BillingAccount", path: []string{"spec", "billingAccountRef", "external"}},
 		{kind: "BigQueryDataset", path: []string{"spec", "destination", "bigQueryDatasetRef", "name"}},
@@ -216,6 +314,13 @@ func (resource *terraformResource) attachReferences() error {
 		{kind: "StorageBucket", path: []string{"spec", "destination", "storageBucketRef", "name"}},
 		{kind: "ComputeNetwork", path: []string{"spec", "networkRef", "name"}},
 		{kind: "ComputeRouter", path: []string{"spec", "routerRef", "name"}},
+		{kind: "ComputeSubnetwork", path: []string{"spec", "subnetworkRef", "name"}},
+		{kind: "ComputeNetwork", path: []string{"spec", "settings", "ipConfiguration", "privateNetworkRef", "name"}},
+		{kind: "ContainerCluster", path: []string{"spec", "clusterRef", "name"}},
+		{kind: "IAMServiceAccount", path: []string{"spec", "nodeConfig", "serviceAccountRef", "name"}},
+		{kind: "SQLInstance", path: []string{"spec", "instanceRef", "name"}},
+		{kind: "KMSKeyRing", path: []string{"spec", "keyRingRef", "name"}},
+		{kind: "KMSCryptoKey", path: []string{"spec", "encryptionKMSCryptoKeyRef", "name"}},
 		{kind: "ComputeAddress", customRetriever: singleComputeAddressRetriever([]string{"spec", "natIps"})},
 		{kind: "ComputeAddress", customRetriever: singleComputeAddressRetriever([]string{"spec", "reservedPeeringRanges"})},
 		//TODO:awmalik@ - remove customerRetriver when this issue is addressed: https://github.com/GoogleCloudPlatform/k8s-config-connector/issues/665
@@ -226,11 +331,14 @@ func (resource *terraformResource) attachReferences() error {
 		ref := resource.getReferencedResource(kind, path.customRetriever, path.path...)
 		if ref != nil {
 			resource.References[kind] = ref
+			if !path.isExternal() {
+				resource.namedRefs = append(resource.namedRefs, ref)
+			}
 		}
 	}
 
 	// attach parents
-	parentKind, parentName, err := resource.getParentRef()
+	parentKind, parentName, external, err := resource.getParentRef()
 	if err != nil {
 		sdk.Logf("no parent reference found for %s, %v", resource.Item.Name(), err)
 		return err
@@ -244,6 +352,9 @@ func (resource *terraformResource) attachReferences() error {
 		parentRef.Children = append(parentRef.Children, resource)
 		resource.isChild = true
 		resource.Parent = parentRef
+		if !external {
+			resource.namedRefs = append(resource.namedRefs, parentRef)
+		}
 	}
 
 	return nil
@@ -272,12 +383,14 @@ func (resource *terraformResource) getReferencedResource(kind string, customRetr
 	return ref
 }
 
-func (resource *terraformResource) getParentRef(path ...string) (string, string, error) {
+// Retrieve the kind and the name of the parent, and whether it is outside of the package
+func (resource *terraformResource) getParentRef(path ...string) (string, string, bool, error) {
 	paths := []referencePath{
 		{kind: "Folder", path: []string{"spec", "folderRef", "name"}},
 		{kind: "Folder", path: []string{"spec", "folderRef", "external"}},
 		{kind: "Organization", path: []string{"spec", "organizationRef", "external"}},
-		{kind: "Project", path: []string{"metadata", "annotations", "cnrm.cloud.google.com/project-id"}},
+		// the annotation holds the project ID, the project is rendered as is when it isn't in the package
+		{kind: "Project", path: []string{"metadata", "annotations", "cnrm.cloud.google.com/project-id"}, external: true},
 		{path: []string{"spec", "resourceRef", "external"}},
 		{path: []string{"spec", "resourceRef", "name"}},
 	}
@@ -291,14 +404,14 @@ func (resource *terraformResource) getParentRef(path ...string) (string, string,
 		kind := path.kind
 		if len(kind) <= 1 {
 			// retrieve everything except the last element of the path, to find the Kind in a sibling node
-			refPath := path.path[0 : len(path.path)-1]
+			refPath := path.path[0 : len(path.path)-1 : len(path.path)-1]
 			kind = resource.GetStringFromObject(append(refPath, "kind")...)
 		}
 
-		return kind, strings.TrimSpace(name), nil
+		return kind, strings.TrimSpace(name), path.isExternal(), nil
 	}
 
-	return "", "", nil
+	return "", "", false, nil
 }
 
 func (ref *terraformResource) GetDisplayName() string {
@@ -315,6 +428,21 @@ func (ref *terraformResource) GetDisplayName() string {
 	return ref.Item.Name()
 }
 
+// Return the display name of the resource as a Terraform expression
+func (ref *terraformResource) GetDisplayNameValue() string {
+	displayName := ref.GetDisplayName()
+	path := []string{"metadata", "name"}
+	if ref.Has("spec", "displayName") {
+		path = []string{"spec", "displayName"}
+	} else if ref.Has("spec", "name") {
+		path = []string{"spec", "name"}
+	}
+	if expr, ok := ref.setterExpression(displayName, path...); ok {
+		return expr
+	}
+	return hclString(displayName)
+}
+
 var tfNameRegex = regexp.MustCompile(`[^a-zA-Z\d_-]`)
 
 func (ref *terraformResource) GetResourceName() string {
@@ -339,6 +467,30 @@ func (ref *terraformResource) GetResourceName() string {
 	return ""
 }
 
+// Return the name of the resource as a Terraform expression, which refers to
+// the variables of the setters when the resource is named after its name in GCP
+func (ref *terraformResource) GetResourceNameValue() string {
+	name := ref.GetResourceName()
+	if ref.ShouldCreate() && name == ref.Name {
+		if expr, ok := ref.setterExpression(name, "metadata", "name"); ok {
+			return expr
+		}
+	}
+	return hclString(name)
+}
+
+// Return the name of the resource as the content of a Terraform string
+// template, to be embedded in a string literal
+func (ref *terraformResource) GetResourceNameTemplate() string {
+	name := ref.GetResourceName()
+	if ref.ShouldCreate() && name == ref.Name {
+		if template, ok := ref.setterTemplate(name, "metadata", "name"); ok {
+			return template
+		}
+	}
+	return hclEscape(name)
+}
+
 func (ref *terraformResource) GetTerraformId(prefix ...bool) string {
 	if ref.ShouldCreate() {
 		return fmt.Sprintf("google_folder.%s.name", ref.GetResourceName())
@@ -22,8 +22,9 @@ func TestGetParentRef(t *testing.T) {
 		Item: item,
 	}
 
-	kind, name, err := resource.getParentRef()
+	kind, name, external, err := resource.getParentRef()
 	require.NoErrorf(err, "Finds parent resource")
 	require.Equalf("Folder", kind, "kind to match")
 	require.Equalf("335620346181", name, "name to match")
+	require.Truef(external, "parent to be external")
 }
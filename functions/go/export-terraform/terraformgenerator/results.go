@@ -0,0 +1,203 @@
+// Copyright 2022 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package terraformgenerator
+
+import (
+	"fmt"
+
+	sdk "github.com/GoogleContainerTools/kpt-functions-catalog/thirdparty/kyaml/fnsdk"
+	"sigs.k8s.io/kustomize/kyaml/kio/kioutil"
+)
+
+// exportReport collects the results for the resources which didn't make it
+// into the Terraform configuration
+type exportReport struct {
+	strict      bool
+	results     sdk.Results
+	total       int
+	ignored     int
+	unsupported int
+	unresolved  int
+	lossy       int
+}
+
+// dropSeverity returns the severity of the results for the dropped resources
+func (report *exportReport) dropSeverity() sdk.Severity {
+	if report.strict {
+		return sdk.Error
+	}
+	return sdk.Warning
+}
+
+// ignore reports a resource excluded from the export by the skip annotation
+func (report *exportReport) ignore(item *sdk.KubeObject) {
+	report.ignored++
+	report.results = append(report.results, objectResult(
+		fmt.Sprintf("%s %q is skipped because of the %s annotation", item.Kind(), item.Name(), skipAnnotation),
+		item, sdk.Info))
+}
+
+// unsupportedKind reports a resource whose kind can't be exported
+func (report *exportReport) unsupportedKind(item *sdk.KubeObject) {
+	report.unsupported++
+	report.results = append(report.results, objectResult(
+		fmt.Sprintf("%s %q is skipped because its kind is not supported", item.Kind(), item.Name()),
+		item, report.dropSeverity()))
+}
+
+// check reports the unresolved references and the lossy conversions of the
+// resources to export
+func (report *exportReport) check(rs *terraformResources) {
+	for _, resources := range rs.getGrouped() {
+		for _, resource := range resources {
+			if !resource.ShouldCreate() {
+				continue
+			}
+			for _, ref := range resource.namedRefs {
+				if ref.ShouldCreate() {
+					continue
+				}
+				report.unresolved++
+				report.results = append(report.results, objectResult(
+					fmt.Sprintf("%s %q references %s %q which is not found in the package", resource.Kind, resource.Name, ref.Kind, ref.Name),
+					resource.Item, report.dropSeverity()))
+			}
+			if reason := resource.dropReason(); reason != "" {
+				report.lossy++
+				report.results = append(report.results, objectResult(
+					fmt.Sprintf("%s %q is not exported: %s", resource.Kind, resource.Name, reason),
+					resource.Item, report.dropSeverity()))
+			}
+		}
+	}
+	for _, setters := range rs.setters {
+		for _, setter := range setters {
+			if reason := setter.literalReason(); reason != "" {
+				report.results = append(report.results, sdk.GeneralResult(reason, sdk.Info))
+			}
+		}
+	}
+	report.results.Sort()
+}
+
+// literalReason returns why the fields set by the setter are exported as
+// literal values instead of referring to a variable, if they are
+func (setter *packageSetter) literalReason() string {
+	name := fmt.Sprintf("setter %q", setter.name)
+	if setter.pkg != "" {
+		name = fmt.Sprintf("setter %q of the package %q", setter.name, setter.pkg)
+	}
+	switch {
+	case !setter.used && setter.inLists:
+		return fmt.Sprintf("%s is not turned into a Terraform variable: it only sets lists or their elements", name)
+	case !setter.used:
+		return ""
+	case setter.variable == nil:
+		return fmt.Sprintf("%s is not turned into a Terraform variable: its fields have different values %q", name, setter.values)
+	case setter.collides:
+		return fmt.Sprintf("%s is not turned into a Terraform variable: the variable %q is already declared", name, setter.variable.Name)
+	case setter.inLists:
+		return fmt.Sprintf("the lists and list elements set by %s don't refer to the variable %q", name, setter.variable.Name)
+	}
+	return ""
+}
+
+// dropped returns the number of the resources and the references dropped
+// from the export, not counting the resources ignored on purpose
+func (report *exportReport) dropped() int {
+	return report.unsupported + report.unresolved + report.lossy
+}
+
+// summary returns the result with the counts of the report
+func (report *exportReport) summary() *sdk.Result {
+	skipped := report.ignored + report.unsupported
+	return sdk.GeneralResult(fmt.Sprintf(
+		"exported %d of %d Config Connector resources to Terraform: %d skipped, %d unresolved references, %d lossy conversions",
+		report.total-skipped-report.lossy, report.total, skipped, report.unresolved, report.lossy), sdk.Info)
+}
+
+// objectResult returns a result for the object with the path of its file
+func objectResult(msg string, item *sdk.KubeObject, severity sdk.Severity) *sdk.Result {
+	result := sdk.ConfigObjectResult(msg, item, severity)
+	result.File.Path = itemPath(item)
+	return result
+}
+
+// itemPath returns the path of the file of the object.
+// KubeObject.PathAnnotation doesn't fall back to the legacy annotation.
+func itemPath(item *sdk.KubeObject) string {
+	if filePath := item.Annotation(kioutil.PathAnnotation); filePath != "" {
+		return filePath
+	}
+	return item.Annotation(kioutil.LegacyPathAnnotation)
+}
+
+// dropReason returns why the resource is left out of the Terraform
+// configuration by the templates, if it is
+func (resource *terraformResource) dropReason() string {
+	switch resource.Kind {
+	case "IAMPolicyMember", "IAMPartialPolicy", "IAMPolicy":
+		if resource.Parent == nil {
+			return "the IAM resource has no resourceRef"
+		}
+		if resource.Parent.Kind != "Organization" && resource.Parent.Kind != "Folder" {
+			return fmt.Sprintf("IAM on %s is not supported, only on Organization and Folder", resource.Parent.Kind)
+		}
+	case "IAMAuditConfig":
+		if resource.Parent == nil || resource.Parent.Kind != "Organization" {
+			return "audit configs are only supported on Organization"
+		}
+	case "BigQueryDataset", "PubSubTopic", "StorageBucket", "LoggingLogBucket":
+		for _, sink := range resource.GetChildrenByKind("LoggingLogSink") {
+			if sink.ShouldCreate() {
+				return ""
+			}
+		}
+		return fmt.Sprintf("%s is only supported as the destination of a LoggingLogSink", resource.Kind)
+	case "ComputeSubnetwork", "ComputeRoute", "ComputeFirewall", "ComputeRouter", "ServiceNetworkingConnection":
+		if network := resource.References["ComputeNetwork"]; network == nil || !network.ShouldCreate() {
+			return fmt.Sprintf("%s is only supported with its ComputeNetwork in the package", resource.Kind)
+		}
+	case "ComputeRouterNAT":
+		if router := resource.References["ComputeRouter"]; router == nil || !router.ShouldCreate() || router.dropReason() != "" {
+			return "ComputeRouterNAT is only supported with its ComputeRouter exported"
+		}
+	case "ComputeAddress":
+		for _, child := range resource.Children {
+			if (child.Kind == "ComputeRouterNAT" || child.Kind == "ServiceNetworkingConnection") &&
+				child.References["ComputeAddress"] == resource && child.ShouldCreate() && child.dropReason() == "" {
+				return ""
+			}
+		}
+		return "ComputeAddress is only supported as the address of an exported ComputeRouterNAT or ServiceNetworkingConnection"
+	case "ContainerNodePool":
+		if cluster := resource.References["ContainerCluster"]; cluster == nil || !cluster.ShouldCreate() {
+			return "ContainerNodePool is only supported with its ContainerCluster in the package"
+		}
+	case "SQLDatabase", "SQLUser":
+		if instance := resource.References["SQLInstance"]; instance == nil || !instance.ShouldCreate() {
+			return fmt.Sprintf("%s is only supported with its SQLInstance in the package", resource.Kind)
+		}
+	case "KMSCryptoKey":
+		if keyRing := resource.References["KMSKeyRing"]; keyRing == nil || !keyRing.ShouldCreate() {
+			return "KMSCryptoKey is only supported with its KMSKeyRing in the package"
+		}
+	case "ComputeSharedVPCHostProject":
+		if resource.Parent == nil || resource.Parent.Kind != "Project" || !resource.Parent.ShouldCreate() {
+			return "ComputeSharedVPCHostProject is only supported with its Project in the package"
+		}
+	}
+	return ""
+}
@@ -0,0 +1,99 @@
+package terraformgenerator
+
+import (
+	"path"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	sdk "github.com/GoogleContainerTools/kpt-functions-catalog/thirdparty/kyaml/fnsdk"
+	"github.com/GoogleContainerTools/kpt-functions-catalog/thirdparty/kyaml/fnsdk/testutil"
+)
+
+type exportResult struct {
+	Severity sdk.Severity
+	File     string
+	Message  string
+}
+
+func TestProcessorResults(t *testing.T) {
+	testCases := []struct {
+		name     string
+		strict   bool
+		results  []exportResult
+		errorMsg string
+	}{
+		{
+			name: "iam",
+			results: []exportResult{
+				{sdk.Info, "folder_ignored.yaml", `IAMPolicyMember "ignored-resource" is skipped because of the cnrm.cloud.google.com/ignore-clusterless annotation`},
+				{sdk.Info, "", "exported 10 of 11 Config Connector resources to Terraform: 1 skipped, 0 unresolved references, 0 lossy conversions"},
+			},
+		},
+		{
+			name:   "iam",
+			strict: true,
+			results: []exportResult{
+				{sdk.Info, "folder_ignored.yaml", `IAMPolicyMember "ignored-resource" is skipped because of the cnrm.cloud.google.com/ignore-clusterless annotation`},
+				{sdk.Info, "", "exported 10 of 11 Config Connector resources to Terraform: 1 skipped, 0 unresolved references, 0 lossy conversions"},
+			},
+		},
+		{
+			name: "log",
+			results: []exportResult{
+				{sdk.Info, "", `setter "location" is not turned into a Terraform variable: its fields have different values ["US" "global"]`},
+				{sdk.Info, "", `setter "management-project-id" is not turned into a Terraform variable: it only sets lists or their elements`},
+				{sdk.Warning, "iam.yaml", `IAMPolicyMember "bq-project-iam-policy" is not exported: IAM on Project is not supported, only on Organization and Folder`},
+				{sdk.Warning, "iam.yaml", `IAMPartialPolicy "logging-sa-iam-permissions" is not exported: IAM on Project is not supported, only on Organization and Folder`},
+				{sdk.Info, "", "exported 9 of 11 Config Connector resources to Terraform: 0 skipped, 0 unresolved references, 2 lossy conversions"},
+			},
+		},
+		{
+			name:   "sql",
+			strict: true,
+			results: []exportResult{
+				{sdk.Info, "", "exported 7 of 7 Config Connector resources to Terraform: 0 skipped, 0 unresolved references, 0 lossy conversions"},
+			},
+		},
+		{
+			name:   "other_resources",
+			strict: true,
+			results: []exportResult{
+				{sdk.Error, "bucket.yaml", `StorageBucket "ignored-bucket" is not exported: StorageBucket is only supported as the destination of a LoggingLogSink`},
+				{sdk.Error, "context.yaml", `ConfigConnectorContext "configconnectorcontext.core.cnrm.cloud.google.com" is skipped because its kind is not supported`},
+				{sdk.Error, "context.yaml", `IAMPartialPolicy "test-project-sa-workload-identity-binding" is not exported: IAM on Project is not supported, only on Organization and Folder`},
+				{sdk.Info, "", "exported 2 of 5 Config Connector resources to Terraform: 1 skipped, 0 unresolved references, 2 lossy conversions"},
+			},
+			errorMsg: "3 resources or references were dropped from the Terraform configuration",
+		},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			require := require.New(t)
+			rl, err := testutil.ResourceListFromDirectory(path.Join("..", testDir, tt.name, "input"), "")
+			require.NoError(err)
+			if tt.strict {
+				rl.FunctionConfig, err = sdk.ParseKubeObject([]byte("apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: fn-config\ndata:\n  strict: \"true\"\n"))
+				require.NoError(err)
+			}
+
+			err = Processor(rl)
+			if tt.errorMsg != "" {
+				require.EqualError(err, tt.errorMsg)
+			} else {
+				require.NoError(err)
+			}
+
+			var results []exportResult
+			for _, result := range rl.Results {
+				var file string
+				if result.File != nil {
+					file = result.File.Path
+				}
+				results = append(results, exportResult{result.Severity, file, result.Message})
+			}
+			require.Equal(tt.results, results)
+		})
+	}
+}
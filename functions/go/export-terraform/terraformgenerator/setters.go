@@ -0,0 +1,362 @@
+// Copyright 2022 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package terraformgenerator
+
+import (
+	"fmt"
+	"path"
+	"regexp"
+	"strconv"
+	"strings"
+
+	sdk "github.com/GoogleContainerTools/kpt-functions-catalog/thirdparty/kyaml/fnsdk"
+	"sigs.k8s.io/kustomize/kyaml/yaml"
+)
+
+const (
+	// setterCommentIdentifier prefixes the setter pattern in the line comment
+	// of a field, as with apply-setters and list-setters
+	setterCommentIdentifier = "# kpt-set: "
+	kptfileKind             = "Kptfile"
+	applySettersKind        = "ApplySetters"
+)
+
+var setterRefRegex = regexp.MustCompile(`\$\{([^}]*)\}`)
+
+// setterField is a field of a resource tagged with a setter comment
+type setterField struct {
+	pattern string // The setter pattern in the comment, e.g. ${name}-bucket
+	value   string // The current value of the field, empty for a list
+	inList  bool   // Whether the field is a list or an element of a list
+}
+
+// packageSetter is a setter of a kpt package along with the values it has in
+// the fields of the resources of the package
+type packageSetter struct {
+	name     string
+	pkg      string    // The directory of the package, empty for the root package
+	values   []string  // The distinct values of the setter
+	variable *variable // The variable for the setter, unless its values conflict
+	used     bool      // Whether an exported field is set by the setter
+	collides bool      // Whether another variable with the same name is declared
+	inLists  bool      // Whether the setter sets lists or their elements, which are exported as literals
+}
+
+// fieldKey returns the key of the field at a path in the setter fields of a
+// resource, the fields of the path may contain dots
+func fieldKey(path ...string) string {
+	return fmt.Sprintf("%q", path)
+}
+
+// findSetterFields returns the fields of the object tagged with setter
+// comments by their key, list-setters finds the setters the same way: the
+// scalar fields, the elements of lists by their index, and the lists tagged on
+// their key or, in flow style, on the list itself
+func findSetterFields(item *sdk.KubeObject) map[string]setterField {
+	fields := make(map[string]setterField)
+	var visit func(node *yaml.Node, path []string, inList bool)
+	visit = func(node *yaml.Node, path []string, inList bool) {
+		switch node.Kind {
+		case yaml.DocumentNode:
+			for _, child := range node.Content {
+				visit(child, path, inList)
+			}
+		case yaml.MappingNode:
+			for i := 0; i+1 < len(node.Content); i += 2 {
+				key, value := node.Content[i], node.Content[i+1]
+				fieldPath := append(path[:len(path):len(path)], key.Value)
+				if value.Kind == yaml.SequenceNode {
+					lineComment := key.LineComment
+					if value.Style == yaml.FlowStyle {
+						lineComment = value.LineComment
+					}
+					if pattern := setterPattern(lineComment); pattern != "" {
+						fields[fieldKey(fieldPath...)] = setterField{pattern: pattern, inList: true}
+					}
+				}
+				visit(value, fieldPath, inList)
+			}
+		case yaml.SequenceNode:
+			for i, element := range node.Content {
+				visit(element, append(path[:len(path):len(path)], strconv.Itoa(i)), true)
+			}
+		case yaml.ScalarNode:
+			if pattern := setterPattern(node.LineComment); pattern != "" {
+				fields[fieldKey(path...)] = setterField{pattern: pattern, value: node.Value, inList: inList}
+			}
+		}
+	}
+	visit(item.ToRNode().YNode(), nil, false)
+	return fields
+}
+
+// setterPattern returns the setter pattern in a line comment, if there is one
+func setterPattern(lineComment string) string {
+	if !strings.HasPrefix(lineComment, setterCommentIdentifier) {
+		return ""
+	}
+	return strings.TrimSpace(strings.TrimPrefix(lineComment, setterCommentIdentifier))
+}
+
+// setterTransform is a function which can be applied to a setter value within a
+// setter reference, along with the equivalent Terraform function. The
+// transforms evaluate values the same way as in apply-setters and list-setters.
+type setterTransform struct {
+	evaluate  func(value, arg string) (string, error)
+	terraform func(expr, arg string) string
+}
+
+var setterTransforms = map[string]setterTransform{
+	"lower": {
+		evaluate:  func(value, _ string) (string, error) { return strings.ToLower(value), nil },
+		terraform: func(expr, _ string) string { return fmt.Sprintf("lower(%s)", expr) },
+	},
+	"upper": {
+		evaluate:  func(value, _ string) (string, error) { return strings.ToUpper(value), nil },
+		terraform: func(expr, _ string) string { return fmt.Sprintf("upper(%s)", expr) },
+	},
+	"default": {
+		evaluate: func(value, arg string) (string, error) {
+			if value == "" {
+				return arg, nil
+			}
+			return value, nil
+		},
+		terraform: func(expr, arg string) string { return fmt.Sprintf("coalesce(%s, %q)", expr, arg) },
+	},
+	"trunc": {
+		evaluate: func(value, arg string) (string, error) {
+			n, err := strconv.Atoi(arg)
+			if err != nil || n < 0 {
+				return "", fmt.Errorf("trunc requires a non-negative integer argument, but found %q", arg)
+			}
+			// substr counts characters, not bytes
+			if runes := []rune(value); len(runes) > n {
+				return string(runes[:n]), nil
+			}
+			return value, nil
+		},
+		terraform: func(expr, arg string) string { return fmt.Sprintf("substr(%s, 0, %s)", expr, arg) },
+	},
+	"quote": {
+		evaluate:  func(value, _ string) (string, error) { return value, nil },
+		terraform: func(expr, _ string) string { return expr },
+	},
+}
+
+// setterRef is a setter reference within a setter pattern, along with the
+// transforms applied to the setter value, e.g. ${name|lower|trunc:63}
+type setterRef struct {
+	name       string
+	transforms [][2]string // The names and the arguments of the transforms
+}
+
+// parseSetterRef parses a setter reference like list-setters does, the whole
+// reference is the setter name if any transform is unknown
+func parseSetterRef(ref string) setterRef {
+	name := strings.TrimSpace(ref)
+	name = strings.TrimSuffix(strings.TrimPrefix(name, "${"), "}")
+	segments := strings.Split(name, "|")
+	r := setterRef{name: segments[0]}
+	for _, segment := range segments[1:] {
+		transform, arg := segment, ""
+		if i := strings.Index(segment, ":"); i >= 0 {
+			transform, arg = segment[:i], segment[i+1:]
+		}
+		if _, found := setterTransforms[transform]; !found {
+			return setterRef{name: name}
+		}
+		r.transforms = append(r.transforms, [2]string{transform, arg})
+	}
+	return r
+}
+
+// value derives the setter value from the part of the field rendered by the
+// reference. All the transforms are idempotent, so the rendered part is the
+// setter value if it renders to itself.
+func (r setterRef) value(rendered string) (string, bool) {
+	value := rendered
+	for _, t := range r.transforms {
+		var err error
+		if value, err = setterTransforms[t[0]].evaluate(value, t[1]); err != nil {
+			return "", false
+		}
+	}
+	return rendered, value == rendered
+}
+
+// terraform returns the Terraform expression which applies the transforms of
+// the reference to an expression
+func (r setterRef) terraform(expr string) string {
+	for _, t := range r.transforms {
+		expr = setterTransforms[t[0]].terraform(expr, t[1])
+	}
+	return expr
+}
+
+// setterValues returns the values of the setters of a pattern derived from the
+// value of the field, or false if the value doesn't match the pattern
+func setterValues(pattern, value string) (map[string]string, bool) {
+	locs := setterRefRegex.FindAllStringIndex(pattern, -1)
+	if len(locs) == 0 {
+		return nil, false
+	}
+	var expr strings.Builder
+	var refs []setterRef
+	last := 0
+	for _, loc := range locs {
+		expr.WriteString(regexp.QuoteMeta(pattern[last:loc[0]]))
+		expr.WriteString("(.*)")
+		refs = append(refs, parseSetterRef(pattern[loc[0]:loc[1]]))
+		last = loc[1]
+	}
+	expr.WriteString(regexp.QuoteMeta(pattern[last:]))
+	matches := regexp.MustCompile("^" + expr.String() + "$").FindStringSubmatch(value)
+	if matches == nil {
+		return nil, false
+	}
+
+	values := make(map[string]string)
+	for i, ref := range refs {
+		v, ok := ref.value(matches[i+1])
+		if !ok || v == "" {
+			return nil, false
+		}
+		if previous, found := values[ref.name]; found && previous != v {
+			return nil, false
+		}
+		values[ref.name] = v
+	}
+	return values, true
+}
+
+// findPackages returns the descriptions of the setters of the kpt packages by
+// package directory, as declared in the apply-setters functionConfig of the
+// Kptfile of each package in the resources
+func findPackages(items []*sdk.KubeObject) map[string]map[string]string {
+	packages := make(map[string]map[string]string)
+	for _, item := range items {
+		if item.Kind() != kptfileKind {
+			continue
+		}
+		dir := packageDir(itemPath(item))
+		descriptions := make(map[string]string)
+		packages[dir] = descriptions
+
+		mutators, err := item.ToRNode().Pipe(yaml.Lookup("pipeline", "mutators"))
+		if err != nil || mutators == nil {
+			continue
+		}
+		for _, fn := range mutators.Content() {
+			fn := yaml.NewRNode(fn)
+			if !strings.Contains(yaml.GetValue(fn.Field("image").Value), "apply-setters") {
+				continue
+			}
+			if configMap := fn.Field("configMap"); configMap != nil {
+				addSetterDescriptions(descriptions, configMap.Value.YNode())
+			} else if configPath := fn.Field("configPath"); configPath != nil {
+				fnConfigPath := path.Join(dir, yaml.GetValue(configPath.Value))
+				for _, config := range items {
+					if itemPath(config) == fnConfigPath {
+						addFnConfigDescriptions(descriptions, config)
+					}
+				}
+			}
+		}
+	}
+	return packages
+}
+
+// packageDir returns the directory of the package of a Kptfile
+func packageDir(kptfilePath string) string {
+	if dir := path.Dir(kptfilePath); dir != "." {
+		return dir
+	}
+	return ""
+}
+
+// addFnConfigDescriptions adds the descriptions of the setters of an
+// apply-setters functionConfig, either a ConfigMap or an ApplySetters
+func addFnConfigDescriptions(descriptions map[string]string, fnConfig *sdk.KubeObject) {
+	rn := fnConfig.ToRNode()
+	if fnConfig.Kind() != applySettersKind {
+		if data := rn.Field("data"); data != nil {
+			addSetterDescriptions(descriptions, data.Value.YNode())
+		}
+		return
+	}
+	setters := rn.Field("setters")
+	if setters == nil {
+		return
+	}
+	for _, setter := range setters.Value.Content() {
+		name := yaml.NewRNode(setter).Field("name")
+		if name == nil {
+			continue
+		}
+		if description := commentText(setter.HeadComment, name.Key.YNode().HeadComment); description != "" {
+			descriptions[yaml.GetValue(name.Value)] = description
+		}
+	}
+}
+
+// addSetterDescriptions adds the descriptions of the setters of a map of
+// setter values, which are the comments of the setters
+func addSetterDescriptions(descriptions map[string]string, setters *yaml.Node) {
+	if setters.Kind != yaml.MappingNode {
+		return
+	}
+	for i := 0; i+1 < len(setters.Content); i += 2 {
+		key, value := setters.Content[i], setters.Content[i+1]
+		if description := commentText(key.HeadComment, value.LineComment, key.LineComment); description != "" {
+			descriptions[key.Value] = description
+		}
+	}
+}
+
+// commentText returns the text of the first comment which isn't empty, on a
+// single line
+func commentText(comments ...string) string {
+	for _, comment := range comments {
+		var lines []string
+		for _, line := range strings.Split(comment, "\n") {
+			if line = strings.TrimSpace(strings.TrimPrefix(strings.TrimSpace(line), "#")); line != "" {
+				lines = append(lines, line)
+			}
+		}
+		if len(lines) > 0 {
+			return strings.Join(lines, " ")
+		}
+	}
+	return ""
+}
+
+var (
+	terraformNameRegex = regexp.MustCompile(`[^a-zA-Z\d_]+`)
+	// reservedVariableNames can't be used as the names of Terraform variables
+	reservedVariableNames = map[string]bool{
+		"source": true, "version": true, "providers": true, "count": true,
+		"for_each": true, "lifecycle": true, "depends_on": true, "locals": true,
+	}
+)
+
+// variableName returns a valid Terraform variable name for a setter
+func variableName(name string) string {
+	name = strings.Trim(terraformNameRegex.ReplaceAllString(name, "_"), "_")
+	if name == "" || (name[0] >= '0' && name[0] <= '9') || reservedVariableNames[name] {
+		name = "setter_" + name
+	}
+	return name
+}
@@ -0,0 +1,193 @@
+// Copyright 2022 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package terraformgenerator
+
+import (
+	"testing"
+
+	sdk "github.com/GoogleContainerTools/kpt-functions-catalog/thirdparty/kyaml/fnsdk"
+	"github.com/stretchr/testify/require"
+)
+
+// setterPackages is a root package with a team subpackage, the Kptfiles are
+// only read by the function with --include-meta-resources
+var setterPackages = []string{`
+apiVersion: kpt.dev/v1
+kind: Kptfile
+metadata:
+  name: root
+  annotations:
+    internal.config.kubernetes.io/path: Kptfile
+pipeline:
+  mutators:
+    - image: gcr.io/kpt-fn/apply-setters:v0.2
+      configMap:
+        # The name of the team
+        team: blue
+        env: dev # The environment of the folders
+`, `
+apiVersion: resourcemanager.cnrm.cloud.google.com/v1beta1
+kind: Folder
+metadata:
+  name: blue-team # kpt-set: ${team}-team
+  annotations:
+    internal.config.kubernetes.io/path: folders.yaml
+spec:
+  displayName: blue # kpt-set: ${team|lower}
+  organizationRef:
+    external: "123456789012"
+  teams:
+    - blue # kpt-set: ${team}
+`, `
+apiVersion: resourcemanager.cnrm.cloud.google.com/v1beta1
+kind: Folder
+metadata:
+  name: dev
+  annotations:
+    internal.config.kubernetes.io/path: folders.yaml
+spec:
+  displayName: dev # kpt-set: ${env}
+  organizationRef:
+    external: "123456789012"
+`, `
+apiVersion: resourcemanager.cnrm.cloud.google.com/v1beta1
+kind: Folder
+metadata:
+  name: prod
+  annotations:
+    internal.config.kubernetes.io/path: folders.yaml
+spec:
+  displayName: prod # kpt-set: ${env}
+  organizationRef:
+    external: "123456789012"
+  regions: [us-east1, us-west1] # kpt-set: ${regions}
+`, `
+apiVersion: kpt.dev/v1
+kind: Kptfile
+metadata:
+  name: team
+  annotations:
+    internal.config.kubernetes.io/path: team/Kptfile
+pipeline:
+  mutators:
+    - image: gcr.io/kpt-fn/apply-setters:v0.2
+      configPath: setters.yaml
+`, `
+apiVersion: fn.kpt.dev/v1alpha1
+kind: ApplySetters
+metadata:
+  name: setters
+  annotations:
+    internal.config.kubernetes.io/path: team/setters.yaml
+    config.kubernetes.io/local-config: "true"
+setters:
+  # The name of the subteam
+  - name: team
+    value: red
+`, `
+apiVersion: resourcemanager.cnrm.cloud.google.com/v1beta1
+kind: Folder
+metadata:
+  name: red-team # kpt-set: ${team}-team
+  annotations:
+    internal.config.kubernetes.io/path: team/folders.yaml
+spec:
+  displayName: red-team # kpt-set: ${team}-team
+  organizationRef:
+    external: "123456789012"
+`, `
+apiVersion: resourcemanager.cnrm.cloud.google.com/v1beta1
+kind: Folder
+metadata:
+  name: shared
+  annotations:
+    internal.config.kubernetes.io/path: folders.yaml
+spec:
+  displayName: 'the "${team}" folder'
+  organizationRef:
+    external: "123456789012"
+`}
+
+func TestSetterVariables(t *testing.T) {
+	require := require.New(t)
+	rl := &sdk.ResourceList{}
+	for _, contents := range setterPackages {
+		item, err := sdk.ParseKubeObject([]byte(contents))
+		require.NoError(err)
+		rl.Items = append(rl.Items, item)
+	}
+	require.NoError(Processor(rl))
+
+	var data map[string]string
+	for _, item := range rl.Items {
+		if item.Kind() == "ConfigMap" {
+			_, err := item.Get(&data, "data")
+			require.NoError(err)
+		}
+	}
+
+	require.Contains(data["folders.tf"], `resource "google_folder" "blue-team" {
+  display_name = lower(var.team)`)
+	require.Contains(data["folders.tf"], `resource "google_folder" "red-team" {
+  display_name = "${var.team_team}-team"`)
+	require.Contains(data["folders.tf"], `display_name = "prod"`)
+	require.Contains(data["folders.tf"], `display_name = "the \"$${team}\" folder"`)
+
+	require.Contains(data["variables.tf"], `variable "team" {
+  description = "The name of the team"
+  type        = string
+  default     = "blue"
+}`)
+	require.Contains(data["variables.tf"], `variable "team_team" {
+  description = "The name of the subteam"
+  type        = string
+  default     = "red"
+}`)
+	require.NotContains(data["variables.tf"], `variable "env"`)
+
+	var messages []string
+	for _, result := range rl.Results {
+		messages = append(messages, result.Message)
+	}
+	require.Contains(messages, `setter "env" is not turned into a Terraform variable: its fields have different values ["dev" "prod"]`)
+	require.Contains(messages, `setter "regions" is not turned into a Terraform variable: it only sets lists or their elements`)
+	require.Contains(messages, `the lists and list elements set by setter "team" don't refer to the variable "team"`)
+}
+
+func TestSetterValues(t *testing.T) {
+	testCases := []struct {
+		pattern string
+		value   string
+		values  map[string]string
+	}{
+		{"${name}", "bucket", map[string]string{"name": "bucket"}},
+		{"${org-id}-sink", "1234-sink", map[string]string{"org-id": "1234"}},
+		{"${name|lower}-${env}", "team-dev", map[string]string{"name": "team", "env": "dev"}},
+		{"${name|lower}", "Team", nil},
+		{"${name|trunc:4}", "team", map[string]string{"name": "team"}},
+		{"${name|trunc:4}-app", "café-app", map[string]string{"name": "café"}},
+		{"${name|trunc:3}", "café", nil},
+		{"${name}-${name}", "a-b", nil},
+		{"${name}-sink", "sink", nil},
+		{"literal", "literal", nil},
+	}
+	for _, tt := range testCases {
+		values, ok := setterValues(tt.pattern, tt.value)
+		require.Equalf(t, tt.values != nil, ok, "pattern %q with value %q", tt.pattern, tt.value)
+		if ok {
+			require.Equal(t, tt.values, values)
+		}
+	}
+}
@@ -0,0 +1,49 @@
+// Copyright 2022 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package terraformgenerator
+
+import (
+	sdk "github.com/GoogleContainerTools/kpt-functions-catalog/thirdparty/kyaml/fnsdk"
+)
+
+type sqlDatabaseFlag struct {
+	Name  string `yaml:"name"`
+	Value string `yaml:"value"`
+}
+
+type sqlAuthorizedNetwork struct {
+	Name  string `yaml:"name,omitempty"`
+	Value string `yaml:"value"`
+}
+
+// GetDatabaseFlags returns the database flags of a Cloud SQL instance
+func (resource *terraformResource) GetDatabaseFlags() []sqlDatabaseFlag {
+	var flags []sqlDatabaseFlag
+	found, err := resource.Item.Get(&flags, "spec", "settings", "databaseFlags")
+	if !found || err != nil {
+		sdk.Logf("unable to find database flags in %s (found = %t): %s\n", resource.Name, found, err)
+	}
+	return flags
+}
+
+// GetSQLAuthorizedNetworks returns the networks allowed to connect to a Cloud SQL instance
+func (resource *terraformResource) GetSQLAuthorizedNetworks() []sqlAuthorizedNetwork {
+	var networks []sqlAuthorizedNetwork
+	found, err := resource.Item.Get(&networks, "spec", "settings", "ipConfiguration", "authorizedNetworks")
+	if !found || err != nil {
+		sdk.Logf("unable to find authorized networks in %s (found = %t): %s\n", resource.Name, found, err)
+	}
+	return networks
+}
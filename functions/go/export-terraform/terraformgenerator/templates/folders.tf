@@ -1,6 +1,6 @@
 {{range $folder := .Folder}}{{ if $folder.ShouldCreate }}
 resource "google_folder" "{{ $folder.GetResourceName }}" {
-  display_name = "{{ $folder.GetDisplayName }}"
+  display_name = {{ $folder.GetDisplayNameValue }}
   parent       = {{ $folder.Parent.GetTerraformId }}
 }
 {{end}}{{end}}
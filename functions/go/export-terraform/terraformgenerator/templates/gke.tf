@@ -0,0 +1,96 @@
+{{range $cluster := .ContainerCluster}}{{ if $cluster.ShouldCreate }}
+resource "google_container_cluster" "{{ $cluster.GetResourceName }}" {
+  name     = {{ $cluster.GetResourceIDValue }}{{ with $cluster.GetProjectID }}
+  project  = {{ . }}{{end}}
+  location = {{ $cluster.GetStringValue "spec" "location" }}{{ with $cluster.GetStringValue "spec" "description" }}
+  description = {{ . }}{{end}}{{ with $cluster.GetStringsFromObject "spec" "nodeLocations" }}
+  node_locations = [{{ range . }}"{{ . }}",{{end}}]{{end}}{{ with $cluster.References.ComputeNetwork }}{{ if .ShouldCreate }}
+  network  = module.{{ .GetResourceName }}.network_self_link{{end}}{{ else }}{{ with $cluster.GetStringValue "spec" "networkRef" "external" }}
+  network  = {{ . }}{{end}}{{end}}{{ with $subnet := $cluster.References.ComputeSubnetwork }}{{ if $subnet.ShouldCreate }}{{ with $subnet.References.ComputeNetwork }}
+  subnetwork = module.{{ .GetResourceName }}.subnets["{{ $subnet.GetStringTemplate "spec" "region" }}/{{ $subnet.GetResourceNameTemplate }}"].self_link{{end}}{{end}}{{ else }}{{ with $cluster.GetStringValue "spec" "subnetworkRef" "external" }}
+  subnetwork = {{ . }}{{end}}{{end}}{{ with $cluster.GetStringValue "spec" "networkingMode" }}
+  networking_mode = {{ . }}{{end}}{{ with $cluster.GetStringValue "spec" "minMasterVersion" }}
+  min_master_version = {{ . }}{{end}}{{ with $cluster.GetStringValue "spec" "loggingService" }}
+  logging_service = {{ . }}{{end}}{{ with $cluster.GetStringValue "spec" "monitoringService" }}
+  monitoring_service = {{ . }}{{end}}{{ with $cluster.GetInt "spec" "initialNodeCount" }}
+  initial_node_count = {{ . }}{{end}}{{ if $cluster.GetBool "metadata" "annotations" "cnrm.cloud.google.com/remove-default-node-pool" }}
+  remove_default_node_pool = true{{end}}{{ with $cluster.GetStringMapFromObject "metadata" "labels" }}
+
+  resource_labels = { {{- range $name, $value := . }}
+    "{{ $name }}" = "{{ $value }}"{{end}}
+  }{{end}}{{ with $cluster.GetStringValue "spec" "releaseChannel" "channel" }}
+
+  release_channel {
+    channel = {{ . }}
+  }{{end}}{{ with or ($cluster.GetStringValue "spec" "workloadIdentityConfig" "workloadPool") ($cluster.GetStringValue "spec" "workloadIdentityConfig" "identityNamespace") }}
+
+  workload_identity_config {
+    workload_pool = {{ . }}
+  }{{end}}{{ if $cluster.Has "spec" "ipAllocationPolicy" }}
+
+  ip_allocation_policy { {{- with $cluster.GetStringValue "spec" "ipAllocationPolicy" "clusterSecondaryRangeName" }}
+    cluster_secondary_range_name  = {{ . }}{{end}}{{ with $cluster.GetStringValue "spec" "ipAllocationPolicy" "servicesSecondaryRangeName" }}
+    services_secondary_range_name = {{ . }}{{end}}{{ with $cluster.GetStringValue "spec" "ipAllocationPolicy" "clusterIpv4CidrBlock" }}
+    cluster_ipv4_cidr_block       = {{ . }}{{end}}{{ with $cluster.GetStringValue "spec" "ipAllocationPolicy" "servicesIpv4CidrBlock" }}
+    services_ipv4_cidr_block      = {{ . }}{{end}}
+  }{{end}}{{ if $cluster.Has "spec" "privateClusterConfig" }}
+
+  private_cluster_config {
+    enable_private_nodes    = {{ $cluster.GetBool "spec" "privateClusterConfig" "enablePrivateNodes" }}
+    enable_private_endpoint = {{ $cluster.GetBool "spec" "privateClusterConfig" "enablePrivateEndpoint" }}{{ with $cluster.GetStringValue "spec" "privateClusterConfig" "masterIpv4CidrBlock" }}
+    master_ipv4_cidr_block  = {{ . }}{{end}}
+  }{{end}}{{ if $cluster.Has "spec" "masterAuthorizedNetworksConfig" }}
+
+  master_authorized_networks_config { {{- if $cluster.Has "spec" "masterAuthorizedNetworksConfig" "cidrBlocks" }}{{ range $block := $cluster.GetMasterAuthorizedNetworks }}
+    cidr_blocks {
+      cidr_block   = "{{ $block.CIDRBlock }}"{{ with $block.DisplayName }}
+      display_name = "{{ . }}"{{end}}
+    }{{end}}{{end}}
+  }{{end}}{{ if $cluster.Has "spec" "databaseEncryption" }}
+
+  database_encryption {
+    state    = {{ $cluster.GetStringValue "spec" "databaseEncryption" "state" }}{{ with $cluster.GetStringValue "spec" "databaseEncryption" "keyName" }}
+    key_name = {{ . }}{{end}}
+  }{{end}}
+}
+{{range $pool := $cluster.GetChildrenByKind "ContainerNodePool" }}{{ if $pool.ShouldCreate }}
+resource "google_container_node_pool" "{{ $pool.GetResourceName }}" {
+  name     = {{ $pool.GetResourceIDValue }}{{ with $cluster.GetProjectID }}
+  project  = {{ . }}{{end}}
+  location = {{ $pool.GetStringValue "spec" "location" }}
+  cluster  = google_container_cluster.{{ $cluster.GetResourceName }}.name{{ with $pool.GetStringsFromObject "spec" "nodeLocations" }}
+  node_locations = [{{ range . }}"{{ . }}",{{end}}]{{end}}{{ with $pool.GetStringValue "spec" "version" }}
+  version  = {{ . }}{{end}}{{ with $pool.GetInt "spec" "initialNodeCount" }}
+  initial_node_count = {{ . }}{{end}}{{ with $pool.GetInt "spec" "nodeCount" }}
+  node_count = {{ . }}{{end}}{{ with $pool.GetInt "spec" "maxPodsPerNode" }}
+  max_pods_per_node = {{ . }}{{end}}{{ if $pool.Has "spec" "autoscaling" }}
+
+  autoscaling {
+    min_node_count = {{ $pool.GetInt "spec" "autoscaling" "minNodeCount" }}
+    max_node_count = {{ $pool.GetInt "spec" "autoscaling" "maxNodeCount" }}
+  }{{end}}{{ if $pool.Has "spec" "management" }}
+
+  management {
+    auto_repair  = {{ $pool.GetBool "spec" "management" "autoRepair" }}
+    auto_upgrade = {{ $pool.GetBool "spec" "management" "autoUpgrade" }}
+  }{{end}}{{ if $pool.Has "spec" "nodeConfig" }}
+
+  node_config { {{- with $pool.GetStringValue "spec" "nodeConfig" "machineType" }}
+    machine_type = {{ . }}{{end}}{{ with $pool.GetInt "spec" "nodeConfig" "diskSizeGb" }}
+    disk_size_gb = {{ . }}{{end}}{{ with $pool.GetStringValue "spec" "nodeConfig" "diskType" }}
+    disk_type    = {{ . }}{{end}}{{ with $pool.GetStringValue "spec" "nodeConfig" "imageType" }}
+    image_type   = {{ . }}{{end}}{{ if $pool.GetBool "spec" "nodeConfig" "preemptible" }}
+    preemptible  = true{{end}}{{ with $pool.References.IAMServiceAccount }}{{ if .ShouldCreate }}
+    service_account = google_service_account.{{ .GetResourceName }}.email{{end}}{{ else }}{{ with $pool.GetStringValue "spec" "nodeConfig" "serviceAccountRef" "external" }}
+    service_account = {{ . }}{{end}}{{end}}{{ with $pool.GetStringsFromObject "spec" "nodeConfig" "oauthScopes" }}
+    oauth_scopes = [{{ range . }}
+      "{{ . }}",{{end}}
+    ]{{end}}{{ with $pool.GetStringsFromObject "spec" "nodeConfig" "tags" }}
+    tags = [{{ range . }}"{{ . }}",{{end}}]{{end}}{{ with $pool.GetStringMapFromObject "spec" "nodeConfig" "labels" }}
+
+    labels = { {{- range $name, $value := . }}
+      "{{ $name }}" = "{{ $value }}"{{end}}
+    }{{end}}
+  }{{end}}
+}
+{{end}}{{end}}{{end}}{{end}}
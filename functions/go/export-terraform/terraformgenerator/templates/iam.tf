@@ -3,12 +3,12 @@ module "{{ $ref.GetResourceName }}-iam" {
   source  = "terraform-google-modules/iam/google//modules/organizations_iam"
   version = "~> 7.4"
 
-  organizations = ["{{ $ref.Name }}"]
+  organizations = [{{ $ref.GetTerraformId false }}]
 
   bindings = {
     {{ range $role, $binding := $ref.GetIAMBindings }}
     "{{ $role }}" = [{{ range $member := $binding.Members }}
-      "{{ $member.Member }}",{{ end }}
+      {{ $member.Member }},{{ end }}
     ]
     {{ end }}
   }
@@ -24,7 +24,7 @@ module "{{ $ref.GetResourceName }}-iam" {
   bindings = {
     {{ range $role, $binding := $ref.GetIAMBindings }}
     "{{ $role }}" = [{{ range $member := $binding.Members }}
-      "{{ $member.Member }}",{{ end }}
+      {{ $member.Member }},{{ end }}
     ]
     {{ end }}
   }
@@ -33,7 +33,7 @@ module "{{ $ref.GetResourceName }}-iam" {
 {{end}}{{end}}{{range $ref := .IAMAuditConfig}}
 resource "google_organization_iam_audit_config" "org_config" {
   org_id  = {{ $ref.Parent.GetTerraformId false}}
-  service = "{{ .GetStringFromObject "spec" "service" }}"
+  service = {{ .GetStringValue "spec" "service" }}
 {{ range $cfg := $ref.GetIAMAuditLogConfigs }}
   audit_log_config {
       log_type = "{{ $cfg.LogType }}"{{ with $cfg.ExemptedMembers}}
@@ -0,0 +1,6 @@
+{{range $import := .GetImports}}{{ if $import.ID }}
+import {
+  to = {{ $import.Address }}
+  id = "{{ $import.ID }}"
+}
+{{end}}{{end}}
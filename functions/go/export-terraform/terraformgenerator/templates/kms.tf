@@ -0,0 +1,25 @@
+{{range $keyRing := .KMSKeyRing}}{{ if $keyRing.ShouldCreate }}
+resource "google_kms_key_ring" "{{ $keyRing.GetResourceName }}" {
+  name     = {{ $keyRing.GetResourceIDValue }}{{ with $keyRing.GetProjectID }}
+  project  = {{ . }}{{end}}
+  location = {{ $keyRing.GetStringValue "spec" "location" }}
+}
+{{range $key := $keyRing.GetChildrenByKind "KMSCryptoKey" }}{{ if $key.ShouldCreate }}
+resource "google_kms_crypto_key" "{{ $key.GetResourceName }}" {
+  name     = {{ $key.GetResourceIDValue }}
+  key_ring = google_kms_key_ring.{{ $keyRing.GetResourceName }}.id{{ with $key.GetStringValue "spec" "purpose" }}
+  purpose  = {{ . }}{{end}}{{ with $key.GetStringValue "spec" "rotationPeriod" }}
+  rotation_period = {{ . }}{{end}}{{ if $key.GetBool "spec" "importOnly" }}
+  import_only = true{{end}}{{ if $key.GetBool "spec" "skipInitialVersionCreation" }}
+  skip_initial_version_creation = true{{end}}{{ if $key.Has "spec" "versionTemplate" }}
+
+  version_template {
+    algorithm        = {{ $key.GetStringValue "spec" "versionTemplate" "algorithm" }}{{ with $key.GetStringValue "spec" "versionTemplate" "protectionLevel" }}
+    protection_level = {{ . }}{{end}}
+  }{{end}}{{ with $key.GetStringMapFromObject "metadata" "labels" }}
+
+  labels = { {{- range $name, $value := . }}
+    "{{ $name }}" = "{{ $value }}"{{end}}
+  }{{end}}
+}
+{{end}}{{end}}{{end}}{{end}}
@@ -4,7 +4,7 @@ module "logsink-{{ $logsink.GetResourceName }}" {
   version = "~> 7.3.0"
 
   destination_uri      = module.{{with or $logsink.References.BigQueryDataset $logsink.References.PubSubTopic $logsink.References.StorageBucket $logsink.References.LoggingLogBucket }}{{.GetResourceName}}{{end}}-destination.destination_uri
-  log_sink_name        = "{{ $logsink.GetDisplayName }}"
+  log_sink_name        = {{ $logsink.GetDisplayNameValue }}
   parent_resource_id   = {{ $logsink.GetOrganization.GetTerraformId false }}
   parent_resource_type = "organization"
   include_children     = true
@@ -14,19 +14,19 @@ module "{{ .GetResourceName }}-destination" {
   source  = "terraform-google-modules/log-export/google//modules/bigquery"
   version = "~> 7.3.0"
 
-  project_id               = module.{{ .Parent.GetResourceName }}.project_id
-  dataset_name             = "{{ .GetResourceName }}"
+  project_id               = {{ .GetProjectID }}
+  dataset_name             = {{ .GetResourceNameValue }}
   log_sink_writer_identity = module.logsink-{{ $logsink.GetResourceName }}.writer_identity{{ with .GetInt "spec" "defaultTableExpirationMs" }}
-  expiration_days          = "{{ . | msToDays }}"{{end}}{{ with .GetStringFromObject "spec" "location" }}
-  location                 = "{{.}}"{{end}}
+  expiration_days          = "{{ . | msToDays }}"{{end}}{{ with .GetStringValue "spec" "location" }}
+  location                 = {{.}}{{end}}
 }
 {{end}}{{with $logsink.References.PubSubTopic }}
 module "{{ .GetResourceName }}-destination" {
   source  = "terraform-google-modules/log-export/google//modules/pubsub"
   version = "~> 7.3.0"
 
-  project_id               = module.{{ .Parent.GetResourceName }}.project_id
-  topic_name               = "{{ .GetResourceName }}"
+  project_id               = {{ .GetProjectID }}
+  topic_name               = {{ .GetResourceNameValue }}
   log_sink_writer_identity = module.logsink-{{ $logsink.GetResourceName }}.writer_identity
 }
 {{end}}{{with $logsink.References.StorageBucket }}
@@ -34,12 +34,12 @@ module "{{ .GetResourceName }}-destination" {
   source  = "terraform-google-modules/log-export/google//modules/storage"
   version = "~> 7.3.0"
 
-  project_id                  = module.{{ .Parent.GetResourceName }}.project_id
-  storage_bucket_name         = "{{ .GetResourceName }}"
+  project_id                  = {{ .GetProjectID }}
+  storage_bucket_name         = {{ .GetResourceNameValue }}
   log_sink_writer_identity    = module.logsink-{{ $logsink.GetResourceName }}.writer_identity
-  uniform_bucket_level_access = {{ .GetBool "spec" "uniformBucketLevelAccess" }}{{ with .GetStringFromObject "spec" "location" }}
-  location                    = "{{.}}"{{end}}{{ with .GetStringFromObject "spec" "storageClass" }}
-  storage_class               = "{{.}}"{{end}}{{ if .GetInt "spec" "retentionPolicy" "retentionPeriod"}}
+  uniform_bucket_level_access = {{ .GetBool "spec" "uniformBucketLevelAccess" }}{{ with .GetStringValue "spec" "location" }}
+  location                    = {{.}}{{end}}{{ with .GetStringValue "spec" "storageClass" }}
+  storage_class               = {{.}}{{end}}{{ if .GetInt "spec" "retentionPolicy" "retentionPeriod"}}
   retention_policy = {
     retention_period_days = {{ .GetInt "spec" "retentionPolicy" "retentionPeriod" | sToDays }},
     is_locked             = {{ .GetBool "spec" "retentionPolicy" "isLocked" }}
@@ -50,9 +50,9 @@ module "{{ .GetResourceName }}-destination" {
   source  = "terraform-google-modules/log-export/google//modules/logbucket"
   version = "~> 7.4.1"
 
-  project_id               = module.{{ .Parent.GetResourceName }}.project_id
-  name                     = "{{ .GetResourceName }}"{{ with .GetStringFromObject "spec" "location" }}
-  location                 = "{{.}}"{{end}}{{ if .GetInt "spec" "retentionDays" }}
+  project_id               = {{ .GetProjectID }}
+  name                     = {{ .GetResourceNameValue }}{{ with .GetStringValue "spec" "location" }}
+  location                 = {{.}}{{end}}{{ if .GetInt "spec" "retentionDays" }}
   retention_days           = {{ .GetInt "spec" "retentionDays" }}{{end}}
   log_sink_writer_identity = module.logsink-{{ $logsink.GetResourceName }}.writer_identity
 }
@@ -4,30 +4,30 @@ module "{{ $vpc.GetResourceName }}" {
     source  = "terraform-google-modules/network/google"
     version = "~> 5.0"
 
-    project_id   = module.{{ .Parent.GetResourceName }}.project_id
-    network_name = "{{ $vpc.GetResourceName }}"{{ with .GetStringFromObject "spec" "routingMode" }}
-    routing_mode = "{{ . }}"{{end}}{{ with .GetStringFromObject "spec" "description" }}
-    description  = "{{ . }}"{{end}}
+    project_id   = {{ .GetProjectID }}
+    network_name = {{ $vpc.GetResourceNameValue }}{{ with .GetStringValue "spec" "routingMode" }}
+    routing_mode = {{ . }}{{end}}{{ with .GetStringValue "spec" "description" }}
+    description  = {{ . }}{{end}}
 
     subnets = [
        {{range $subnet := $vpc.GetChildrenByKind "ComputeSubnetwork" }}
         {
-            subnet_name           = "{{ $subnet.GetResourceName }}"
-            subnet_ip             = "{{ $subnet.GetStringFromObject "spec" "ipCidrRange" }}"
-            subnet_region         = "{{ $subnet.GetStringFromObject "spec" "region" }}"{{ with $subnet.GetBool "spec" "privateIpGoogleAccess" }}
+            subnet_name           = {{ $subnet.GetResourceNameValue }}
+            subnet_ip             = {{ $subnet.GetStringValue "spec" "ipCidrRange" }}
+            subnet_region         = {{ $subnet.GetStringValue "spec" "region" }}{{ with $subnet.GetBool "spec" "privateIpGoogleAccess" }}
             subnet_private_access = {{ . }}{{end}}{{ if $subnet.GetStringFromObject "spec" "logConfig" "aggregationInterval" }}
             subnet_flow_logs      = true{{ with $subnet.GetFloat "spec" "logConfig" "flowSampling" }}
-            subnet_flow_logs_sampling = "{{ . }}"{{end}}{{ with $subnet.GetStringFromObject "spec" "logConfig" "metadata" }}
-            subnet_flow_logs_metadata = "{{ . }}"{{end}}
-            subnet_flow_logs_interval = "{{ $subnet.GetStringFromObject "spec" "logConfig" "aggregationInterval" }}"{{end}}
+            subnet_flow_logs_sampling = "{{ . }}"{{end}}{{ with $subnet.GetStringValue "spec" "logConfig" "metadata" }}
+            subnet_flow_logs_metadata = {{ . }}{{end}}
+            subnet_flow_logs_interval = {{ $subnet.GetStringValue "spec" "logConfig" "aggregationInterval" }}{{end}}
         },{{end}}
     ]
     {{if $vpc.GetChildrenByKind "ComputeRoute"}}
     routes = [
       {{range $route := $vpc.GetChildrenByKind "ComputeRoute" }}{
-        name = "{{ $route.GetResourceName }}"{{ with $route.GetStringFromObject "spec" "description" }}
-        description = "{{ . }}"{{end}}{{ with $route.GetStringFromObject "spec" "destRange" }}
-        destination_range = "{{ . }}"{{end}}{{ with $route.GetInt "spec" "priority" }}
+        name = {{ $route.GetResourceNameValue }}{{ with $route.GetStringValue "spec" "description" }}
+        description = {{ . }}{{end}}{{ with $route.GetStringValue "spec" "destRange" }}
+        destination_range = {{ . }}{{end}}{{ with $route.GetInt "spec" "priority" }}
         priority = "{{ . }}"{{end}}{{ with $route.GetStringFromObject "spec" "nextHopGateway" }}{{if eq . "default-internet-gateway"}}
         next_hop_internet = "true"{{end}}{{end}}{{ with $route.GetStringsFromObject "spec" "tags" }}
         tags = "{{ . | strSliceToCommaSepStr }}"{{end}}
@@ -36,10 +36,10 @@ module "{{ $vpc.GetResourceName }}" {
 }
 # Firewall Rules{{range $fw := $vpc.GetChildrenByKind "ComputeFirewall" }}
 resource "google_compute_firewall" "{{ $fw.GetResourceName }}" {
-  name      = "{{ $fw.GetResourceName }}"
+  name      = {{ $fw.GetResourceNameValue }}
   network   = module.{{ $vpc.GetResourceName }}.network_name
-  project   = module.{{ $vpc.Parent.GetResourceName }}.project_id{{ with $fw.GetStringFromObject "spec" "direction" }}
-  direction = "{{ . }}"{{end}}{{ with $fw.GetInt "spec" "priority" }}
+  project   = {{ $vpc.GetProjectID }}{{ with $fw.GetStringValue "spec" "direction" }}
+  direction = {{ . }}{{end}}{{ with $fw.GetInt "spec" "priority" }}
   priority  = {{.}}{{end}}
 {{ if $fw.GetBool "spec" "enableLogging" }}
   log_config {
@@ -57,31 +57,31 @@ resource "google_compute_firewall" "{{ $fw.GetResourceName }}" {
 }{{end}}
 # NAT Router and config{{range $router := $vpc.GetChildrenByKind "ComputeRouter" }}
 resource "google_compute_router" "{{ $router.GetResourceName }}" {
-  name    = "{{ $router.GetResourceName }}"
-  project = module.{{ $vpc.Parent.GetResourceName }}.project_id
-  region  = "{{ $router.GetStringFromObject "spec" "region" }}"
+  name    = {{ $router.GetResourceNameValue }}
+  project = {{ $vpc.GetProjectID }}
+  region  = {{ $router.GetStringValue "spec" "region" }}
   network = module.{{ $vpc.GetResourceName }}.network_self_link
 }
 {{range $routerNat := $router.GetChildrenByKind "ComputeRouterNAT" }}
 resource "google_compute_router_nat" "{{ $routerNat.GetResourceName }}" {
-  name                               = "{{ $routerNat.GetResourceName }}"
-  project                            = module.{{ $vpc.Parent.GetResourceName }}.project_id
+  name                               = {{ $routerNat.GetResourceNameValue }}
+  project                            = {{ $vpc.GetProjectID }}
   router                             = google_compute_router.{{ $router.GetResourceName }}.name
-  region                             = "{{ $routerNat.GetStringFromObject "spec" "region" }}" {{ with $routerNat.GetStringFromObject "spec" "natIpAllocateOption" }}
-  nat_ip_allocate_option             = "{{ . }}"{{end}}
-  nat_ips                            = google_compute_address.{{ $routerNat.References.ComputeAddress.GetResourceName }}.*.self_link {{ with $routerNat.GetStringFromObject "spec" "sourceSubnetworkIpRangesToNat" }}
-  source_subnetwork_ip_ranges_to_nat = "{{ . }}"{{end}}
+  region                             = {{ $routerNat.GetStringValue "spec" "region" }} {{ with $routerNat.GetStringValue "spec" "natIpAllocateOption" }}
+  nat_ip_allocate_option             = {{ . }}{{end}}
+  nat_ips                            = google_compute_address.{{ $routerNat.References.ComputeAddress.GetResourceName }}.*.self_link {{ with $routerNat.GetStringValue "spec" "sourceSubnetworkIpRangesToNat" }}
+  source_subnetwork_ip_ranges_to_nat = {{ . }}{{end}}
 {{ if $routerNat.GetBool "spec" "logConfig" "enable" }}
-  log_config { {{ with $routerNat.GetStringFromObject "spec" "logConfig" "filter" }}
-    filter = "{{ . }}" {{ end }}
+  log_config { {{ with $routerNat.GetStringValue "spec" "logConfig" "filter" }}
+    filter = {{ . }} {{ end }}
     enable = true
   }{{ end }}
 }
 {{with $routerNat.References.ComputeAddress }}
 resource "google_compute_address" "{{ .GetResourceName }}" {
-  project = module.{{ $vpc.Parent.GetResourceName }}.project_id
-  name    = "{{ .GetResourceName }}"
-  region  = "{{ .GetStringFromObject "spec" "location" }}"
+  project = {{ $vpc.GetProjectID }}
+  name    = {{ .GetResourceNameValue }}
+  region  = {{ .GetStringValue "spec" "location" }}
 }{{end}}{{end}}{{end}}
 {{range $svcNet := $vpc.GetChildrenByKind "ServiceNetworkingConnection" }}
 # Service Networking for Cloud SQL & other services
@@ -92,11 +92,11 @@ resource "google_service_networking_connection" "{{ $svcNet.GetResourceName }}"
 }
 {{with $svcNet.References.ComputeAddress }}
 resource "google_compute_global_address" "{{ .GetResourceName }}" {
-  name          = "{{ .GetResourceName }}"
-  project       = module.{{ $vpc.Parent.GetResourceName }}.project_id{{ with .GetStringFromObject "spec" "purpose" }}
-  purpose       = "{{ . }}" {{end}}{{ with .GetStringFromObject "spec" "addressType" }}
-  address_type  = "{{ . }}"{{end}}{{ with .GetStringFromObject "spec" "address" }}
-  address       = "{{ . }}"{{ end }}{{ with .GetInt "spec" "prefixLength" }}
+  name          = {{ .GetResourceNameValue }}
+  project       = {{ $vpc.GetProjectID }}{{ with .GetStringValue "spec" "purpose" }}
+  purpose       = {{ . }} {{end}}{{ with .GetStringValue "spec" "addressType" }}
+  address_type  = {{ . }}{{end}}{{ with .GetStringValue "spec" "address" }}
+  address       = {{ . }}{{ end }}{{ with .GetInt "spec" "prefixLength" }}
   prefix_length = "{{ . }}"{{ end }}
   network       = module.{{ $vpc.GetResourceName }}.network_self_link
 }
@@ -3,8 +3,8 @@ module "{{ $project.GetResourceName }}" {
   source  = "terraform-google-modules/project-factory/google"
   version = "~> 12.0"
 
-  name       = "{{ $project.GetDisplayName }}"{{ if ne $project.GetDisplayName $project.GetResourceName }}
-  project_id = "{{ $project.GetResourceName }}"{{end}}
+  name       = {{ $project.GetDisplayNameValue }}{{ if ne $project.GetDisplayName $project.GetResourceName }}
+  project_id = {{ $project.GetResourceNameValue }}{{end}}
   org_id     = {{ $project.GetOrganization.GetTerraformId false }}{{if eq $project.Parent.Kind "Folder"}}
   folder_id  = {{ $project.Parent.GetTerraformId false }}{{end}}
 {{ if $project.IsSVPCHost }}
@@ -0,0 +1,9 @@
+{{range $sa := .IAMServiceAccount}}{{ if $sa.ShouldCreate }}
+resource "google_service_account" "{{ $sa.GetResourceName }}" {
+  account_id   = {{ $sa.GetResourceIDValue }}{{ with $sa.GetProjectID }}
+  project      = {{ . }}{{end}}{{ with $sa.GetStringValue "spec" "displayName" }}
+  display_name = {{ . }}{{end}}{{ with $sa.GetStringValue "spec" "description" }}
+  description  = {{ . }}{{end}}{{ if $sa.GetBool "spec" "disabled" }}
+  disabled     = true{{end}}
+}
+{{end}}{{end}}
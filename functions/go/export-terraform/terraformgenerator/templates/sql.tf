@@ -0,0 +1,63 @@
+{{range $instance := .SQLInstance}}{{ if $instance.ShouldCreate }}
+resource "google_sql_database_instance" "{{ $instance.GetResourceName }}" {
+  name             = {{ $instance.GetResourceIDValue }}{{ with $instance.GetProjectID }}
+  project          = {{ . }}{{end}}
+  database_version = {{ $instance.GetStringValue "spec" "databaseVersion" }}{{ with $instance.GetStringValue "spec" "region" }}
+  region           = {{ . }}{{end}}{{ with $instance.References.KMSCryptoKey }}{{ if .ShouldCreate }}
+  encryption_key_name = google_kms_crypto_key.{{ .GetResourceName }}.id{{end}}{{ else }}{{ with $instance.GetStringValue "spec" "encryptionKMSCryptoKeyRef" "external" }}
+  encryption_key_name = {{ . }}{{end}}{{end}}
+
+  settings {
+    tier = {{ $instance.GetStringValue "spec" "settings" "tier" }}{{ with $instance.GetStringValue "spec" "settings" "availabilityType" }}
+    availability_type = {{ . }}{{end}}{{ with $instance.GetStringValue "spec" "settings" "activationPolicy" }}
+    activation_policy = {{ . }}{{end}}{{ with $instance.GetInt "spec" "settings" "diskSize" }}
+    disk_size         = {{ . }}{{end}}{{ with $instance.GetStringValue "spec" "settings" "diskType" }}
+    disk_type         = {{ . }}{{end}}{{ with $instance.GetStringMapFromObject "metadata" "labels" }}
+
+    user_labels = { {{- range $name, $value := . }}
+      "{{ $name }}" = "{{ $value }}"{{end}}
+    }{{end}}{{ if $instance.Has "spec" "settings" "backupConfiguration" }}
+
+    backup_configuration {
+      enabled = {{ $instance.GetBool "spec" "settings" "backupConfiguration" "enabled" }}{{ with $instance.GetStringValue "spec" "settings" "backupConfiguration" "startTime" }}
+      start_time = {{ . }}{{end}}{{ if $instance.GetBool "spec" "settings" "backupConfiguration" "binaryLogEnabled" }}
+      binary_log_enabled = true{{end}}{{ if $instance.GetBool "spec" "settings" "backupConfiguration" "pointInTimeRecoveryEnabled" }}
+      point_in_time_recovery_enabled = true{{end}}
+    }{{end}}{{ if $instance.Has "spec" "settings" "ipConfiguration" }}
+
+    ip_configuration {
+      ipv4_enabled = {{ $instance.GetBool "spec" "settings" "ipConfiguration" "ipv4Enabled" }}{{ with $instance.References.ComputeNetwork }}{{ if .ShouldCreate }}
+      private_network = module.{{ .GetResourceName }}.network_self_link{{end}}{{ else }}{{ with $instance.GetStringValue "spec" "settings" "ipConfiguration" "privateNetworkRef" "external" }}
+      private_network = {{ . }}{{end}}{{end}}{{ if $instance.GetBool "spec" "settings" "ipConfiguration" "requireSsl" }}
+      require_ssl = true{{end}}{{ if $instance.Has "spec" "settings" "ipConfiguration" "authorizedNetworks" }}{{ range $network := $instance.GetSQLAuthorizedNetworks }}
+
+      authorized_networks { {{- with $network.Name }}
+        name  = "{{ . }}"{{end}}
+        value = "{{ $network.Value }}"
+      }{{end}}{{end}}
+    }{{end}}{{ if $instance.Has "spec" "settings" "databaseFlags" }}{{ range $flag := $instance.GetDatabaseFlags }}
+
+    database_flags {
+      name  = "{{ $flag.Name }}"
+      value = "{{ $flag.Value }}"
+    }{{end}}{{end}}
+  }
+}
+{{range $database := $instance.GetChildrenByKind "SQLDatabase" }}{{ if $database.ShouldCreate }}
+resource "google_sql_database" "{{ $database.GetResourceName }}" {
+  name     = {{ $database.GetResourceIDValue }}{{ with $instance.GetProjectID }}
+  project  = {{ . }}{{end}}
+  instance = google_sql_database_instance.{{ $instance.GetResourceName }}.name{{ with $database.GetStringValue "spec" "charset" }}
+  charset  = {{ . }}{{end}}{{ with $database.GetStringValue "spec" "collation" }}
+  collation = {{ . }}{{end}}
+}
+{{end}}{{end}}{{range $user := $instance.GetChildrenByKind "SQLUser" }}{{ if $user.ShouldCreate }}
+resource "google_sql_user" "{{ $user.GetResourceName }}" {
+  name     = {{ $user.GetResourceIDValue }}{{ with $instance.GetProjectID }}
+  project  = {{ . }}{{end}}
+  instance = google_sql_database_instance.{{ $instance.GetResourceName }}.name{{ with $user.GetStringValue "spec" "host" }}
+  host     = {{ . }}{{end}}{{ with $user.GetStringValue "spec" "type" }}
+  type     = {{ . }}{{end}}{{ with $user.GetVariable }}
+  password = var.{{ .Name }}{{end}}
+}
+{{end}}{{end}}{{end}}{{end}}
@@ -1,7 +1,7 @@
 {{range $variable := .Variables}}
 variable "{{ $variable.Name }}" {
   description = "{{ $variable.Description }}"
-  type        = string
-  default     = "{{ $variable.Default }}"
+  type        = string{{ with $variable.Default }}
+  default     = "{{ . }}"{{end}}
 }
 {{end}}
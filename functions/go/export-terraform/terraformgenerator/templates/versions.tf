@@ -1,5 +1,5 @@
 terraform {
-  required_version = ">=0.13"
+  required_version = "{{ if .Imports }}>=1.5{{ else }}>=0.13{{ end }}"
 
   required_providers {
     google = {
@@ -37,7 +37,8 @@ func (rs *terraformResources) getHCL() (map[string]string, error) {
 	groupedResources := rs.getGrouped()
 
 	data := make(map[string]string)
-	resourceFiles := []string{"folders.tf", "iam.tf", "projects.tf", "log-export.tf", "network.tf"}
+	resourceFiles := []string{"folders.tf", "iam.tf", "projects.tf", "log-export.tf", "network.tf",
+		"service-accounts.tf", "kms.tf", "gke.tf", "sql.tf"}
 	for _, file := range resourceFiles {
 		err := addFile(tmpl, file, groupedResources, data)
 		if err != nil {
@@ -47,6 +48,9 @@ func (rs *terraformResources) getHCL() (map[string]string, error) {
 
 	// only add other files if resource files exist
 	metaFiles := []string{"README.md", "versions.tf", "variables.tf"}
+	if rs.Imports {
+		metaFiles = append(metaFiles, "imports.tf", "imports.md")
+	}
 	if len(data) > 0 {
 		for _, file := range metaFiles {
 			err := addFile(tmpl, file, rs, data)
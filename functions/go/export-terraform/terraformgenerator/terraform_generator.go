@@ -16,6 +16,7 @@ package terraformgenerator
 
 import (
 	"embed"
+	"fmt"
 	"strings"
 
 	sdk "github.com/GoogleContainerTools/kpt-functions-catalog/thirdparty/kyaml/fnsdk"
@@ -30,10 +31,20 @@ var templates embed.FS
 const (
 	kccAPI         = "cnrm.cloud.google.com"
 	skipAnnotation = "cnrm.cloud.google.com/ignore-clusterless"
+	// strictKey is the functionConfig data key which fails the export when
+	// any resource or reference is dropped
+	strictKey = "strict"
+	// importsKey is the functionConfig data key which adds the import blocks
+	// of the existing resources to the Terraform configuration
+	importsKey = "imports"
 )
 
 func Processor(rl *sdk.ResourceList) error {
-	var resources terraformResources
+	resources := terraformResources{
+		Imports:  isEnabled(rl.FunctionConfig, importsKey),
+		packages: findPackages(rl.Items),
+	}
+	report := &exportReport{strict: isEnabled(rl.FunctionConfig, strictKey)}
 	supportedKinds := map[string]bool{
 		"Folder":                      true,
 		"Organization":                true,
@@ -56,19 +67,30 @@ func Processor(rl *sdk.ResourceList) error {
 		"ComputeFirewall":             true,
 		"LoggingLogBucket":            true,
 		"ComputeSharedVPCHostProject": true,
+		"ContainerCluster":            true,
+		"ContainerNodePool":           true,
+		"SQLInstance":                 true,
+		"SQLDatabase":                 true,
+		"SQLUser":                     true,
+		"KMSKeyRing":                  true,
+		"KMSCryptoKey":                true,
+		"IAMServiceAccount":           true,
 	}
 
 	for _, item := range rl.Items {
 		if !strings.Contains(item.APIVersion(), kccAPI) {
 			continue
 		}
+		report.total++
 
 		shouldSkip := item.Annotation(skipAnnotation)
 		if shouldSkip == "true" {
+			report.ignore(item)
 			continue
 		}
 
 		if _, ok := supportedKinds[item.Kind()]; !ok {
+			report.unsupportedKind(item)
 			continue
 		}
 
@@ -86,8 +108,26 @@ func Processor(rl *sdk.ResourceList) error {
 	}
 
 	configMap := makeConfigMap(data)
+	if err := rl.UpsertObjectToItems(configMap, nil, false); err != nil {
+		return err
+	}
 
-	return rl.UpsertObjectToItems(configMap, nil, false)
+	report.check(&resources)
+	rl.Results = append(rl.Results, report.results...)
+	rl.Results = append(rl.Results, report.summary())
+	if report.strict && report.dropped() > 0 {
+		return fmt.Errorf("%d resources or references were dropped from the Terraform configuration", report.dropped())
+	}
+	return nil
+}
+
+// isEnabled returns whether the option is enabled in the functionConfig
+func isEnabled(fc *sdk.KubeObject, key string) bool {
+	if fc == nil {
+		return false
+	}
+	value, _, _ := fc.GetString("data", key)
+	return strings.ToLower(value) == "true"
 }
 
 func makeConfigMap(data map[string]string) interface{} {
@@ -33,8 +33,9 @@ const (
 )
 
 type TerraformTest struct {
-	Name string
-	Mode string
+	Name     string
+	Mode     string
+	FnConfig string
 }
 
 var testCases = []TerraformTest{
@@ -70,6 +71,19 @@ var testCases = []TerraformTest{
 		Name: "multi-network",
 		Mode: "terraform",
 	},
+	{
+		Name: "gke",
+		Mode: "terraform",
+	},
+	{
+		Name: "sql",
+		Mode: "terraform",
+	},
+	{
+		Name:     "imports",
+		Mode:     "terraform",
+		FnConfig: "fn-config.yaml",
+	},
 }
 
 func TestTerraformGeneration(t *testing.T) {
@@ -78,7 +92,12 @@ func TestTerraformGeneration(t *testing.T) {
 			require := require.New(t)
 			inDir := path.Join("..", testDir, tt.Name, "input")
 
-			actualRL, err := testutil.ResourceListFromDirectory(inDir, "")
+			var fnConfig string
+			if tt.FnConfig != "" {
+				fnConfig = path.Join("..", testDir, tt.Name, tt.FnConfig)
+			}
+
+			actualRL, err := testutil.ResourceListFromDirectory(inDir, fnConfig)
 			require.NoError(err)
 			var expectedRL *sdk.ResourceList
 
@@ -165,10 +184,14 @@ func TestTerraformGeneration(t *testing.T) {
 
 func getTerraformFromDir(sourceDir string) (map[string]string, error) {
 	files, err := filepath.Glob(path.Join(sourceDir, "*.tf"))
-	files = append(files, "README.md")
 	if err != nil {
 		return nil, err
 	}
+	docs, err := filepath.Glob(path.Join(sourceDir, "*.md"))
+	if err != nil {
+		return nil, err
+	}
+	files = append(files, docs...)
 
 	data := make(map[string]string)
 	for _, file := range files {
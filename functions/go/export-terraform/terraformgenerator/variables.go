@@ -14,6 +14,12 @@
 
 package terraformgenerator
 
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
 type variable struct {
 	Name        string
 	Description string
@@ -26,11 +32,32 @@ type resourceVariable struct {
 	underlying *variable
 }
 
+// externalRefPaths are the fields referencing the resources of each kind
+// outside of the package, which refer to the variable of their setter
+var externalRefPaths = map[string][][]string{
+	"Organization":   {{"spec", "organizationRef", "external"}, {"spec", "resourceRef", "external"}},
+	"BillingAccount": {{"spec", "billingAccountRef", "external"}},
+	"Folder":         {{"spec", "folderRef", "external"}, {"spec", "resourceRef", "external"}},
+}
+
 // Find common values and make them into variables
 func (rs *terraformResources) makeVariables() {
 	rs.Variables = make(map[string]*variable)
+	rs.makeSetterVariables()
 
 	resources := rs.getGrouped()
+	for kind := range externalRefPaths {
+		for _, resource := range resources[kind] {
+			if resource.ShouldCreate() {
+				continue
+			}
+			if v := resource.getReferenceVariable(); v != nil {
+				resource.variable = v
+				rs.Variables[v.Name] = v
+			}
+		}
+	}
+
 	vars := []resourceVariable{
 		{
 			kind: "Organization",
@@ -53,8 +80,259 @@ func (rs *terraformResources) makeVariables() {
 			continue
 		}
 		resource := resources[candidate.kind][0]
+		if resource.variable != nil {
+			// the variable of the setter of the references
+			if resource.variable.Description == "" {
+				resource.variable.Description = candidate.underlying.Description
+			}
+			continue
+		}
 		candidate.underlying.Default = resource.Name
 		resource.variable = candidate.underlying
 		rs.Variables[candidate.underlying.Name] = candidate.underlying
 	}
+
+	// passwords are never exported, they must be provided to Terraform instead
+	for _, user := range resources["SQLUser"] {
+		if !user.ShouldCreate() || !user.Has("spec", "password") {
+			continue
+		}
+		password := &variable{
+			Name:        fmt.Sprintf("%s_password", strings.ReplaceAll(user.GetResourceName(), "-", "_")),
+			Description: fmt.Sprintf("The password of the SQL user %s", user.GetResourceID()),
+		}
+		user.variable = password
+		rs.Variables[password.Name] = password
+	}
+
+	for _, setters := range rs.setters {
+		for _, setter := range setters {
+			if setter.variable != nil && setter.variable.Description == "" {
+				setter.variable.Description = fmt.Sprintf("The value of the %s setter", setter.name)
+			}
+		}
+	}
+}
+
+// makeSetterVariables makes a variable for each setter of each kpt package,
+// with the value of the setter in the fields of the resources as default. The
+// variables are declared once an exported field refers to them.
+func (rs *terraformResources) makeSetterVariables() {
+	rs.setters = make(map[string]map[string]*packageSetter)
+	keys := make([]string, 0, len(rs.resources))
+	for key := range rs.resources {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	var setters []*packageSetter
+	for _, key := range keys {
+		resource := rs.resources[key]
+		if !resource.ShouldCreate() {
+			continue
+		}
+		pkg := resource.getPackage()
+		if rs.setters[pkg] == nil {
+			rs.setters[pkg] = make(map[string]*packageSetter)
+		}
+		setterOf := func(name string) *packageSetter {
+			setter, found := rs.setters[pkg][name]
+			if !found {
+				setter = &packageSetter{name: name, pkg: pkg}
+				rs.setters[pkg][name] = setter
+				setters = append(setters, setter)
+			}
+			return setter
+		}
+		for _, field := range resource.setters {
+			if field.inList {
+				for _, ref := range setterRefRegex.FindAllString(field.pattern, -1) {
+					setterOf(parseSetterRef(ref).name).inLists = true
+				}
+			}
+			values, ok := setterValues(field.pattern, field.value)
+			if !ok {
+				continue
+			}
+			for name, value := range values {
+				setter := setterOf(name)
+				if !contains(setter.values, value) {
+					setter.values = append(setter.values, value)
+					sort.Strings(setter.values)
+				}
+			}
+		}
+	}
+
+	// the variables of a setter with different values in several packages
+	// are prefixed by the package
+	values := make(map[string][]string)
+	for _, setter := range setters {
+		if len(setter.values) == 1 && !contains(values[setter.name], setter.values[0]) {
+			values[setter.name] = append(values[setter.name], setter.values[0])
+		}
+	}
+	for _, setter := range setters {
+		if len(setter.values) != 1 {
+			continue
+		}
+		name := setter.name
+		if len(values[setter.name]) > 1 && setter.pkg != "" {
+			name = fmt.Sprintf("%s_%s", setter.pkg, setter.name)
+		}
+		setter.variable = &variable{
+			Name:        variableName(name),
+			Description: hclEscape(rs.packages[setter.pkg][setter.name]),
+			Default:     setter.values[0],
+		}
+	}
+}
+
+// getPackage returns the directory of the kpt package of the resource, which
+// is the closest directory with a Kptfile, or empty for the root package
+func (resource *terraformResource) getPackage() string {
+	filePath := itemPath(resource.Item)
+	pkg := ""
+	for dir := range resource.resources.packages {
+		if strings.HasPrefix(filePath, dir+"/") && len(dir) > len(pkg) {
+			pkg = dir
+		}
+	}
+	return pkg
+}
+
+// setterTemplate returns the Terraform string template of the value of a field
+// which interpolates the variables of its setters, to be embedded in a string
+// literal. It returns false unless all the setters of the field are turned
+// into variables with the values they have in the field.
+func (resource *terraformResource) setterTemplate(value string, path ...string) (string, bool) {
+	template, _, ok := resource.setterInterpolation(value, path...)
+	return template, ok
+}
+
+// setterExpression returns the Terraform expression of the value of a field
+// which refers to the variables of its setters: the variable itself when the
+// field is set by a single setter e.g. var.name, otherwise a string template
+// e.g. "${var.name}-bucket". It returns false like setterTemplate.
+func (resource *terraformResource) setterExpression(value string, path ...string) (string, bool) {
+	template, expr, ok := resource.setterInterpolation(value, path...)
+	if !ok {
+		return "", false
+	}
+	if expr != "" {
+		return expr, true
+	}
+	return fmt.Sprintf(`"%s"`, template), true
+}
+
+// setterInterpolation returns the string template of the value of a field
+// which interpolates the variables of its setters, along with the expression
+// of the variable when the field is set by a single setter
+func (resource *terraformResource) setterInterpolation(value string, path ...string) (string, string, bool) {
+	field, found := resource.setters[fieldKey(path...)]
+	if !found || field.value != value {
+		return "", "", false
+	}
+	values, ok := setterValues(field.pattern, field.value)
+	if !ok {
+		return "", "", false
+	}
+
+	pkg := resource.getPackage()
+	var template strings.Builder
+	var variables []*variable
+	var expr string
+	locs := setterRefRegex.FindAllStringIndex(field.pattern, -1)
+	last := 0
+	for _, loc := range locs {
+		ref := parseSetterRef(field.pattern[loc[0]:loc[1]])
+		v := resource.resources.getSetterVariable(pkg, ref.name)
+		if v == nil || v.Default != values[ref.name] {
+			return "", "", false
+		}
+		variables = append(variables, v)
+		expr = ref.terraform("var." + v.Name)
+		template.WriteString(hclEscape(field.pattern[last:loc[0]]))
+		fmt.Fprintf(&template, "${%s}", expr)
+		last = loc[1]
+	}
+	template.WriteString(hclEscape(field.pattern[last:]))
+	if len(locs) != 1 || locs[0][0] != 0 || locs[0][1] != len(field.pattern) {
+		expr = ""
+	}
+
+	for _, v := range variables {
+		resource.resources.Variables[v.Name] = v
+	}
+	return template.String(), expr, true
+}
+
+// getSetterVariable returns the variable of a setter of a package, unless the
+// setter has conflicting values or its variable collides with another one
+func (rs *terraformResources) getSetterVariable(pkg string, name string) *variable {
+	setter := rs.setters[pkg][name]
+	if setter == nil {
+		return nil
+	}
+	setter.used = true
+	if setter.variable == nil {
+		return nil
+	}
+	if declared, found := rs.Variables[setter.variable.Name]; found && declared != setter.variable {
+		if declared.Default != setter.variable.Default {
+			setter.collides = true
+			return nil
+		}
+		return declared
+	}
+	return setter.variable
+}
+
+// getReferenceVariable returns the variable of the setter which sets the
+// external references to the resource, if there is one
+func (resource *terraformResource) getReferenceVariable() *variable {
+	for _, child := range resource.Children {
+		if !child.ShouldCreate() {
+			continue
+		}
+		pkg := child.getPackage()
+		for _, path := range externalRefPaths[resource.Kind] {
+			field, found := child.setters[fieldKey(path...)]
+			if !found || strings.TrimSpace(field.value) != resource.Name {
+				continue
+			}
+			// only a setter setting the whole reference, e.g. ${org-id}
+			locs := setterRefRegex.FindAllStringIndex(field.pattern, -1)
+			if len(locs) != 1 || locs[0][0] != 0 || locs[0][1] != len(field.pattern) {
+				continue
+			}
+			ref := parseSetterRef(field.pattern)
+			if len(ref.transforms) > 0 {
+				continue
+			}
+			if v := child.resources.getSetterVariable(pkg, ref.name); v != nil && v.Default == field.value {
+				return v
+			}
+		}
+	}
+	return nil
+}
+
+// hclEscape escapes a value for a Terraform string literal
+func hclEscape(value string) string {
+	return strings.NewReplacer(`\`, `\\`, `"`, `\"`, "${", "$${", "%{", "%%{").Replace(value)
+}
+
+// hclString returns the Terraform string literal of a value
+func hclString(value string) string {
+	return fmt.Sprintf(`"%s"`, hclEscape(value))
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
 }
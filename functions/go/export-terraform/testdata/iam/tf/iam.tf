@@ -2,7 +2,7 @@ module "organization-iam" {
   source  = "terraform-google-modules/iam/google//modules/organizations_iam"
   version = "~> 7.4"
 
-  organizations = ["11111111111"]
+  organizations = [var.org_id]
 
   bindings = {
     
@@ -0,0 +1,4 @@
+resource "google_folder" "workloads" {
+  display_name = "Workloads"
+  parent       = "organizations/${var.org_id}"
+}
@@ -0,0 +1,71 @@
+resource "google_container_cluster" "gke-cluster" {
+  name     = "gke-cluster"
+  project  = module.prj-gke.project_id
+  location = "us-central1"
+  network  = module.vpc-gke.network_self_link
+  subnetwork = module.vpc-gke.subnets["us-central1/sb-gke-us-central1"].self_link
+  initial_node_count = 1
+
+  resource_labels = {
+    "env" = "dev"
+  }
+
+  release_channel {
+    channel = "REGULAR"
+  }
+
+  workload_identity_config {
+    workload_pool = "prj-gke.svc.id.goog"
+  }
+
+  ip_allocation_policy {
+    cluster_ipv4_cidr_block       = "/14"
+    services_ipv4_cidr_block      = "/20"
+  }
+
+  private_cluster_config {
+    enable_private_nodes    = true
+    enable_private_endpoint = false
+    master_ipv4_cidr_block  = "172.16.0.0/28"
+  }
+
+  master_authorized_networks_config {
+    cidr_blocks {
+      cidr_block   = "10.0.0.0/8"
+      display_name = "internal"
+    }
+  }
+}
+
+resource "google_container_node_pool" "gke-cluster-default" {
+  name     = "gke-cluster-default"
+  project  = module.prj-gke.project_id
+  location = "us-central1"
+  cluster  = google_container_cluster.gke-cluster.name
+  initial_node_count = 1
+
+  autoscaling {
+    min_node_count = 1
+    max_node_count = 3
+  }
+
+  management {
+    auto_repair  = true
+    auto_upgrade = true
+  }
+
+  node_config {
+    machine_type = "e2-standard-4"
+    disk_size_gb = 100
+    disk_type    = "pd-standard"
+    service_account = google_service_account.gke-nodes.email
+    oauth_scopes = [
+      "https://www.googleapis.com/auth/cloud-platform",
+    ]
+    tags = ["gke-node",]
+
+    labels = {
+      "pool" = "default"
+    }
+  }
+}
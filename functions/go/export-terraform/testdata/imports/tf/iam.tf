@@ -0,0 +1,14 @@
+module "workloads-iam" {
+  source  = "terraform-google-modules/iam/google//modules/folders_iam"
+  version = "~> 7.4"
+
+  folders = [google_folder.workloads.name]
+
+  bindings = {
+    
+    "roles/viewer" = [
+      "group:workloads@example.com",
+    ]
+    
+  }
+}
@@ -0,0 +1,69 @@
+import {
+  to = google_container_cluster.gke-cluster
+  id = "projects/prj-gke/locations/us-central1/clusters/gke-cluster"
+}
+
+import {
+  to = google_container_node_pool.gke-cluster-default
+  id = "projects/prj-gke/locations/us-central1/clusters/gke-cluster/nodePools/gke-cluster-default"
+}
+
+import {
+  to = google_folder.workloads
+  id = "folders/246813579024"
+}
+
+import {
+  to = google_kms_crypto_key.key-sql
+  id = "projects/prj-sql/locations/us-central1/keyRings/kr-sql/cryptoKeys/key-sql"
+}
+
+import {
+  to = google_kms_key_ring.kr-sql
+  id = "projects/prj-sql/locations/us-central1/keyRings/kr-sql"
+}
+
+import {
+  to = google_service_account.gke-nodes
+  id = "projects/prj-gke/serviceAccounts/gke-nodes@prj-gke.iam.gserviceaccount.com"
+}
+
+import {
+  to = google_sql_database.sql-main-app
+  id = "projects/prj-sql/instances/sql-main/databases/app"
+}
+
+import {
+  to = google_sql_database_instance.sql-main
+  id = "projects/prj-sql/instances/sql-main"
+}
+
+import {
+  to = google_sql_user.sql-main-app
+  id = "prj-sql/sql-main/app"
+}
+
+import {
+  to = module.prj-gke.module.project-factory.google_project.main
+  id = "prj-gke"
+}
+
+import {
+  to = module.prj-sql.module.project-factory.google_project.main
+  id = "prj-sql"
+}
+
+import {
+  to = module.vpc-gke.module.subnets.google_compute_subnetwork.subnetwork["us-central1/sb-gke-us-central1"]
+  id = "projects/prj-gke/regions/us-central1/subnetworks/sb-gke-us-central1"
+}
+
+import {
+  to = module.vpc-gke.module.vpc.google_compute_network.network
+  id = "projects/prj-gke/global/networks/vpc-gke"
+}
+
+import {
+  to = module.vpc-sql.module.vpc.google_compute_network.network
+  id = "projects/prj-sql/global/networks/vpc-sql"
+}
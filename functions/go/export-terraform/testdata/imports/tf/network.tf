@@ -0,0 +1,39 @@
+# VPC and Subnets
+module "vpc-gke" {
+    source  = "terraform-google-modules/network/google"
+    version = "~> 5.0"
+
+    project_id   = module.prj-gke.project_id
+    network_name = "vpc-gke"
+    routing_mode = "REGIONAL"
+
+    subnets = [
+       
+        {
+            subnet_name           = "sb-gke-us-central1"
+            subnet_ip             = "10.0.0.0/20"
+            subnet_region         = "us-central1"
+            subnet_private_access = true
+        },
+    ]
+    
+}
+# Firewall Rules
+# NAT Router and config
+
+# VPC and Subnets
+module "vpc-sql" {
+    source  = "terraform-google-modules/network/google"
+    version = "~> 5.0"
+
+    project_id   = module.prj-sql.project_id
+    network_name = "vpc-sql"
+    routing_mode = "REGIONAL"
+
+    subnets = [
+       
+    ]
+    
+}
+# Firewall Rules
+# NAT Router and config
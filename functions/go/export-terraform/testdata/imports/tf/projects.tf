@@ -0,0 +1,20 @@
+module "prj-gke" {
+  source  = "terraform-google-modules/project-factory/google"
+  version = "~> 12.0"
+
+  name       = "prj-gke"
+  org_id     = var.org_id
+  folder_id  = google_folder.workloads.name
+
+  billing_account = var.billing_account
+}
+
+module "prj-sql" {
+  source  = "terraform-google-modules/project-factory/google"
+  version = "~> 12.0"
+
+  name       = "prj-sql"
+  org_id     = var.org_id
+
+  billing_account = var.billing_account
+}
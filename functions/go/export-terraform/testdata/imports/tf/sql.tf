@@ -0,0 +1,45 @@
+resource "google_sql_database_instance" "sql-main" {
+  name             = "sql-main"
+  project          = module.prj-sql.project_id
+  database_version = "POSTGRES_14"
+  region           = "us-central1"
+  encryption_key_name = google_kms_crypto_key.key-sql.id
+
+  settings {
+    tier = "db-custom-2-7680"
+    availability_type = "REGIONAL"
+    disk_size         = 100
+    disk_type         = "PD_SSD"
+
+    backup_configuration {
+      enabled = true
+      start_time = "02:00"
+      point_in_time_recovery_enabled = true
+    }
+
+    ip_configuration {
+      ipv4_enabled = false
+      private_network = module.vpc-sql.network_self_link
+      require_ssl = true
+    }
+
+    database_flags {
+      name  = "log_checkpoints"
+      value = "on"
+    }
+  }
+}
+
+resource "google_sql_database" "sql-main-app" {
+  name     = "app"
+  project  = module.prj-sql.project_id
+  instance = google_sql_database_instance.sql-main.name
+  charset  = "UTF8"
+}
+
+resource "google_sql_user" "sql-main-app" {
+  name     = "app"
+  project  = module.prj-sql.project_id
+  instance = google_sql_database_instance.sql-main.name
+  password = var.sql_main_app_password
+}
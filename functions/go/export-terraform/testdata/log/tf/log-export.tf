@@ -3,7 +3,7 @@ module "logsink-123456789012-bqsink" {
   version = "~> 7.3.0"
 
   destination_uri      = module.bqlogexportdataset-destination.destination_uri
-  log_sink_name        = "123456789012-bqsink"
+  log_sink_name        = "${var.org_id}-bqsink"
   parent_resource_id   = var.org_id
   parent_resource_type = "organization"
   include_children     = true
@@ -17,7 +17,7 @@ module "bqlogexportdataset-destination" {
   dataset_name             = "bqlogexportdataset"
   log_sink_writer_identity = module.logsink-123456789012-bqsink.writer_identity
   expiration_days          = "365"
-  location                 = "US"
+  location                 = var.dataset_location
 }
 
 module "logsink-123456789012-orglogbucketsink" {
@@ -36,7 +36,7 @@ module "my-log-k8s-bucket-destination" {
   version = "~> 7.4.1"
 
   project_id               = module.prj-logging.project_id
-  name                     = "my-log-k8s-bucket"
+  name                     = var.log_bucket_name
   location                 = "global"
   retention_days           = 30
   log_sink_writer_identity = module.logsink-123456789012-orglogbucketsink.writer_identity
@@ -47,7 +47,7 @@ module "logsink-123456789012-pubsubsink" {
   version = "~> 7.3.0"
 
   destination_uri      = module.pubsub-logexport-dataset-destination.destination_uri
-  log_sink_name        = "123456789012-pubsubsink"
+  log_sink_name        = "${var.org_id}-pubsubsink"
   parent_resource_id   = var.org_id
   parent_resource_type = "organization"
   include_children     = true
@@ -58,7 +58,7 @@ module "pubsub-logexport-dataset-destination" {
   version = "~> 7.3.0"
 
   project_id               = module.prj-logging.project_id
-  topic_name               = "pubsub-logexport-dataset"
+  topic_name               = var.topic_name
   log_sink_writer_identity = module.logsink-123456789012-pubsubsink.writer_identity
 }
 
@@ -67,7 +67,7 @@ module "logsink-123456789012-storagesink" {
   version = "~> 7.3.0"
 
   destination_uri      = module.my-storage-bucket-destination.destination_uri
-  log_sink_name        = "123456789012-storagesink"
+  log_sink_name        = "${var.org_id}-storagesink"
   parent_resource_id   = var.org_id
   parent_resource_type = "organization"
   include_children     = true
@@ -78,11 +78,11 @@ module "my-storage-bucket-destination" {
   version = "~> 7.3.0"
 
   project_id                  = module.prj-logging.project_id
-  storage_bucket_name         = "my-storage-bucket"
+  storage_bucket_name         = var.storage_bucket_name
   log_sink_writer_identity    = module.logsink-123456789012-storagesink.writer_identity
   uniform_bucket_level_access = false
   location                    = "US"
-  storage_class               = "MULTI_REGIONAL"
+  storage_class               = var.storage_class
   retention_policy = {
     retention_period_days = 365,
     is_locked             = false
@@ -4,8 +4,38 @@ variable "billing_account" {
   default     = "AAAAAA-AAAAAA-AAAAAA"
 }
 
+variable "dataset_location" {
+  description = "The value of the dataset-location setter"
+  type        = string
+  default     = "US"
+}
+
+variable "log_bucket_name" {
+  description = "The value of the log-bucket-name setter"
+  type        = string
+  default     = "my-log-k8s-bucket"
+}
+
 variable "org_id" {
   description = "The organization id for the associated resources"
   type        = string
   default     = "123456789012"
 }
+
+variable "storage_bucket_name" {
+  description = "The value of the storage-bucket-name setter"
+  type        = string
+  default     = "my-storage-bucket"
+}
+
+variable "storage_class" {
+  description = "The value of the storage-class setter"
+  type        = string
+  default     = "MULTI_REGIONAL"
+}
+
+variable "topic_name" {
+  description = "The value of the topic-name setter"
+  type        = string
+  default     = "pubsub-logexport-dataset"
+}
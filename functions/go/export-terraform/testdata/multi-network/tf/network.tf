@@ -161,7 +161,7 @@ resource "google_compute_global_address" "ga-vpc-shared-dev-dev-vpc-peering-inte
   project       = module.prj-network1.project_id
   purpose       = "VPC_PEERING" 
   address_type  = "INTERNAL"
-  address       = "10.16.64.0"
+  address       = var.private_ip_cidr
   prefix_length = "21"
   network       = module.vpc-shared-dev.network_self_link
 }
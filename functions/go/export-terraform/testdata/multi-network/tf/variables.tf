@@ -9,3 +9,9 @@ variable "org_id" {
   type        = string
   default     = "123456789012"
 }
+
+variable "private_ip_cidr" {
+  description = "The value of the private-ip-cidr setter"
+  type        = string
+  default     = "10.16.64.0"
+}
@@ -4,9 +4,9 @@ module "vpc-shared-base" {
     version = "~> 5.0"
 
     project_id   = module.prj-network.project_id
-    network_name = "vpc-shared-base"
+    network_name = var.network_name
     routing_mode = "GLOBAL"
-    description  = "vpc-shared-base VPC"
+    description  = "${var.network_name} VPC"
 
     subnets = [
        
@@ -34,13 +34,13 @@ module "vpc-shared-base" {
     
     routes = [
       {
-        name = "rt-vpc-shared-base-1000-all-default-private-api"
+        name = "rt-${var.network_name}-1000-all-default-private-api"
         description = "Route through IGW to allow private google api access."
         destination_range = "199.36.153.8/30"
         priority = "1000"
         next_hop_internet = "true"
       },{
-        name = "rt-vpc-shared-base-1000-egress-internet-default"
+        name = "rt-${var.network_name}-1000-egress-internet-default"
         description = "Tag based route through IGW to access internet"
         destination_range = "0.0.0.0/0"
         priority = "1000"
@@ -51,7 +51,7 @@ module "vpc-shared-base" {
 }
 # Firewall Rules
 resource "google_compute_firewall" "vpc-shared-base-allow-iap-rdp" {
-  name      = "vpc-shared-base-allow-iap-rdp"
+  name      = "${var.network_name}-allow-iap-rdp"
   network   = module.vpc-shared-base.network_name
   project   = module.prj-network.project_id
   direction = "INGRESS"
@@ -71,7 +71,7 @@ resource "google_compute_firewall" "vpc-shared-base-allow-iap-rdp" {
   ]
 }
 resource "google_compute_firewall" "vpc-shared-base-allow-iap-ssh" {
-  name      = "vpc-shared-base-allow-iap-ssh"
+  name      = "${var.network_name}-allow-iap-ssh"
   network   = module.vpc-shared-base.network_name
   project   = module.prj-network.project_id
   direction = "INGRESS"
@@ -91,7 +91,7 @@ resource "google_compute_firewall" "vpc-shared-base-allow-iap-ssh" {
   ]
 }
 resource "google_compute_firewall" "vpc-shared-base-allow-icmp" {
-  name      = "vpc-shared-base-allow-icmp"
+  name      = "${var.network_name}-allow-icmp"
   network   = module.vpc-shared-base.network_name
   project   = module.prj-network.project_id
   direction = "INGRESS"
@@ -173,11 +173,11 @@ resource "google_service_networking_connection" "svc-net-vpc-shared-base-dev" {
 }
 
 resource "google_compute_global_address" "ga-vpc-shared-base-dev-vpc-peering-internal" {
-  name          = "ga-vpc-shared-base-dev-vpc-peering-internal"
+  name          = "ga-${var.network_name}-${var.env}-vpc-peering-internal"
   project       = module.prj-network.project_id
   purpose       = "VPC_PEERING" 
   address_type  = "INTERNAL"
-  address       = "10.16.64.0"
+  address       = var.private_ip_cidr
   prefix_length = "21"
   network       = module.vpc-shared-base.network_self_link
 }
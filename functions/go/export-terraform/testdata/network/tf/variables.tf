@@ -4,8 +4,26 @@ variable "billing_account" {
   default     = "AAAAAA-AAAAAA-AAAAAA"
 }
 
+variable "env" {
+  description = "The value of the env setter"
+  type        = string
+  default     = "dev"
+}
+
+variable "network_name" {
+  description = "The value of the network-name setter"
+  type        = string
+  default     = "vpc-shared-base"
+}
+
 variable "org_id" {
   description = "The organization id for the associated resources"
   type        = string
   default     = "123456789012"
 }
+
+variable "private_ip_cidr" {
+  description = "The value of the private-ip-cidr setter"
+  type        = string
+  default     = "10.16.64.0"
+}
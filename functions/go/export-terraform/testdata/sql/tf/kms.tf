@@ -0,0 +1,21 @@
+resource "google_kms_key_ring" "kr-sql" {
+  name     = "kr-sql"
+  project  = module.prj-sql.project_id
+  location = "us-central1"
+}
+
+resource "google_kms_crypto_key" "key-sql" {
+  name     = "key-sql"
+  key_ring = google_kms_key_ring.kr-sql.id
+  purpose  = "ENCRYPT_DECRYPT"
+  rotation_period = "7776000s"
+
+  version_template {
+    algorithm        = "GOOGLE_SYMMETRIC_ENCRYPTION"
+    protection_level = "SOFTWARE"
+  }
+
+  labels = {
+    "purpose" = "cmek"
+  }
+}
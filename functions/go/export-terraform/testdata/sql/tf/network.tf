@@ -0,0 +1,16 @@
+# VPC and Subnets
+module "vpc-sql" {
+    source  = "terraform-google-modules/network/google"
+    version = "~> 5.0"
+
+    project_id   = module.prj-sql.project_id
+    network_name = "vpc-sql"
+    routing_mode = "REGIONAL"
+
+    subnets = [
+       
+    ]
+    
+}
+# Firewall Rules
+# NAT Router and config
@@ -0,0 +1,558 @@
+// Copyright 2022 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/google/cel-go/cel"
+	"github.com/google/cel-go/ext"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	"sigs.k8s.io/kustomize/kyaml/fn/framework"
+)
+
+const (
+	// admissionGroup is the API group of the ValidatingAdmissionPolicy and
+	// the ValidatingAdmissionPolicyBinding
+	admissionGroup = "admissionregistration.k8s.io"
+
+	// the validation actions of the bindings, the violations of the bindings
+	// with the Audit action only are reported as info
+	denyAction = "Deny"
+	warnAction = "Warn"
+
+	// ignorePolicy is the failure policy ignoring the evaluation errors
+	ignorePolicy = "Ignore"
+
+	// the actions of the bindings when no param is found
+	allowAction = "Allow"
+
+	// createOperation is the operation of the admission requests, the package
+	// resources are validated as if they are created
+	createOperation = "CREATE"
+
+	// violatedPolicyPrefix and violatedBindingPrefix precede the names of the
+	// violated policy and of its binding in the message of the result items
+	violatedPolicyPrefix  = "\nviolatedPolicy: "
+	violatedBindingPrefix = "\nviolatedBinding: "
+)
+
+// admissionPolicy is a ValidatingAdmissionPolicy. The types of
+// admissionregistration.k8s.io are not available in the k8s.io/api version
+// which Gatekeeper depends on, they are declared with the fields evaluated
+// offline.
+type admissionPolicy struct {
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+	Spec              struct {
+		ParamKind        *paramKind       `json:"paramKind,omitempty"`
+		MatchConstraints *matchResources  `json:"matchConstraints,omitempty"`
+		Validations      []celValidation  `json:"validations,omitempty"`
+		FailurePolicy    *string          `json:"failurePolicy,omitempty"`
+		MatchConditions  []namedCondition `json:"matchConditions,omitempty"`
+		Variables        []namedCondition `json:"variables,omitempty"`
+	} `json:"spec"`
+}
+
+// policyBinding is a ValidatingAdmissionPolicyBinding
+type policyBinding struct {
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+	Spec              struct {
+		PolicyName        string          `json:"policyName"`
+		ParamRef          *paramRef       `json:"paramRef,omitempty"`
+		MatchResources    *matchResources `json:"matchResources,omitempty"`
+		ValidationActions []string        `json:"validationActions,omitempty"`
+	} `json:"spec"`
+}
+
+type paramKind struct {
+	APIVersion string `json:"apiVersion"`
+	Kind       string `json:"kind"`
+}
+
+type paramRef struct {
+	Name                    string                `json:"name,omitempty"`
+	Namespace               string                `json:"namespace,omitempty"`
+	Selector                *metav1.LabelSelector `json:"selector,omitempty"`
+	ParameterNotFoundAction *string               `json:"parameterNotFoundAction,omitempty"`
+}
+
+type matchResources struct {
+	NamespaceSelector    *metav1.LabelSelector `json:"namespaceSelector,omitempty"`
+	ObjectSelector       *metav1.LabelSelector `json:"objectSelector,omitempty"`
+	ResourceRules        []resourceRule        `json:"resourceRules,omitempty"`
+	ExcludeResourceRules []resourceRule        `json:"excludeResourceRules,omitempty"`
+}
+
+type resourceRule struct {
+	ResourceNames []string `json:"resourceNames,omitempty"`
+	Operations    []string `json:"operations,omitempty"`
+	APIGroups     []string `json:"apiGroups,omitempty"`
+	APIVersions   []string `json:"apiVersions,omitempty"`
+	Resources     []string `json:"resources,omitempty"`
+	Scope         *string  `json:"scope,omitempty"`
+}
+
+type celValidation struct {
+	Expression        string `json:"expression"`
+	Message           string `json:"message,omitempty"`
+	MessageExpression string `json:"messageExpression,omitempty"`
+	FieldPath         string `json:"fieldPath,omitempty"`
+}
+
+// namedCondition is a match condition or a variable
+type namedCondition struct {
+	Name       string `json:"name"`
+	Expression string `json:"expression"`
+}
+
+// isAdmissionPolicy returns true if the object is a ValidatingAdmissionPolicy
+// or a ValidatingAdmissionPolicyBinding
+func isAdmissionPolicy(u *unstructured.Unstructured) bool {
+	gvk := u.GroupVersionKind()
+	return gvk.Group == admissionGroup &&
+		(gvk.Kind == "ValidatingAdmissionPolicy" || gvk.Kind == "ValidatingAdmissionPolicyBinding")
+}
+
+// ValidateCEL evaluates the ValidatingAdmissionPolicies bound by the
+// ValidatingAdmissionPolicyBindings among the objects against the other
+// objects, as the API server does when the objects are created. The params
+// are looked up among the objects and the inventory. The violations are
+// returned along with the result items for the reports.
+func ValidateCEL(objects, inventory []*unstructured.Unstructured) ([]framework.ResultItem, []violation, error) {
+	policies := make(map[string]*admissionPolicy)
+	var bindings []*policyBinding
+	for _, obj := range objects {
+		if !isAdmissionPolicy(obj) {
+			continue
+		}
+		if obj.GetKind() == "ValidatingAdmissionPolicy" {
+			p := &admissionPolicy{}
+			if err := fromUnstructured(obj, p); err != nil {
+				return nil, nil, fmt.Errorf("unable to parse ValidatingAdmissionPolicy %q: %w", obj.GetName(), err)
+			}
+			policies[p.Name] = p
+		} else {
+			b := &policyBinding{}
+			if err := fromUnstructured(obj, b); err != nil {
+				return nil, nil, fmt.Errorf("unable to parse ValidatingAdmissionPolicyBinding %q: %w", obj.GetName(), err)
+			}
+			bindings = append(bindings, b)
+		}
+	}
+	if len(bindings) == 0 {
+		return nil, nil, nil
+	}
+	sort.SliceStable(bindings, func(i, j int) bool { return bindings[i].Name < bindings[j].Name })
+
+	all := append(append([]*unstructured.Unstructured{}, objects...), inventory...)
+	namespaces, err := namespaceMap(all)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var items []framework.ResultItem
+	var violations []violation
+	for _, b := range bindings {
+		p, found := policies[b.Spec.PolicyName]
+		if !found {
+			// the binding has no effect until its policy is created
+			continue
+		}
+		e, err := newEvaluator(p)
+		if err != nil {
+			return nil, nil, err
+		}
+		for _, obj := range objects {
+			if isAdmissionPolicy(obj) || isPolicy(obj) {
+				continue
+			}
+			ns := namespaceOf(obj, namespaces)
+			if !p.Spec.MatchConstraints.matches(obj, ns, false) || !b.Spec.MatchResources.matches(obj, ns, true) {
+				continue
+			}
+			results, err := e.evaluate(b, obj, all)
+			if err != nil {
+				return nil, nil, err
+			}
+			for _, v := range results {
+				items = append(items, v.item)
+			}
+			violations = append(violations, results...)
+		}
+	}
+	return items, violations, nil
+}
+
+// matches returns true if the object matches the resources. The empty
+// resource rules match all the resources when optional, i.e. in a binding.
+func (m *matchResources) matches(obj *unstructured.Unstructured, ns *corev1.Namespace, optional bool) bool {
+	if m == nil {
+		return optional
+	}
+	if !selectorMatches(m.ObjectSelector, obj.GetLabels()) {
+		return false
+	}
+	if obj.GetNamespace() != "" || isNamespace(obj) {
+		var nsLabels map[string]string
+		if ns != nil {
+			nsLabels = ns.GetLabels()
+		}
+		if !selectorMatches(m.NamespaceSelector, nsLabels) {
+			return false
+		}
+	}
+	for _, r := range m.ExcludeResourceRules {
+		if r.matches(obj) {
+			return false
+		}
+	}
+	if len(m.ResourceRules) == 0 {
+		return optional
+	}
+	for _, r := range m.ResourceRules {
+		if r.matches(obj) {
+			return true
+		}
+	}
+	return false
+}
+
+// selectorMatches returns true if the labels match the selector, the nil
+// selector matches all the labels
+func selectorMatches(selector *metav1.LabelSelector, l map[string]string) bool {
+	if selector == nil {
+		return true
+	}
+	s, err := metav1.LabelSelectorAsSelector(selector)
+	if err != nil {
+		return false
+	}
+	return s.Matches(labels.Set(l))
+}
+
+// matches returns true if the rule matches the creation of the object. The
+// resource of the object is guessed from its kind.
+func (r *resourceRule) matches(obj *unstructured.Unstructured) bool {
+	gvk := obj.GroupVersionKind()
+	resource, _ := meta.UnsafeGuessKindToResource(gvk)
+	if !containsOrStar(r.Operations, createOperation) ||
+		!containsOrStar(r.APIGroups, gvk.Group) ||
+		!containsOrStar(r.APIVersions, gvk.Version) ||
+		!(containsOrStar(r.Resources, resource.Resource) || contains(r.Resources, "*/*")) {
+		return false
+	}
+	if len(r.ResourceNames) > 0 && !contains(r.ResourceNames, obj.GetName()) {
+		return false
+	}
+	if r.Scope != nil {
+		clusterScoped := obj.GetNamespace() == ""
+		switch *r.Scope {
+		case "Cluster":
+			return clusterScoped
+		case "Namespaced":
+			return !clusterScoped
+		}
+	}
+	return true
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+func containsOrStar(values []string, value string) bool {
+	return contains(values, value) || contains(values, "*")
+}
+
+// isNamespace returns true if the object is a Namespace
+func isNamespace(u *unstructured.Unstructured) bool {
+	gvk := u.GroupVersionKind()
+	return gvk.Group == "" && gvk.Kind == "Namespace"
+}
+
+// evaluator evaluates the compiled expressions of a policy
+type evaluator struct {
+	policy      *admissionPolicy
+	conditions  []cel.Program
+	variables   []cel.Program
+	validations []cel.Program
+	messages    []cel.Program
+	// err is the compilation error of the expressions
+	err error
+}
+
+// newEvaluator compiles the expressions of the policy. The compilation
+// errors are reported when the policy is evaluated, depending on its failure
+// policy.
+func newEvaluator(p *admissionPolicy) (*evaluator, error) {
+	env, err := cel.NewEnv(
+		cel.Variable("object", cel.DynType),
+		cel.Variable("oldObject", cel.DynType),
+		cel.Variable("request", cel.DynType),
+		cel.Variable("params", cel.DynType),
+		cel.Variable("namespaceObject", cel.DynType),
+		cel.Variable("variables", cel.MapType(cel.StringType, cel.DynType)),
+		ext.Strings(),
+		ext.Encoders(),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	e := &evaluator{policy: p}
+	compile := func(expression string) cel.Program {
+		if e.err != nil || expression == "" {
+			return nil
+		}
+		ast, issues := env.Compile(expression)
+		if issues != nil && issues.Err() != nil {
+			e.err = fmt.Errorf("compilation of %q failed: %w", expression, issues.Err())
+			return nil
+		}
+		prg, err := env.Program(ast)
+		if err != nil {
+			e.err = fmt.Errorf("compilation of %q failed: %w", expression, err)
+			return nil
+		}
+		return prg
+	}
+	for _, c := range p.Spec.MatchConditions {
+		e.conditions = append(e.conditions, compile(c.Expression))
+	}
+	for _, v := range p.Spec.Variables {
+		e.variables = append(e.variables, compile(v.Expression))
+	}
+	for _, v := range p.Spec.Validations {
+		e.validations = append(e.validations, compile(v.Expression))
+		e.messages = append(e.messages, compile(v.MessageExpression))
+	}
+	return e, nil
+}
+
+// evaluate evaluates the policy against the object with each param of the
+// binding, and returns a violation for each failed validation
+func (e *evaluator) evaluate(b *policyBinding, obj *unstructured.Unstructured, objects []*unstructured.Unstructured) ([]violation, error) {
+	params, err := e.params(b, obj, objects)
+	if err != nil {
+		return e.fail(b, obj, err.Error(), "")
+	}
+	if e.err != nil {
+		return e.fail(b, obj, e.err.Error(), "")
+	}
+
+	var violations []violation
+	for _, param := range params {
+		vars := map[string]interface{}{
+			"object":          obj.Object,
+			"oldObject":       nil,
+			"request":         admissionRequest(obj),
+			"params":          param,
+			"namespaceObject": namespaceObject(obj, objects),
+		}
+		matched, err := e.matchConditions(vars)
+		if err != nil {
+			return e.fail(b, obj, err.Error(), "")
+		}
+		if !matched {
+			continue
+		}
+		variables := make(map[string]interface{})
+		vars["variables"] = variables
+		for i, prg := range e.variables {
+			out, _, err := prg.Eval(vars)
+			if err != nil {
+				return e.fail(b, obj, fmt.Sprintf("variable %q failed: %v", e.policy.Spec.Variables[i].Name, err), "")
+			}
+			variables[e.policy.Spec.Variables[i].Name] = out.Value()
+		}
+
+		for i, prg := range e.validations {
+			v := e.policy.Spec.Validations[i]
+			out, _, err := prg.Eval(vars)
+			if err != nil {
+				results, err := e.fail(b, obj, fmt.Sprintf("expression %q failed: %v", v.Expression, err), v.FieldPath)
+				if err != nil {
+					return nil, err
+				}
+				violations = append(violations, results...)
+				continue
+			}
+			if valid, ok := out.Value().(bool); ok && valid {
+				continue
+			}
+			violated, err := e.newViolation(b, obj, e.message(i, vars), v.FieldPath)
+			if err != nil {
+				return nil, err
+			}
+			violations = append(violations, violated)
+		}
+	}
+	return violations, nil
+}
+
+// matchConditions returns true if all the match conditions are true
+func (e *evaluator) matchConditions(vars map[string]interface{}) (bool, error) {
+	for i, prg := range e.conditions {
+		out, _, err := prg.Eval(vars)
+		if err != nil {
+			return false, fmt.Errorf("match condition %q failed: %w", e.policy.Spec.MatchConditions[i].Name, err)
+		}
+		if matched, ok := out.Value().(bool); !ok || !matched {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+// message returns the message of the failed validation, from its message
+// expression, its message or its expression
+func (e *evaluator) message(i int, vars map[string]interface{}) string {
+	v := e.policy.Spec.Validations[i]
+	if prg := e.messages[i]; prg != nil {
+		if out, _, err := prg.Eval(vars); err == nil {
+			if msg, ok := out.Value().(string); ok && strings.TrimSpace(msg) != "" {
+				return msg
+			}
+		}
+	}
+	if v.Message != "" {
+		return v.Message
+	}
+	return fmt.Sprintf("failed expression: %s", v.Expression)
+}
+
+// fail returns the violation of an evaluation error, unless the failure
+// policy of the policy ignores the errors
+func (e *evaluator) fail(b *policyBinding, obj *unstructured.Unstructured, msg, field string) ([]violation, error) {
+	if fp := e.policy.Spec.FailurePolicy; fp != nil && *fp == ignorePolicy {
+		return nil, nil
+	}
+	v, err := e.newViolation(b, obj, msg, field)
+	if err != nil {
+		return nil, err
+	}
+	return []violation{v}, nil
+}
+
+// newViolation returns the violation of the binding, whose result item severity
+// depends on the validation actions of the binding
+func (e *evaluator) newViolation(b *policyBinding, obj *unstructured.Unstructured, msg, field string) (violation, error) {
+	item, err := newResultItem(obj)
+	if err != nil {
+		return violation{}, err
+	}
+	item.Message = msg + violatedPolicyPrefix + e.policy.Name + violatedBindingPrefix + b.Name
+	item.Field.Path = strings.TrimPrefix(field, ".")
+	switch {
+	case contains(b.Spec.ValidationActions, denyAction) || len(b.Spec.ValidationActions) == 0:
+		item.Severity = framework.Error
+	case contains(b.Spec.ValidationActions, warnAction):
+		item.Severity = framework.Warning
+	default:
+		item.Severity = framework.Info
+	}
+	return violation{report: "ValidatingAdmissionPolicyBinding/" + b.Name, item: item, message: msg}, nil
+}
+
+// params returns the params of the binding for the object. There is a nil
+// param when the policy has no param kind, or when no param is found and the
+// binding allows it.
+func (e *evaluator) params(b *policyBinding, obj *unstructured.Unstructured, objects []*unstructured.Unstructured) ([]interface{}, error) {
+	pk := e.policy.Spec.ParamKind
+	if pk == nil {
+		return []interface{}{nil}, nil
+	}
+	ref := b.Spec.ParamRef
+	if ref == nil {
+		return nil, fmt.Errorf("binding %q must reference the params of kind %s", b.Name, pk.Kind)
+	}
+
+	var params []interface{}
+	for _, o := range objects {
+		if o.GetAPIVersion() != pk.APIVersion || o.GetKind() != pk.Kind {
+			continue
+		}
+		namespace := ref.Namespace
+		if namespace == "" && o.GetNamespace() != "" {
+			// the namespaced params are looked up in the namespace of the object
+			namespace = obj.GetNamespace()
+		}
+		if o.GetNamespace() != namespace {
+			continue
+		}
+		if ref.Name != "" && o.GetName() != ref.Name {
+			continue
+		}
+		if ref.Name == "" && !selectorMatches(ref.Selector, o.GetLabels()) {
+			continue
+		}
+		params = append(params, o.Object)
+	}
+	if len(params) > 0 {
+		return params, nil
+	}
+	if a := ref.ParameterNotFoundAction; a != nil && *a == allowAction {
+		return nil, nil
+	}
+	return nil, fmt.Errorf("no params found for binding %q", b.Name)
+}
+
+// admissionRequest returns the admission request of the creation of the object
+func admissionRequest(obj *unstructured.Unstructured) map[string]interface{} {
+	gvk := obj.GroupVersionKind()
+	resource, _ := meta.UnsafeGuessKindToResource(gvk)
+	return map[string]interface{}{
+		"kind": map[string]interface{}{
+			"group": gvk.Group, "version": gvk.Version, "kind": gvk.Kind,
+		},
+		"resource": map[string]interface{}{
+			"group": resource.Group, "version": resource.Version, "resource": resource.Resource,
+		},
+		"name":      obj.GetName(),
+		"namespace": obj.GetNamespace(),
+		"operation": createOperation,
+		"userInfo":  map[string]interface{}{},
+		"dryRun":    true,
+	}
+}
+
+// namespaceObject returns the namespace of the object, which is looked up
+// among the objects or only has a name, or nil for the cluster scoped objects
+func namespaceObject(obj *unstructured.Unstructured, objects []*unstructured.Unstructured) interface{} {
+	if obj.GetNamespace() == "" {
+		return nil
+	}
+	for _, o := range objects {
+		if isNamespace(o) && o.GetName() == obj.GetNamespace() {
+			return o.Object
+		}
+	}
+	return map[string]interface{}{
+		"apiVersion": "v1",
+		"kind":       "Namespace",
+		"metadata":   map[string]interface{}{"name": obj.GetNamespace()},
+	}
+}
@@ -0,0 +1,211 @@
+package main
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"sigs.k8s.io/kustomize/kyaml/fn/framework"
+	"sigs.k8s.io/kustomize/kyaml/yaml"
+)
+
+const replicasPolicy = `apiVersion: admissionregistration.k8s.io/v1
+kind: ValidatingAdmissionPolicy
+metadata:
+  name: max-replicas
+spec:
+  failurePolicy: Fail
+  paramKind:
+    apiVersion: v1
+    kind: ConfigMap
+  matchConstraints:
+    resourceRules:
+      - apiGroups: ["apps"]
+        apiVersions: ["v1"]
+        operations: ["CREATE", "UPDATE"]
+        resources: ["deployments"]
+  matchConditions:
+    - name: not-system
+      expression: "!object.metadata.name.startsWith('system-')"
+  variables:
+    - name: max
+      expression: "int(params.data.maxReplicas)"
+  validations:
+    - expression: "object.spec.replicas <= variables.max"
+      messageExpression: "'replicas must be no greater than ' + string(variables.max)"
+      fieldPath: ".spec.replicas"
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: replicas-limit
+  namespace: prod
+data:
+  maxReplicas: "3"
+---
+apiVersion: v1
+kind: Namespace
+metadata:
+  name: prod
+  labels:
+    env: prod
+---
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: nginx
+  namespace: prod
+spec:
+  replicas: 5
+---
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: system-nginx
+  namespace: prod
+spec:
+  replicas: 5
+---
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: nginx
+  namespace: dev
+spec:
+  replicas: 5
+`
+
+func TestValidateCEL(t *testing.T) {
+	testcases := []struct {
+		name     string
+		binding  string
+		policy   string
+		expected []framework.ResultItem
+	}{
+		{
+			name: "deny",
+			binding: `
+spec:
+  policyName: max-replicas
+  validationActions: [Deny]
+  paramRef:
+    name: replicas-limit
+    parameterNotFoundAction: Allow
+`,
+			expected: []framework.ResultItem{
+				{
+					Message:     "replicas must be no greater than 3\nviolatedPolicy: max-replicas\nviolatedBinding: max-replicas-binding",
+					Severity:    framework.Error,
+					ResourceRef: nginxRef("prod"),
+					Field:       framework.Field{Path: "spec.replicas"},
+				},
+			},
+		},
+		{
+			name: "warn with namespace selector",
+			binding: `
+spec:
+  policyName: max-replicas
+  validationActions: [Warn, Audit]
+  paramRef:
+    name: replicas-limit
+    namespace: prod
+  matchResources:
+    namespaceSelector:
+      matchLabels:
+        env: prod
+`,
+			expected: []framework.ResultItem{
+				{
+					Message:     "replicas must be no greater than 3\nviolatedPolicy: max-replicas\nviolatedBinding: max-replicas-binding",
+					Severity:    framework.Warning,
+					ResourceRef: nginxRef("prod"),
+					Field:       framework.Field{Path: "spec.replicas"},
+				},
+			},
+		},
+		{
+			name: "excluded resources",
+			binding: `
+spec:
+  policyName: max-replicas
+  validationActions: [Audit]
+  paramRef:
+    name: replicas-limit
+  matchResources:
+    objectSelector:
+      matchExpressions:
+        - {key: app, operator: DoesNotExist}
+    excludeResourceRules:
+      - apiGroups: ["apps"]
+        apiVersions: ["v1"]
+        operations: ["*"]
+        resources: ["deployments"]
+        resourceNames: ["nginx"]
+        scope: Namespaced
+`,
+		},
+		{
+			name: "params not found",
+			binding: `
+spec:
+  policyName: max-replicas
+  validationActions: [Audit]
+  paramRef:
+    name: replicas-limit
+`,
+			expected: []framework.ResultItem{
+				{
+					Message:     "replicas must be no greater than 3\nviolatedPolicy: max-replicas\nviolatedBinding: max-replicas-binding",
+					Severity:    framework.Info,
+					ResourceRef: nginxRef("prod"),
+					Field:       framework.Field{Path: "spec.replicas"},
+				},
+				{
+					Message:     "no params found for binding \"max-replicas-binding\"\nviolatedPolicy: max-replicas\nviolatedBinding: max-replicas-binding",
+					Severity:    framework.Info,
+					ResourceRef: nginxRef("dev"),
+				},
+			},
+		},
+		{
+			name:   "ignored failure",
+			policy: "failurePolicy: Ignore",
+			binding: `
+spec:
+  policyName: max-replicas
+  validationActions: [Deny]
+`,
+		},
+	}
+
+	for _, tc := range testcases {
+		input := replicasPolicy
+		if tc.policy != "" {
+			input = strings.Replace(input, "failurePolicy: Fail", tc.policy, 1)
+		}
+		input += `---
+apiVersion: admissionregistration.k8s.io/v1
+kind: ValidatingAdmissionPolicyBinding
+metadata:
+  name: max-replicas-binding` + tc.binding
+		objects, err := parseInventory([]byte(input), "input.yaml")
+		if err != nil {
+			t.Fatalf("in testcase %q, unexpected error: %v", tc.name, err)
+		}
+		items, _, err := ValidateCEL(objects, nil)
+		if err != nil {
+			t.Fatalf("in testcase %q, unexpected error: %v", tc.name, err)
+		}
+		if !reflect.DeepEqual(items, tc.expected) {
+			t.Errorf("in testcase %q, expect: %#v, but got: %#v", tc.name, tc.expected, items)
+		}
+	}
+}
+
+func nginxRef(namespace string) yaml.ResourceIdentifier {
+	return yaml.ResourceIdentifier{
+		TypeMeta: yaml.TypeMeta{APIVersion: "apps/v1", Kind: "Deployment"},
+		NameMeta: yaml.NameMeta{Name: "nginx", Namespace: namespace},
+	}
+}
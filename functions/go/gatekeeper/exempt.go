@@ -0,0 +1,120 @@
+// Copyright 2022 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	configv1alpha1 "github.com/open-policy-agent/gatekeeper/apis/config/v1alpha1"
+	"github.com/open-policy-agent/gatekeeper/pkg/controller/config/process"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/kustomize/kyaml/fn/framework"
+)
+
+const (
+	// ExemptAnnotation exempts the resource from the validation and the
+	// mutation. Its value is the justification of the exemption, which must
+	// not be empty.
+	ExemptAnnotation = "gatekeeper.kpt.dev/exempt"
+
+	// configName is the name of the Gatekeeper Config honored by Gatekeeper
+	configName = "config"
+)
+
+// isConfig returns true if the object is the Gatekeeper Config
+func isConfig(u *unstructured.Unstructured) bool {
+	gvk := u.GroupVersionKind()
+	return gvk.Group == configv1alpha1.GroupVersion.Group && gvk.Kind == "Config" && u.GetName() == configName
+}
+
+// newExcluder returns the excluder of the namespaces excluded by the
+// spec.match of the Gatekeeper Configs in the objects
+func newExcluder(objects []*unstructured.Unstructured) (*process.Excluder, error) {
+	excluder := process.New()
+	for _, obj := range objects {
+		if !isConfig(obj) {
+			continue
+		}
+		config := &configv1alpha1.Config{}
+		if err := runtime.DefaultUnstructuredConverter.FromUnstructured(obj.Object, config); err != nil {
+			return nil, fmt.Errorf("unable to parse Config %q: %w", obj.GetName(), err)
+		}
+		excluder.Add(config.Spec.Match)
+	}
+	return excluder, nil
+}
+
+// exempt returns the indices of the objects which are not exempted from the
+// Gatekeeper process, either by the excluded namespaces of the Gatekeeper
+// Config or by the exempt annotation, and an info result for each exempted
+// object. The policies and the mutators are never exempted.
+func exempt(objects []*unstructured.Unstructured, excluder *process.Excluder, p process.Process) ([]int, []framework.ResultItem, error) {
+	var included []int
+	var items []framework.ResultItem
+	for i, obj := range objects {
+		if isPolicy(obj) || isMutator(obj) || isAdmissionPolicy(obj) {
+			included = append(included, i)
+			continue
+		}
+		var reason string
+		if justification, found := obj.GetAnnotations()[ExemptAnnotation]; found {
+			if strings.TrimSpace(justification) == "" {
+				return nil, nil, fmt.Errorf("the %s annotation of %s %q must provide a justification",
+					ExemptAnnotation, obj.GetKind(), obj.GetName())
+			}
+			reason = justification
+		} else {
+			excluded, err := excluder.IsNamespaceExcluded(p, obj)
+			if err != nil {
+				return nil, nil, err
+			}
+			if excluded {
+				reason = fmt.Sprintf("namespace %q is excluded by the Gatekeeper Config", namespaceName(obj))
+			}
+		}
+		if reason == "" {
+			included = append(included, i)
+			continue
+		}
+
+		item, err := newResultItem(obj)
+		if err != nil {
+			return nil, nil, err
+		}
+		item.Message = fmt.Sprintf("exempted from %s: %s", processName(p), reason)
+		item.Severity = framework.Info
+		items = append(items, item)
+	}
+	return included, items, nil
+}
+
+// namespaceName returns the name of the namespace of the object, which is its
+// own name for a Namespace
+func namespaceName(u *unstructured.Unstructured) string {
+	if gvk := u.GroupVersionKind(); gvk.Group == "" && gvk.Kind == "Namespace" {
+		return u.GetName()
+	}
+	return u.GetNamespace()
+}
+
+// processName returns the name of the Gatekeeper process in the results
+func processName(p process.Process) string {
+	if p == process.Mutation {
+		return "mutation"
+	}
+	return "validation"
+}
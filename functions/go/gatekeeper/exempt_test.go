@@ -0,0 +1,109 @@
+package main
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"sigs.k8s.io/kustomize/kyaml/fn/framework"
+	"sigs.k8s.io/kustomize/kyaml/kio"
+)
+
+const gatekeeperConfig = `---
+apiVersion: config.gatekeeper.sh/v1alpha1
+kind: Config
+metadata:
+  name: config
+  namespace: gatekeeper-system
+spec:
+  match:
+    - excludedNamespaces: ["kube-*"]
+      processes: ["webhook"]
+`
+
+func TestExempt(t *testing.T) {
+	testcases := []struct {
+		name     string
+		input    string
+		messages []string
+		err      string
+	}{
+		{
+			name: "excluded namespace",
+			input: requiredNamespacePolicy + gatekeeperConfig + `---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: kube-config
+  namespace: kube-system
+`,
+			messages: []string{
+				"namespace prod does not exist\nviolatedConstraint: required-namespace",
+				`exempted from validation: namespace "kube-system" is excluded by the Gatekeeper Config`,
+			},
+		},
+		{
+			name: "namespace excluded from another process",
+			input: requiredNamespacePolicy + strings.Replace(gatekeeperConfig, "webhook", "audit", 1) + `---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: kube-config
+  namespace: kube-system
+`,
+			messages: []string{
+				"namespace prod does not exist\nviolatedConstraint: required-namespace",
+				"namespace kube-system does not exist\nviolatedConstraint: required-namespace",
+			},
+		},
+		{
+			name: "exempt annotation",
+			input: requiredNamespacePolicy + `---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: legacy-config
+  namespace: legacy
+  annotations:
+    gatekeeper.kpt.dev/exempt: "migrated by JIRA-123"
+`,
+			messages: []string{
+				"namespace prod does not exist\nviolatedConstraint: required-namespace",
+				"exempted from validation: migrated by JIRA-123",
+			},
+		},
+		{
+			name: "exempt annotation without justification",
+			input: requiredNamespacePolicy + `---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: legacy-config
+  annotations:
+    gatekeeper.kpt.dev/exempt: ""
+`,
+			err: `the gatekeeper.kpt.dev/exempt annotation of ConfigMap "legacy-config" must provide a justification`,
+		},
+	}
+
+	for _, tc := range testcases {
+		items, err := (&kio.ByteReader{Reader: strings.NewReader(tc.input), OmitReaderAnnotations: true}).Read()
+		if err != nil {
+			t.Fatalf("in testcase %q, unexpected error: %v", tc.name, err)
+		}
+		rl := &framework.ResourceList{Items: items}
+		_ = (&GatekeeperProcessor{}).Process(rl)
+
+		var messages []string
+		for _, item := range rl.Result.Items {
+			messages = append(messages, item.Message)
+		}
+		expected := tc.messages
+		if tc.err != "" {
+			expected = []string{tc.err}
+		}
+		if !reflect.DeepEqual(messages, expected) {
+			t.Errorf("in testcase %q, expect: %q, but got: %q", tc.name, expected, messages)
+		}
+	}
+}
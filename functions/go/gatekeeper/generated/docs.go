@@ -77,4 +77,203 @@ above.
             - 'apps'
           kinds:
             - Deployment
+
+### ValidatingAdmissionPolicy
+
+The ` + "`" + `ValidatingAdmissionPolicy` + "`" + ` and ` + "`" + `ValidatingAdmissionPolicyBinding` + "`" + `
+resources (` + "`" + `admissionregistration.k8s.io` + "`" + `) in the package are evaluated
+alongside the Gatekeeper constraints, as the API server does when the package
+resources are created:
+
+- The resources must match both the ` + "`" + `matchConstraints` + "`" + ` of the policy and the
+  ` + "`" + `matchResources` + "`" + ` of the binding. The resource of a kind is guessed from the
+  kind, e.g. ` + "`" + `deployments` + "`" + ` for ` + "`" + `Deployment` + "`" + `. The namespace selectors are
+  matched against the labels of the ` + "`" + `Namespace` + "`" + ` resources in the package or in
+  the inventory.
+- The ` + "`" + `params` + "`" + ` are resolved by the ` + "`" + `paramRef` + "`" + ` of the binding to the package
+  and inventory resources of the ` + "`" + `paramKind` + "`" + ` of the policy, by name or by
+  selector. The namespaced params are looked up in the namespace of the
+  resource when the ` + "`" + `paramRef` + "`" + ` has no namespace. The policy is evaluated once
+  for each param.
+- The ` + "`" + `matchConditions` + "`" + `, ` + "`" + `variables` + "`" + `, ` + "`" + `validations` + "`" + ` and their
+  ` + "`" + `messageExpression` + "`" + ` are evaluated with the ` + "`" + `object` + "`" + `, ` + "`" + `oldObject` + "`" + ` (null),
+  ` + "`" + `params` + "`" + `, ` + "`" + `namespaceObject` + "`" + `, ` + "`" + `request` + "`" + ` and ` + "`" + `variables` + "`" + ` variables. The
+  ` + "`" + `authorizer` + "`" + ` variable is not available offline.
+- A failed validation is reported with the same severities as the constraints:
+  error for the ` + "`" + `Deny` + "`" + ` action, warning for the ` + "`" + `Warn` + "`" + ` action and info for the
+  ` + "`" + `Audit` + "`" + ` action. The ` + "`" + `fieldPath` + "`" + ` of the validation is the field of the result.
+- The evaluation errors, e.g. params which are not found, are reported as
+  violations unless the ` + "`" + `failurePolicy` + "`" + ` of the policy is ` + "`" + `Ignore` + "`" + `.
+
+  apiVersion: admissionregistration.k8s.io/v1
+  kind: ValidatingAdmissionPolicy
+  metadata:
+    name: max-replicas
+  spec:
+    paramKind:
+      apiVersion: v1
+      kind: ConfigMap
+    matchConstraints:
+      resourceRules:
+        - apiGroups: ["apps"]
+          apiVersions: ["v1"]
+          operations: ["CREATE", "UPDATE"]
+          resources: ["deployments"]
+    validations:
+      - expression: "object.spec.replicas <= int(params.data.maxReplicas)"
+        message: "too many replicas"
+        fieldPath: ".spec.replicas"
+  ---
+  apiVersion: admissionregistration.k8s.io/v1
+  kind: ValidatingAdmissionPolicyBinding
+  metadata:
+    name: max-replicas-binding
+  spec:
+    policyName: max-replicas
+    validationActions: [Deny]
+    paramRef:
+      name: replicas-limit
+      namespace: default
+
+### Mutation
+
+The function can also apply the [Gatekeeper mutators] in the package to the
+other resources before validating them, so that the manifests which the cluster
+will admit can be reviewed before they are applied. The mutation is enabled by
+the ` + "`" + `mutate: "true"` + "`" + ` key of a ` + "`" + `ConfigMap` + "`" + ` functionConfig or by the ` + "`" + `--mutate` + "`" + `
+flag. The ` + "`" + `Assign` + "`" + `, ` + "`" + `AssignMetadata` + "`" + `, ` + "`" + `ModifySet` + "`" + ` and ` + "`" + `AssignImage` + "`" + ` mutators are
+supported. The mutators are applied in the same order as Gatekeeper does until
+the mutations converge, the mutated resources are written back to the package
+and an info result reports each field changed by a mutator.
+
+  apiVersion: mutations.gatekeeper.sh/v1beta1
+  kind: Assign
+  metadata:
+    name: run-as-non-root
+  spec:
+    applyTo:
+      - groups: ["apps"]
+        kinds: ["Deployment"]
+        versions: ["v1"]
+    location: spec.template.spec.securityContext.runAsNonRoot
+    parameters:
+      assign:
+        value: true
+
+### Referential constraints
+
+Referential constraints read other objects through ` + "`" + `data.inventory` + "`" + `, e.g. to
+require that the namespace of a resource exists or that the ingress hosts are
+unique. By default, the inventory contains the package resources. The inventory
+can be extended with read-only objects, which are not validated and are not
+written back to the package:
+
+- In-package resources with the ` + "`" + `gatekeeper.kpt.dev/inventory: "true"` + "`" + `
+  annotation. They should also be marked as local config with the
+  ` + "`" + `config.kubernetes.io/local-config: "true"` + "`" + ` annotation so that they are not
+  applied to the cluster.
+- YAML and JSON files in local directories or tarballs (` + "`" + `.tar` + "`" + `, ` + "`" + `.tar.gz` + "`" + ` or
+  ` + "`" + `.tgz` + "`" + `), e.g. the output of ` + "`" + `kubectl get namespaces -o yaml` + "`" + `. The comma
+  separated paths are provided by the ` + "`" + `inventory` + "`" + ` key of a ` + "`" + `ConfigMap` + "`" + `
+  functionConfig or by the ` + "`" + `--inventory` + "`" + ` flag.
+
+  apiVersion: v1
+  kind: ConfigMap
+  metadata:
+    name: gatekeeper-fn-config
+  data:
+    inventory: /inventory/namespaces.yaml.tar.gz,/inventory/ingresses
+
+The package resources take precedence over the inventory objects with the same
+group, kind, namespace and name. The constraint templates and constraints in
+the inventory are ignored.
+
+### Field locations
+
+When a violation provides the path of the offending field in its ` + "`" + `details` + "`" + `,
+the result references the field and its message ends with its location in the
+source file, e.g. ` + "`" + `location: pod.yaml:19:21` + "`" + `, so that the violation can be
+reported on the exact line. The path is read from the ` + "`" + `field` + "`" + ` key of the
+details by default, the key can be changed by the ` + "`" + `field-details-key` + "`" + ` key of a
+` + "`" + `ConfigMap` + "`" + ` functionConfig or by the ` + "`" + `--field-details-key` + "`" + ` flag. The path is
+either a string, e.g. ` + "`" + `spec.containers[0].image` + "`" + `, or a list of the field names
+and the list indices, e.g. ` + "`" + `["spec", "containers", 0, "image"]` + "`" + `. When the field
+doesn't exist, e.g. a required field is missing, the location of its closest
+existing parent is reported.
+
+### Exemptions
+
+The function honors the excluded namespaces of the [Gatekeeper Config] named
+` + "`" + `config` + "`" + ` in the package or in the inventory, the same way as Gatekeeper does:
+the namespaces excluded from the ` + "`" + `webhook` + "`" + ` process are not validated, and the
+namespaces excluded from the ` + "`" + `mutation-webhook` + "`" + ` process are not mutated.
+
+  apiVersion: config.gatekeeper.sh/v1alpha1
+  kind: Config
+  metadata:
+    name: config
+    namespace: gatekeeper-system
+  spec:
+    match:
+      - excludedNamespaces: ["kube-*"]
+        processes: ["webhook", "mutation-webhook"]
+
+Individual resources are exempted by the ` + "`" + `gatekeeper.kpt.dev/exempt` + "`" + `
+annotation, whose value is the justification of the exemption. The function
+fails if the justification is empty.
+
+  apiVersion: v1
+  kind: ConfigMap
+  metadata:
+    name: legacy-config
+    annotations:
+      gatekeeper.kpt.dev/exempt: "migrated by JIRA-123"
+
+Each exempted resource is reported by an info result, e.g.
+` + "`" + `exempted from validation: migrated by JIRA-123` + "`" + `. The exempted resources are
+still visible to the referential constraints. The constraint templates,
+constraints and mutators are never exempted.
+
+### Reports
+
+The function can also write the validation results as [SARIF] 2.1.0 and
+JUnit XML reports, for code scanning tools and CI dashboards. The comma
+separated report formats, ` + "`" + `sarif` + "`" + ` and ` + "`" + `junit` + "`" + `, are provided by the
+` + "`" + `report-formats` + "`" + ` key of a ` + "`" + `ConfigMap` + "`" + ` functionConfig or by the
+` + "`" + `--report-formats` + "`" + ` flag.
+
+  apiVersion: v1
+  kind: ConfigMap
+  metadata:
+    name: gatekeeper-fn-config
+  data:
+    report-formats: sarif,junit
+
+By default, the reports are written to the ` + "`" + `gatekeeper.sarif` + "`" + ` and
+` + "`" + `gatekeeper.junit.xml` + "`" + ` keys of the ` + "`" + `gatekeeper-report` + "`" + ` local config
+` + "`" + `ConfigMap` + "`" + `, in the ` + "`" + `gatekeeper-report.yaml` + "`" + ` file of the package. It is marked
+by the ` + "`" + `gatekeeper.kpt.dev/report: "true"` + "`" + ` annotation, is replaced on each run
+and is not validated. Since the package is not written when the validation
+fails, the reports can instead be written to the files with the same names in
+the directory provided by the ` + "`" + `report-path` + "`" + ` key of the functionConfig or by the
+` + "`" + `--report-path` + "`" + ` flag, e.g. a directory mounted in the function container.
+
+Each constraint and each ` + "`" + `ValidatingAdmissionPolicyBinding` + "`" + ` is a rule of the
+SARIF report and a test suite of the JUnit report, identified by its kind and
+name, e.g. ` + "`" + `K8sRequiredLabels/prod` + "`" + ` or
+` + "`" + `ValidatingAdmissionPolicyBinding/max-replicas-binding` + "`" + `. The resources matched
+by the constraint, or by both the policy and the binding, are counted as passed
+or failed in the ` + "`" + `passed` + "`" + ` and ` + "`" + `failed` + "`" + ` properties of the SARIF rule and in the
+` + "`" + `tests` + "`" + ` and ` + "`" + `failures` + "`" + ` attributes of the JUnit test suite. Each resource
+failing the constraint or the binding is a JUnit test case with a failure per
+violation.
+The SARIF results have the file, line and column of the offending field when
+the violation provides it, see [Field locations](#field-locations).
+
+  violation[{"msg": msg, "details": {"field": field}}] {
+    c := input.review.object.spec.containers[i]
+    c.securityContext.privileged
+    msg := sprintf("privileged container is not allowed: %v", [c.name])
+    field := sprintf("spec.containers[%v].securityContext.privileged", [i])
+  }
 `
@@ -0,0 +1,180 @@
+// Copyright 2022 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/kustomize/kyaml/kio"
+	k8syaml "sigs.k8s.io/yaml"
+)
+
+const (
+	// InventoryAnnotation marks the in-package resources which only populate
+	// the inventory (data.inventory) of the referential constraints. They are
+	// not validated and should also be marked as local config.
+	InventoryAnnotation = "gatekeeper.kpt.dev/inventory"
+
+	// InventoryKey is the key of the functionConfig data listing the comma
+	// separated paths of the directories and tarballs of inventory objects
+	InventoryKey = "inventory"
+)
+
+// isInventory returns true if the object is marked as inventory
+func isInventory(u *unstructured.Unstructured) bool {
+	return u.GetAnnotations()[InventoryAnnotation] == "true"
+}
+
+// isPolicy returns true if the object is a constraint template or a constraint
+func isPolicy(u *unstructured.Unstructured) bool {
+	gvk := u.GroupVersionKind()
+	return (gvk.Group == "templates.gatekeeper.sh" && gvk.Kind == "ConstraintTemplate") ||
+		gvk.Group == "constraints.gatekeeper.sh"
+}
+
+// readInventory reads the inventory objects from the YAML and JSON files in
+// the directory or in the tarball, which may be gzipped, in the input path
+func readInventory(path string) ([]*unstructured.Unstructured, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read inventory: %w", err)
+	}
+	if info.IsDir() {
+		return readInventoryDir(path)
+	}
+	return readInventoryTarball(path)
+}
+
+// readInventoryDir reads the inventory objects from the files in the directory tree
+func readInventoryDir(dir string) ([]*unstructured.Unstructured, error) {
+	var objects []*unstructured.Unstructured
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() || !isInventoryFile(path) {
+			return err
+		}
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		objs, err := parseInventory(content, path)
+		if err != nil {
+			return err
+		}
+		objects = append(objects, objs...)
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("unable to read inventory: %w", err)
+	}
+	return objects, nil
+}
+
+// readInventoryTarball reads the inventory objects from the files in the tarball
+func readInventoryTarball(path string) ([]*unstructured.Unstructured, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read inventory: %w", err)
+	}
+	defer f.Close()
+
+	var r io.Reader = f
+	if strings.HasSuffix(path, ".gz") || strings.HasSuffix(path, ".tgz") {
+		gz, err := gzip.NewReader(f)
+		if err != nil {
+			return nil, fmt.Errorf("unable to read inventory %q: %w", path, err)
+		}
+		defer gz.Close()
+		r = gz
+	}
+
+	var objects []*unstructured.Unstructured
+	tr := tar.NewReader(r)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			return objects, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("unable to read inventory %q: %w", path, err)
+		}
+		if hdr.Typeflag != tar.TypeReg || !isInventoryFile(hdr.Name) {
+			continue
+		}
+		content, err := io.ReadAll(tr)
+		if err != nil {
+			return nil, fmt.Errorf("unable to read inventory %q: %w", path, err)
+		}
+		objs, err := parseInventory(content, hdr.Name)
+		if err != nil {
+			return nil, fmt.Errorf("unable to read inventory %q: %w", path, err)
+		}
+		objects = append(objects, objs...)
+	}
+}
+
+// isInventoryFile returns true if the file may contain inventory objects
+func isInventoryFile(path string) bool {
+	switch filepath.Ext(path) {
+	case ".yaml", ".yml", ".json":
+		return true
+	}
+	return false
+}
+
+// parseInventory parses the objects in the content of the file in the input path
+func parseInventory(content []byte, path string) ([]*unstructured.Unstructured, error) {
+	if filepath.Ext(path) == ".json" {
+		var err error
+		content, err = k8syaml.JSONToYAML(content)
+		if err != nil {
+			return nil, fmt.Errorf("unable to parse %q: %w", path, err)
+		}
+	}
+	nodes, err := (&kio.ByteReader{
+		Reader:                bytes.NewReader(content),
+		OmitReaderAnnotations: true,
+	}).Read()
+	if err != nil {
+		return nil, fmt.Errorf("unable to parse %q: %w", path, err)
+	}
+	var objects []*unstructured.Unstructured
+	for _, node := range nodes {
+		s, err := node.String()
+		if err != nil {
+			return nil, err
+		}
+		un := &unstructured.Unstructured{}
+		if err := k8syaml.Unmarshal([]byte(s), un); err != nil {
+			return nil, fmt.Errorf("unable to parse %q: %w", path, err)
+		}
+		objects = append(objects, un)
+	}
+	return objects, nil
+}
+
+// objectKey returns the key identifying the object in the inventory
+func objectKey(u *unstructured.Unstructured) string {
+	gvk := u.GroupVersionKind()
+	return fmt.Sprintf("%s/%s/%s/%s", gvk.Group, gvk.Kind, u.GetNamespace(), u.GetName())
+}
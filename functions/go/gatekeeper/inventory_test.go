@@ -0,0 +1,166 @@
+package main
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+const requiredNamespacePolicy = `apiVersion: templates.gatekeeper.sh/v1beta1
+kind: ConstraintTemplate
+metadata:
+  name: k8srequirednamespace
+spec:
+  crd:
+    spec:
+      names:
+        kind: K8sRequiredNamespace
+  targets:
+    - target: admission.k8s.gatekeeper.sh
+      rego: |-
+        package k8srequirednamespace
+        violation[{"msg": msg}] {
+          ns := input.review.object.metadata.namespace
+          not data.inventory.cluster["v1"]["Namespace"][ns]
+          msg := sprintf("namespace %v does not exist", [ns])
+        }
+---
+apiVersion: constraints.gatekeeper.sh/v1beta1
+kind: K8sRequiredNamespace
+metadata:
+  name: required-namespace
+spec:
+  match:
+    kinds:
+      - apiGroups: [""]
+        kinds: ["ConfigMap"]
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: app-config
+  namespace: prod
+`
+
+const namespaceInventory = `apiVersion: v1
+kind: List
+items:
+  - apiVersion: v1
+    kind: Namespace
+    metadata:
+      name: prod
+  - apiVersion: v1
+    kind: ConfigMap
+    metadata:
+      name: cluster-config
+      namespace: missing
+`
+
+func TestValidateInventory(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(dir, "inventory"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "inventory", "namespaces.yaml"), []byte(namespaceInventory), 0644); err != nil {
+		t.Fatal(err)
+	}
+	writeTarball(t, filepath.Join(dir, "inventory.tar.gz"), "namespaces.yaml", namespaceInventory)
+
+	testcases := []struct {
+		name      string
+		input     string
+		inventory string
+		messages  []string
+	}{
+		{
+			name:     "no inventory",
+			input:    requiredNamespacePolicy,
+			messages: []string{"namespace prod does not exist\nviolatedConstraint: required-namespace"},
+		},
+		{
+			name:      "inventory directory",
+			input:     requiredNamespacePolicy,
+			inventory: filepath.Join(dir, "inventory"),
+		},
+		{
+			name:      "inventory tarball",
+			input:     requiredNamespacePolicy,
+			inventory: filepath.Join(dir, "inventory.tar.gz"),
+		},
+		{
+			name: "in-package inventory",
+			input: requiredNamespacePolicy + `---
+apiVersion: v1
+kind: Namespace
+metadata:
+  name: prod
+  annotations:
+    config.kubernetes.io/local-config: "true"
+    gatekeeper.kpt.dev/inventory: "true"
+`,
+		},
+	}
+
+	for _, tc := range testcases {
+		objects, err := parseInventory([]byte(tc.input), "input.yaml")
+		if err != nil {
+			t.Fatalf("in testcase %q, unexpected error: %v", tc.name, err)
+		}
+		var packageObjects, inventory []*unstructured.Unstructured
+		for _, obj := range objects {
+			if isInventory(obj) {
+				inventory = append(inventory, obj)
+			} else {
+				packageObjects = append(packageObjects, obj)
+			}
+		}
+		if tc.inventory != "" {
+			objs, err := readInventory(tc.inventory)
+			if err != nil {
+				t.Fatalf("in testcase %q, unexpected error: %v", tc.name, err)
+			}
+			inventory = append(inventory, objs...)
+		}
+
+		result, _, err := Validate(packageObjects, inventory, DefaultFieldDetailsKey)
+		if err != nil {
+			t.Fatalf("in testcase %q, unexpected error: %v", tc.name, err)
+		}
+		var messages []string
+		if result != nil {
+			for _, item := range result.Items {
+				messages = append(messages, item.Message)
+			}
+		}
+		if strings.Join(messages, ";") != strings.Join(tc.messages, ";") {
+			t.Errorf("in testcase %q, expect: %q, but got: %q", tc.name, tc.messages, messages)
+		}
+	}
+}
+
+func writeTarball(t *testing.T, path, name, content string) {
+	f, err := os.Create(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	gz := gzip.NewWriter(f)
+	tw := tar.NewWriter(gz)
+	if err := tw.WriteHeader(&tar.Header{Name: name, Mode: 0644, Size: int64(len(content)), Typeflag: tar.TypeReg}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := tw.Write([]byte(content)); err != nil {
+		t.Fatal(err)
+	}
+	if err := tw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if err := gz.Close(); err != nil {
+		t.Fatal(err)
+	}
+}
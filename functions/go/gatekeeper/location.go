@@ -0,0 +1,234 @@
+// Copyright 2022 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+
+	"sigs.k8s.io/kustomize/kyaml/fn/framework"
+	"sigs.k8s.io/kustomize/kyaml/kio"
+	"sigs.k8s.io/kustomize/kyaml/kio/kioutil"
+	"sigs.k8s.io/kustomize/kyaml/yaml"
+)
+
+const (
+	// FieldDetailsKey is the key of the functionConfig data naming the key of
+	// the violation details which holds the path of the offending field
+	FieldDetailsKey = "field-details-key"
+
+	// DefaultFieldDetailsKey is the key of the violation details holding the
+	// path of the offending field when none is configured
+	DefaultFieldDetailsKey = "field"
+
+	// idAnnotation is the annotation set by kpt to identify the resources, it
+	// is removed when the resources are written to the files
+	idAnnotation = "config.k8s.io/id"
+)
+
+// violatedField returns the path of the offending field in the details of the
+// violation. The path in the details may either be a string, e.g.
+// spec.containers[0].image, or a list of the field names and the list indices.
+func violatedField(metadata map[string]interface{}, key string) string {
+	details, ok := metadata["details"].(map[string]interface{})
+	if !ok {
+		return ""
+	}
+	switch v := details[key].(type) {
+	case string:
+		return v
+	case []interface{}:
+		var path string
+		for _, segment := range v {
+			switch s := segment.(type) {
+			case string:
+				path = joinField(path, s)
+			case int, int64, float64:
+				path += fmt.Sprintf("[%v]", s)
+			default:
+				return ""
+			}
+		}
+		return path
+	}
+	return ""
+}
+
+// fileLocator resolves the field paths to the lines and columns in the files
+// which the items are written to
+type fileLocator struct {
+	// docs are the documents in each file, by the index of the resource
+	docs map[string]map[int]*yaml.Node
+}
+
+// newFileLocator writes the items to their files in memory, the same way as
+// the orchestrator writes them to the package, and parses the files back
+func newFileLocator(items []*yaml.RNode) (*fileLocator, error) {
+	files := make(map[string][]*yaml.RNode)
+	for _, item := range items {
+		path, _, err := kioutil.GetFileAnnotations(item)
+		if err != nil {
+			return nil, err
+		}
+		if path != "" {
+			files[path] = append(files[path], item)
+		}
+	}
+
+	l := &fileLocator{docs: make(map[string]map[int]*yaml.Node)}
+	for path, nodes := range files {
+		sort.SliceStable(nodes, func(i, j int) bool {
+			return fileIndex(nodes[i]) < fileIndex(nodes[j])
+		})
+		var buf bytes.Buffer
+		err := kio.ByteWriter{
+			Writer:           &buf,
+			ClearAnnotations: []string{kioutil.PathAnnotation, idAnnotation},
+		}.Write(nodes)
+		if err != nil {
+			return nil, fmt.Errorf("unable to write %q: %w", path, err)
+		}
+
+		l.docs[path] = make(map[int]*yaml.Node)
+		decoder := yaml.NewDecoder(&buf)
+		for _, node := range nodes {
+			doc := &yaml.Node{}
+			if err := decoder.Decode(doc); err != nil {
+				if errors.Is(err, io.EOF) {
+					break
+				}
+				return nil, fmt.Errorf("unable to parse %q: %w", path, err)
+			}
+			l.docs[path][fileIndex(node)] = doc
+		}
+	}
+	return l, nil
+}
+
+// fileIndex returns the index of the resource in its file
+func fileIndex(node *yaml.RNode) int {
+	_, index, _ := kioutil.GetFileAnnotations(node)
+	i, _ := strconv.Atoi(index)
+	return i
+}
+
+// locate returns the line and column of the field in the file. When the field
+// doesn't exist, e.g. a required field is missing, the position of its closest
+// existing parent is returned.
+func (l *fileLocator) locate(file framework.File, path string) (int, int, bool) {
+	doc, found := l.docs[file.Path][file.Index]
+	if !found || len(doc.Content) == 0 {
+		return 0, 0, false
+	}
+	node := doc.Content[0]
+	for _, segment := range splitField(path) {
+		next := childNode(node, segment)
+		if next == nil {
+			break
+		}
+		node = next
+	}
+	return node.Line, node.Column, true
+}
+
+// locateFields appends the location of the offending field to the message of
+// the result items which have a field path
+func locateFields(items []*yaml.RNode, results []framework.ResultItem) error {
+	var l *fileLocator
+	for i := range results {
+		if results[i].Field.Path == "" || results[i].File.Path == "" {
+			continue
+		}
+		if l == nil {
+			var err error
+			if l, err = newFileLocator(items); err != nil {
+				return err
+			}
+		}
+		if line, column, found := l.locate(results[i].File, results[i].Field.Path); found {
+			results[i].Message += fmt.Sprintf("\nlocation: %s:%d:%d", results[i].File.Path, line, column)
+		}
+	}
+	return nil
+}
+
+// splitField splits the field path into the field names and the list indices,
+// e.g. spec.containers[0].image is split into spec, containers, [0] and image
+func splitField(path string) []string {
+	var segments []string
+	for _, field := range strings.Split(path, ".") {
+		for field != "" {
+			i := strings.Index(field, "[")
+			if i < 0 {
+				segments = append(segments, field)
+				break
+			}
+			if i > 0 {
+				segments = append(segments, field[:i])
+			}
+			j := strings.Index(field[i:], "]")
+			if j < 0 {
+				segments = append(segments, field[i:])
+				break
+			}
+			segments = append(segments, field[i:i+j+1])
+			field = field[i+j+1:]
+		}
+	}
+	return segments
+}
+
+// childNode returns the field of the mapping node or the element of the
+// sequence node, selected either by the index, e.g. [0], or by the value of
+// one of its fields, e.g. [name=nginx]
+func childNode(node *yaml.Node, segment string) *yaml.Node {
+	if !strings.HasPrefix(segment, "[") {
+		if node.Kind != yaml.MappingNode {
+			return nil
+		}
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			if node.Content[i].Value == segment {
+				return node.Content[i+1]
+			}
+		}
+		return nil
+	}
+
+	if node.Kind != yaml.SequenceNode {
+		return nil
+	}
+	selector := strings.TrimSuffix(strings.TrimPrefix(segment, "["), "]")
+	if index, err := strconv.Atoi(selector); err == nil {
+		if index < 0 || index >= len(node.Content) {
+			return nil
+		}
+		return node.Content[index]
+	}
+	key, value, found := strings.Cut(selector, "=")
+	if !found {
+		return nil
+	}
+	for _, element := range node.Content {
+		if child := childNode(element, key); child != nil && child.Value == value {
+			return element
+		}
+	}
+	return nil
+}
@@ -0,0 +1,148 @@
+package main
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"sigs.k8s.io/kustomize/kyaml/fn/framework"
+	"sigs.k8s.io/kustomize/kyaml/kio"
+	"sigs.k8s.io/kustomize/kyaml/yaml"
+)
+
+const privilegedPolicy = `apiVersion: templates.gatekeeper.sh/v1beta1
+kind: ConstraintTemplate
+metadata:
+  name: k8spspprivileged
+  annotations:
+    config.kubernetes.io/path: policy.yaml
+    config.kubernetes.io/index: '0'
+spec:
+  crd:
+    spec:
+      names:
+        kind: K8sPSPPrivileged
+  targets:
+    - target: admission.k8s.gatekeeper.sh
+      rego: |-
+        package k8spspprivileged
+        violation[{"msg": msg, "details": {"field": field, "path": path}}] {
+          c := input.review.object.spec.containers[i]
+          c.securityContext.privileged
+          msg := sprintf("privileged container is not allowed: %v", [c.name])
+          field := sprintf("spec.containers[%v].securityContext.privileged", [i])
+          path := ["spec", "containers", i, "securityContext", "privileged"]
+        }
+---
+apiVersion: constraints.gatekeeper.sh/v1beta1
+kind: K8sPSPPrivileged
+metadata:
+  name: psp-privileged
+  annotations:
+    config.kubernetes.io/path: policy.yaml
+    config.kubernetes.io/index: '1'
+spec:
+  match:
+    kinds:
+      - apiGroups: [""]
+        kinds: ["Pod"]
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: unrelated
+  annotations:
+    config.kubernetes.io/path: pod.yaml
+    config.kubernetes.io/index: '0'
+data:
+  foo: bar
+---
+apiVersion: v1
+kind: Pod
+metadata:
+  name: nginx
+  annotations:
+    config.kubernetes.io/path: pod.yaml
+    config.kubernetes.io/index: '1'
+spec:
+  containers:
+    - name: sidecar
+      image: envoy
+    - name: nginx
+      image: nginx
+      securityContext:
+        privileged: true
+`
+
+func TestLocateFields(t *testing.T) {
+	testcases := []struct {
+		name     string
+		data     map[string]string
+		messages []string
+		field    string
+	}{
+		{
+			name: "default details key",
+			messages: []string{"privileged container is not allowed: nginx\nviolatedConstraint: psp-privileged\n" +
+				"location: pod.yaml:19:21"},
+			field: "spec.containers[1].securityContext.privileged",
+		},
+		{
+			name: "configured details key",
+			data: map[string]string{FieldDetailsKey: "path"},
+			messages: []string{"privileged container is not allowed: nginx\nviolatedConstraint: psp-privileged\n" +
+				"location: pod.yaml:19:21"},
+			field: "spec.containers[1].securityContext.privileged",
+		},
+		{
+			name:     "missing details key",
+			data:     map[string]string{FieldDetailsKey: "missing"},
+			messages: []string{"privileged container is not allowed: nginx\nviolatedConstraint: psp-privileged"},
+		},
+	}
+
+	for _, tc := range testcases {
+		items, err := (&kio.ByteReader{Reader: strings.NewReader(privilegedPolicy), OmitReaderAnnotations: true}).Read()
+		if err != nil {
+			t.Fatalf("in testcase %q, unexpected error: %v", tc.name, err)
+		}
+		fc := yaml.NewMapRNode(nil)
+		if err := fc.LoadMapIntoConfigMapData(tc.data); err != nil {
+			t.Fatalf("in testcase %q, unexpected error: %v", tc.name, err)
+		}
+		rl := &framework.ResourceList{Items: items, FunctionConfig: fc}
+		if err := (&GatekeeperProcessor{}).Process(rl); err == nil {
+			t.Fatalf("in testcase %q, expect violations", tc.name)
+		}
+
+		var messages []string
+		var fields []string
+		for _, item := range rl.Result.Items {
+			messages = append(messages, item.Message)
+			fields = append(fields, item.Field.Path)
+		}
+		if !reflect.DeepEqual(messages, tc.messages) {
+			t.Errorf("in testcase %q, expect: %q, but got: %q", tc.name, tc.messages, messages)
+		}
+		if !reflect.DeepEqual(fields, []string{tc.field}) {
+			t.Errorf("in testcase %q, expect: %q, but got: %q", tc.name, tc.field, fields)
+		}
+	}
+}
+
+func TestSplitField(t *testing.T) {
+	testcases := []struct {
+		path     string
+		segments []string
+	}{
+		{path: "metadata.name", segments: []string{"metadata", "name"}},
+		{path: "spec.containers[0].image", segments: []string{"spec", "containers", "[0]", "image"}},
+		{path: "spec.containers[name=nginx].ports[1]", segments: []string{"spec", "containers", "[name=nginx]", "ports", "[1]"}},
+	}
+	for _, tc := range testcases {
+		segments := splitField(tc.path)
+		if !reflect.DeepEqual(segments, tc.segments) {
+			t.Errorf("in testcase %q, expect: %q, but got: %q", tc.path, tc.segments, segments)
+		}
+	}
+}
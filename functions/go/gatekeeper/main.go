@@ -18,12 +18,15 @@ import (
 	"bytes"
 	"fmt"
 	"os"
+	"strings"
 
 	"github.com/GoogleContainerTools/kpt-functions-catalog/functions/go/gatekeeper/generated"
+	"github.com/open-policy-agent/gatekeeper/pkg/controller/config/process"
 	"github.com/spf13/cobra"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"sigs.k8s.io/kustomize/kyaml/fn/framework"
 	"sigs.k8s.io/kustomize/kyaml/kio"
+	"sigs.k8s.io/kustomize/kyaml/yaml"
 	k8syaml "sigs.k8s.io/yaml"
 )
 
@@ -33,9 +36,14 @@ const (
 )
 
 type GatekeeperProcessor struct {
-	input   string
-	output  string
-	useJSON bool
+	input     string
+	output    string
+	useJSON   bool
+	inventory []string
+	mutation  bool
+	fieldKey  string
+	formats   []string
+	report    string
 
 	inputBuf  *bytes.Buffer
 	outputBuf *bytes.Buffer
@@ -45,8 +53,10 @@ func (gkp *GatekeeperProcessor) Process(resourceList *framework.ResourceList) er
 	resourceList.Result = &framework.Result{
 		Name: "gatekeeper",
 	}
-	var objects []*unstructured.Unstructured
-	for _, item := range resourceList.Items {
+	var objects, inventory []*unstructured.Unstructured
+	// indices are the indices of the objects in the items
+	var indices []int
+	for i, item := range resourceList.Items {
 		s, err := item.String()
 		if err != nil {
 			return err
@@ -58,10 +68,75 @@ func (gkp *GatekeeperProcessor) Process(resourceList *framework.ResourceList) er
 			return err
 		}
 
+		// the reports of the previous run are not validated
+		if isReport(un) {
+			continue
+		}
+		if isInventory(un) {
+			inventory = append(inventory, un)
+			continue
+		}
 		objects = append(objects, un)
+		indices = append(indices, i)
 	}
 
-	result, err := Validate(objects)
+	var result *framework.Result
+	var items []framework.ResultItem
+	var violations []violation
+	var validated, exempted []*unstructured.Unstructured
+	objs, err := gkp.readInventory(resourceList.FunctionConfig)
+	inventory = append(inventory, objs...)
+	var excluder *process.Excluder
+	if err == nil {
+		excluder, err = newExcluder(append(objects, inventory...))
+	}
+	if err == nil && gkp.mutationEnabled(resourceList.FunctionConfig) {
+		var included []int
+		var exemptions, mutations []framework.ResultItem
+		included, exemptions, err = exempt(objects, excluder, process.Mutation)
+		var mutObjects []*unstructured.Unstructured
+		var mutIndices []int
+		for _, i := range included {
+			mutObjects = append(mutObjects, objects[i])
+			mutIndices = append(mutIndices, indices[i])
+		}
+		if err == nil {
+			mutations, err = mutateItems(resourceList.Items, mutObjects, mutIndices)
+		}
+		items = append(items, exemptions...)
+		items = append(items, mutations...)
+	}
+	if err == nil {
+		var included []int
+		var exemptions []framework.ResultItem
+		included, exemptions, err = exempt(objects, excluder, process.Webhook)
+		items = append(items, exemptions...)
+		if err == nil {
+			// the exempted objects remain visible to the referential constraints
+			validated, exempted = split(objects, included)
+			result, violations, err = Validate(validated, append(inventory, exempted...), gkp.fieldDetailsKey(resourceList.FunctionConfig))
+		}
+		if err == nil {
+			var celItems []framework.ResultItem
+			var celViolations []violation
+			celItems, celViolations, err = ValidateCEL(validated, append(inventory, exempted...))
+			items = append(items, celItems...)
+			violations = append(violations, celViolations...)
+		}
+	}
+	if err == nil && len(items) > 0 {
+		if result == nil {
+			result = &framework.Result{}
+		}
+		result.Items = append(items, result.Items...)
+		sortResultItems(result.Items)
+	}
+	if err == nil && result != nil {
+		err = locateFields(resourceList.Items, result.Items)
+	}
+	if err == nil {
+		err = gkp.writeReports(resourceList, validated, violations)
+	}
 	// When err is not nil, result should be nil.
 	if err != nil {
 		result = &framework.Result{
@@ -80,6 +155,126 @@ func (gkp *GatekeeperProcessor) Process(resourceList *framework.ResourceList) er
 	return nil
 }
 
+// writeReports writes the reports of the violations in the formats from the
+// functionConfig or the --report-formats flag, either to the files in the
+// report path or to the report ConfigMap, which replaces the previous one
+func (gkp *GatekeeperProcessor) writeReports(rl *framework.ResourceList, objects []*unstructured.Unstructured, violations []violation) error {
+	formats, path := gkp.formats, gkp.report
+	if rl.FunctionConfig != nil {
+		data := rl.FunctionConfig.GetDataMap()
+		if v := data[ReportFormatsKey]; v != "" {
+			formats = nil
+			for _, f := range strings.Split(v, ",") {
+				formats = append(formats, strings.TrimSpace(f))
+			}
+		}
+		if v := data[ReportPathKey]; v != "" {
+			path = v
+		}
+	}
+	if len(formats) == 0 {
+		return nil
+	}
+
+	reports, err := newReports(objects, violations, rl.Items)
+	if err != nil {
+		return err
+	}
+	files, err := renderReports(formats, reports)
+	if err != nil {
+		return err
+	}
+	if path != "" {
+		return writeReports(path, files)
+	}
+
+	cm, err := newReportConfigMap(files)
+	if err != nil {
+		return err
+	}
+	var items []*yaml.RNode
+	for _, item := range rl.Items {
+		if item.GetAnnotations()[ReportAnnotation] != "true" {
+			items = append(items, item)
+		}
+	}
+	rl.Items = append(items, cm)
+	return nil
+}
+
+// split splits the objects into the objects at the indices and the others
+func split(objects []*unstructured.Unstructured, indices []int) (in, out []*unstructured.Unstructured) {
+	included := make(map[int]bool)
+	for _, i := range indices {
+		included[i] = true
+	}
+	for i, obj := range objects {
+		if included[i] {
+			in = append(in, obj)
+		} else {
+			out = append(out, obj)
+		}
+	}
+	return in, out
+}
+
+// mutationEnabled returns true if the mutators are applied before the validation
+// by the --mutate flag or the functionConfig
+func (gkp *GatekeeperProcessor) mutationEnabled(fc *yaml.RNode) bool {
+	return gkp.mutation || (fc != nil && fc.GetDataMap()[MutateKey] == "true")
+}
+
+// fieldDetailsKey returns the key of the violation details holding the path of
+// the offending field, from the functionConfig or the --field-details-key flag
+func (gkp *GatekeeperProcessor) fieldDetailsKey(fc *yaml.RNode) string {
+	if fc != nil {
+		if v := fc.GetDataMap()[FieldDetailsKey]; v != "" {
+			return v
+		}
+	}
+	if gkp.fieldKey == "" {
+		return DefaultFieldDetailsKey
+	}
+	return gkp.fieldKey
+}
+
+// mutateItems applies the mutators to the objects and writes the mutated
+// objects back to the items
+func mutateItems(items []*yaml.RNode, objects []*unstructured.Unstructured, indices []int) ([]framework.ResultItem, error) {
+	mutated, results, err := Mutate(objects)
+	if err != nil {
+		return nil, err
+	}
+	for i := range mutated {
+		node, err := updateNode(items[indices[i]], objects[i])
+		if err != nil {
+			return nil, err
+		}
+		items[indices[i]] = node
+	}
+	return results, nil
+}
+
+// readInventory reads the inventory objects from the paths in the --inventory
+// flag and in the functionConfig
+func (gkp *GatekeeperProcessor) readInventory(fc *yaml.RNode) ([]*unstructured.Unstructured, error) {
+	paths := gkp.inventory
+	if fc != nil {
+		if v := fc.GetDataMap()[InventoryKey]; v != "" {
+			paths = append(paths, strings.Split(v, ",")...)
+		}
+	}
+	var inventory []*unstructured.Unstructured
+	for _, path := range paths {
+		objs, err := readInventory(strings.TrimSpace(path))
+		if err != nil {
+			return nil, err
+		}
+		inventory = append(inventory, objs...)
+	}
+	return inventory, nil
+}
+
 func (gkp *GatekeeperProcessor) ProcessInput() error {
 	content, err := os.ReadFile(gkp.input)
 	if err != nil {
@@ -143,6 +338,16 @@ func (gkp *GatekeeperProcessor) addFlags(cmd *cobra.Command) {
 		`path to the output file`)
 	cmd.Flags().BoolVar(&gkp.useJSON, "json", false,
 		`input and output is JSON instead of YAML`)
+	cmd.Flags().BoolVar(&gkp.mutation, "mutate", false,
+		`apply the mutators in the package to the other resources before validating them`)
+	cmd.Flags().StringVar(&gkp.fieldKey, "field-details-key", DefaultFieldDetailsKey,
+		`key of the violation details holding the path of the offending field`)
+	cmd.Flags().StringSliceVar(&gkp.formats, "report-formats", nil,
+		`formats of the validation reports, sarif and junit`)
+	cmd.Flags().StringVar(&gkp.report, "report-path", "",
+		`path of the directory which the reports are written to instead of the report ConfigMap`)
+	cmd.Flags().StringSliceVar(&gkp.inventory, "inventory", nil,
+		`paths to the directories or tarballs of the inventory objects of the referential constraints`)
 }
 
 func (gkp *GatekeeperProcessor) runGatekeeper() error {
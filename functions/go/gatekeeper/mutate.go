@@ -0,0 +1,477 @@
+// Copyright 2022 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	mutationsunversioned "github.com/open-policy-agent/gatekeeper/apis/mutations/unversioned"
+	"github.com/open-policy-agent/gatekeeper/pkg/mutation/match"
+	"github.com/open-policy-agent/gatekeeper/pkg/mutation/mutators"
+	"github.com/open-policy-agent/gatekeeper/pkg/mutation/mutators/core"
+	"github.com/open-policy-agent/gatekeeper/pkg/mutation/path/parser"
+	patht "github.com/open-policy-agent/gatekeeper/pkg/mutation/path/tester"
+	"github.com/open-policy-agent/gatekeeper/pkg/mutation/types"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/kustomize/kyaml/comments"
+	"sigs.k8s.io/kustomize/kyaml/fn/framework"
+	"sigs.k8s.io/kustomize/kyaml/yaml"
+)
+
+const (
+	// mutationsGroup is the API group of the Gatekeeper mutators
+	mutationsGroup = "mutations.gatekeeper.sh"
+
+	// MutateKey is the key of the functionConfig data enabling the mutation mode
+	MutateKey = "mutate"
+)
+
+// mutator is a Gatekeeper mutator which is applied to the package objects
+type mutator interface {
+	// Matches tells if the given object is eligible for this mutation
+	Matches(obj client.Object, ns *corev1.Namespace) bool
+	// Mutate applies the mutation to the given object
+	Mutate(obj *unstructured.Unstructured) (bool, error)
+	// ID returns the id of the mutator
+	ID() types.ID
+}
+
+// isMutator returns true if the object is a Gatekeeper mutator
+func isMutator(u *unstructured.Unstructured) bool {
+	return u.GroupVersionKind().Group == mutationsGroup
+}
+
+// newMutator returns the mutator for the Assign, AssignMetadata, ModifySet
+// or AssignImage object
+func newMutator(u *unstructured.Unstructured) (mutator, error) {
+	var (
+		m   mutator
+		err error
+	)
+	switch u.GetKind() {
+	case "Assign":
+		obj := &mutationsunversioned.Assign{}
+		if err = fromUnstructured(u, obj); err == nil {
+			m, err = mutators.MutatorForAssign(obj)
+		}
+	case "AssignMetadata":
+		obj := &mutationsunversioned.AssignMetadata{}
+		if err = fromUnstructured(u, obj); err == nil {
+			m, err = mutators.MutatorForAssignMetadata(obj)
+		}
+	case "ModifySet":
+		obj := &mutationsunversioned.ModifySet{}
+		if err = fromUnstructured(u, obj); err == nil {
+			m, err = mutators.MutatorForModifySet(obj)
+		}
+	case "AssignImage":
+		obj := &assignImage{}
+		if err = fromUnstructured(u, obj); err == nil {
+			m, err = mutatorForAssignImage(obj)
+		}
+	default:
+		return nil, fmt.Errorf("unsupported mutator kind %q", u.GetKind())
+	}
+	if err != nil {
+		return nil, fmt.Errorf("invalid %s %q: %w", u.GetKind(), u.GetName(), err)
+	}
+	return m, nil
+}
+
+// fromUnstructured converts the unstructured object to the typed object, the
+// versioned and unversioned mutators share the same schema
+func fromUnstructured(u *unstructured.Unstructured, obj interface{}) error {
+	b, err := json.Marshal(u.Object)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(b, obj)
+}
+
+// Mutate applies the mutators among the objects to the other objects in place,
+// in the order of the mutator IDs as Gatekeeper does, until the mutations
+// converge. It returns the indices of the mutated objects and an info result
+// item for each field changed by a mutator.
+func Mutate(objects []*unstructured.Unstructured) (map[int]bool, []framework.ResultItem, error) {
+	var ms []mutator
+	for _, obj := range objects {
+		if isMutator(obj) {
+			m, err := newMutator(obj)
+			if err != nil {
+				return nil, nil, err
+			}
+			ms = append(ms, m)
+		}
+	}
+	namespaces, err := namespaceMap(objects)
+	if err != nil {
+		return nil, nil, err
+	}
+	sort.SliceStable(ms, func(i, j int) bool { return idLess(ms[i].ID(), ms[j].ID()) })
+
+	mutated := make(map[int]bool)
+	var items []framework.ResultItem
+	for i, obj := range objects {
+		if len(ms) == 0 || isMutator(obj) || isPolicy(obj) {
+			continue
+		}
+		fields, err := mutate(ms, obj, namespaceOf(obj, namespaces))
+		if err != nil {
+			return nil, nil, err
+		}
+		for _, f := range fields {
+			item, err := newResultItem(obj)
+			if err != nil {
+				return nil, nil, err
+			}
+			item.Message = fmt.Sprintf("mutated by %s %q", f.id.Kind, f.id.Name)
+			item.Severity = framework.Info
+			item.Field = framework.Field{Path: f.path}
+			items = append(items, item)
+			mutated[i] = true
+		}
+	}
+	sortResultItems(items)
+	return mutated, items, nil
+}
+
+// updateNode returns the node for the mutated object, the comments, the order
+// of the fields and the style of the unchanged values are kept from the node
+func updateNode(node *yaml.RNode, u *unstructured.Unstructured) (*yaml.RNode, error) {
+	rn, err := yaml.FromMap(u.Object)
+	if err != nil {
+		return nil, err
+	}
+	if err := comments.CopyComments(node, rn); err != nil {
+		return nil, err
+	}
+	syncNode(node.YNode(), rn.YNode())
+	return rn, nil
+}
+
+// syncNode orders the mapping fields in to as in from, followed by the new
+// fields, and copies the style of the unchanged scalars
+func syncNode(from, to *yaml.Node) {
+	if from.Kind != to.Kind {
+		return
+	}
+	switch to.Kind {
+	case yaml.MappingNode:
+		to.Style = from.Style
+		index := make(map[string]int)
+		for i := 0; i+1 < len(to.Content); i += 2 {
+			index[to.Content[i].Value] = i
+		}
+		var content []*yaml.Node
+		for i := 0; i+1 < len(from.Content); i += 2 {
+			j, found := index[from.Content[i].Value]
+			if !found {
+				continue
+			}
+			syncNode(from.Content[i+1], to.Content[j+1])
+			content = append(content, to.Content[j], to.Content[j+1])
+			delete(index, from.Content[i].Value)
+		}
+		for i := 0; i+1 < len(to.Content); i += 2 {
+			if _, found := index[to.Content[i].Value]; found {
+				content = append(content, to.Content[i], to.Content[i+1])
+			}
+		}
+		to.Content = content
+	case yaml.SequenceNode:
+		to.Style = from.Style
+		for i := 0; i < len(from.Content) && i < len(to.Content); i++ {
+			syncNode(from.Content[i], to.Content[i])
+		}
+	case yaml.ScalarNode:
+		if from.Value == to.Value {
+			to.Style = from.Style
+			to.Tag = from.Tag
+		}
+	}
+}
+
+// mutatedField is a field changed by a mutator
+type mutatedField struct {
+	id   types.ID
+	path string
+}
+
+// mutate applies the mutators to the object until the mutations converge and
+// returns the fields changed by each mutator
+func mutate(ms []mutator, obj *unstructured.Unstructured, ns *corev1.Namespace) ([]mutatedField, error) {
+	var fields []mutatedField
+	seen := make(map[mutatedField]bool)
+	for iteration := 0; iteration <= len(ms); iteration++ {
+		old := obj.DeepCopy()
+		for _, m := range ms {
+			if !m.Matches(obj, ns) {
+				continue
+			}
+			before := obj.DeepCopy()
+			if _, err := m.Mutate(obj); err != nil {
+				return nil, fmt.Errorf("mutator %v failed for %s %q: %w", m.ID(), obj.GetKind(), obj.GetName(), err)
+			}
+			for _, path := range changedFields(before.Object, obj.Object, "") {
+				f := mutatedField{id: m.ID(), path: path}
+				if !seen[f] {
+					seen[f] = true
+					fields = append(fields, f)
+				}
+			}
+		}
+		if reflect.DeepEqual(old.Object, obj.Object) {
+			return fields, nil
+		}
+	}
+	return nil, fmt.Errorf("mutation not converging for %s %q", obj.GetKind(), obj.GetName())
+}
+
+// namespaceMap returns the Namespaces among the objects by their names
+func namespaceMap(objects []*unstructured.Unstructured) (map[string]*corev1.Namespace, error) {
+	namespaces := make(map[string]*corev1.Namespace)
+	for _, obj := range objects {
+		if obj.GroupVersionKind() == corev1.SchemeGroupVersion.WithKind("Namespace") {
+			ns := &corev1.Namespace{}
+			if err := runtime.DefaultUnstructuredConverter.FromUnstructured(obj.Object, ns); err != nil {
+				return nil, err
+			}
+			namespaces[ns.Name] = ns
+		}
+	}
+	return namespaces, nil
+}
+
+// namespaceOf returns the namespace to match the mutators against the object,
+// the namespace is looked up in the package or it only has a name
+func namespaceOf(obj *unstructured.Unstructured, namespaces map[string]*corev1.Namespace) *corev1.Namespace {
+	if obj.GroupVersionKind() == corev1.SchemeGroupVersion.WithKind("Namespace") {
+		if ns, found := namespaces[obj.GetName()]; found {
+			return ns
+		}
+	}
+	if obj.GetNamespace() == "" {
+		return nil
+	}
+	if ns, found := namespaces[obj.GetNamespace()]; found {
+		return ns
+	}
+	return &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: obj.GetNamespace()}}
+}
+
+// idLess orders the mutator IDs by group, kind, namespace and name
+func idLess(a, b types.ID) bool {
+	if a.Group != b.Group {
+		return a.Group < b.Group
+	}
+	if a.Kind != b.Kind {
+		return a.Kind < b.Kind
+	}
+	if a.Namespace != b.Namespace {
+		return a.Namespace < b.Namespace
+	}
+	return a.Name < b.Name
+}
+
+// changedFields returns the paths of the fields which are different in the
+// old and the new values e.g. spec.template.spec.containers[0].image, the
+// fields of the new maps are reported instead of the maps
+func changedFields(old, new interface{}, path string) []string {
+	switch n := new.(type) {
+	case map[string]interface{}:
+		o, ok := old.(map[string]interface{})
+		if !ok && old != nil {
+			break
+		}
+		var out []string
+		for _, k := range unionKeys(o, n) {
+			out = append(out, changedFields(o[k], n[k], joinField(path, k))...)
+		}
+		return out
+	case []interface{}:
+		o, ok := old.([]interface{})
+		if !ok || len(o) != len(n) {
+			break
+		}
+		var out []string
+		for i := range n {
+			out = append(out, changedFields(o[i], n[i], fmt.Sprintf("%s[%d]", path, i))...)
+		}
+		return out
+	}
+	if reflect.DeepEqual(old, new) {
+		return nil
+	}
+	return []string{path}
+}
+
+// unionKeys returns the sorted keys of both maps
+func unionKeys(a, b map[string]interface{}) []string {
+	var keys []string
+	for k := range a {
+		keys = append(keys, k)
+	}
+	for k := range b {
+		if _, found := a[k]; !found {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// joinField appends the field to the path
+func joinField(path, field string) string {
+	if path == "" {
+		return field
+	}
+	return path + "." + field
+}
+
+// assignImage is the AssignImage mutator, which sets the domain, path and tag
+// components of the image at the location
+type assignImage struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+	Spec              assignImageSpec `json:"spec,omitempty"`
+}
+
+type assignImageSpec struct {
+	ApplyTo    []match.ApplyTo       `json:"applyTo,omitempty"`
+	Match      match.Match           `json:"match,omitempty"`
+	Location   string                `json:"location,omitempty"`
+	Parameters assignImageParameters `json:"parameters,omitempty"`
+}
+
+type assignImageParameters struct {
+	PathTests    []mutationsunversioned.PathTest `json:"pathTests,omitempty"`
+	AssignDomain string                          `json:"assignDomain,omitempty"`
+	AssignPath   string                          `json:"assignPath,omitempty"`
+	AssignTag    string                          `json:"assignTag,omitempty"`
+}
+
+// assignImageMutator applies the AssignImage mutator
+type assignImageMutator struct {
+	id     types.ID
+	spec   assignImageSpec
+	path   parser.Path
+	tester *patht.Tester
+}
+
+func mutatorForAssignImage(a *assignImage) (*assignImageMutator, error) {
+	p := a.Spec.Parameters
+	if p.AssignDomain == "" && p.AssignPath == "" && p.AssignTag == "" {
+		return nil, fmt.Errorf("at least one of assignDomain, assignPath or assignTag must be set")
+	}
+	if p.AssignTag != "" && !strings.HasPrefix(p.AssignTag, ":") && !strings.HasPrefix(p.AssignTag, "@") {
+		return nil, fmt.Errorf("assignTag %q must start with : or @", p.AssignTag)
+	}
+	path, err := parser.Parse(a.Spec.Location)
+	if err != nil {
+		return nil, fmt.Errorf("invalid location format %q: %w", a.Spec.Location, err)
+	}
+	if len(path.Nodes) == 0 || path.Nodes[len(path.Nodes)-1].Type() != parser.ObjectNode {
+		return nil, fmt.Errorf("location %q must be a field", a.Spec.Location)
+	}
+	var tests []patht.Test
+	for _, pt := range p.PathTests {
+		sp, err := parser.Parse(pt.SubPath)
+		if err != nil {
+			return nil, fmt.Errorf("invalid sub path %q: %w", pt.SubPath, err)
+		}
+		tests = append(tests, patht.Test{SubPath: sp, Condition: pt.Condition})
+	}
+	tester, err := patht.New(path, tests)
+	if err != nil {
+		return nil, err
+	}
+	return &assignImageMutator{
+		id:     types.ID{Group: mutationsGroup, Kind: "AssignImage", Namespace: a.Namespace, Name: a.Name},
+		spec:   a.Spec,
+		path:   path,
+		tester: tester,
+	}, nil
+}
+
+func (m *assignImageMutator) Matches(obj client.Object, ns *corev1.Namespace) bool {
+	if !match.AppliesTo(m.spec.ApplyTo, obj) {
+		return false
+	}
+	matches, err := match.Matches(&m.spec.Match, obj, ns)
+	return err == nil && matches
+}
+
+func (m *assignImageMutator) Mutate(obj *unstructured.Unstructured) (bool, error) {
+	return core.Mutate(m.path, m.tester, &imageSetter{parameters: m.spec.Parameters}, obj)
+}
+
+func (m *assignImageMutator) ID() types.ID {
+	return m.id
+}
+
+// imageSetter sets the components of the image
+type imageSetter struct {
+	parameters assignImageParameters
+}
+
+func (s *imageSetter) SetValue(obj map[string]interface{}, key string) error {
+	image, _ := obj[key].(string)
+	domain, path, tag := splitImage(image)
+	if s.parameters.AssignDomain != "" {
+		domain = s.parameters.AssignDomain
+	}
+	if s.parameters.AssignPath != "" {
+		path = s.parameters.AssignPath
+	}
+	if s.parameters.AssignTag != "" {
+		tag = s.parameters.AssignTag
+	}
+	if domain != "" {
+		path = domain + "/" + path
+	}
+	obj[key] = path + tag
+	return nil
+}
+
+func (s *imageSetter) KeyedListOkay() bool { return false }
+
+func (s *imageSetter) KeyedListValue() (map[string]interface{}, error) {
+	return nil, fmt.Errorf("AssignImage can not assign a keyed list element")
+}
+
+// splitImage splits the image into the domain, path and tag (or digest)
+// components e.g. gcr.io/project/nginx:1.7.1 is split into gcr.io,
+// project/nginx and :1.7.1
+func splitImage(image string) (domain, path, tag string) {
+	if i := strings.Index(image, "@"); i >= 0 {
+		image, tag = image[:i], image[i:]
+	} else if i := strings.LastIndex(image, ":"); i > strings.LastIndex(image, "/") {
+		image, tag = image[:i], image[i:]
+	}
+	if i := strings.Index(image, "/"); i >= 0 {
+		if d := image[:i]; strings.ContainsAny(d, ".:") || d == "localhost" {
+			return d, image[i+1:], tag
+		}
+	}
+	return "", image, tag
+}
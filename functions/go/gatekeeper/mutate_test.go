@@ -0,0 +1,166 @@
+package main
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+
+	"sigs.k8s.io/kustomize/kyaml/fn/framework"
+	"sigs.k8s.io/kustomize/kyaml/kio"
+	"sigs.k8s.io/kustomize/kyaml/yaml"
+)
+
+const mutatorPolicies = `apiVersion: mutations.gatekeeper.sh/v1beta1
+kind: Assign
+metadata:
+  name: run-as-non-root
+spec:
+  applyTo:
+    - groups: ["apps"]
+      kinds: ["Deployment"]
+      versions: ["v1"]
+  location: spec.template.spec.securityContext.runAsNonRoot
+  parameters:
+    assign:
+      value: true
+---
+apiVersion: mutations.gatekeeper.sh/v1beta1
+kind: AssignMetadata
+metadata:
+  name: owner
+spec:
+  location: metadata.labels.owner
+  parameters:
+    assign:
+      value: team-a
+---
+apiVersion: mutations.gatekeeper.sh/v1alpha1
+kind: ModifySet
+metadata:
+  name: debug-args
+spec:
+  applyTo:
+    - groups: ["apps"]
+      kinds: ["Deployment"]
+      versions: ["v1"]
+  location: spec.template.spec.containers[name:nginx].args
+  parameters:
+    operation: merge
+    values:
+      fromList:
+        - --debug
+---
+apiVersion: mutations.gatekeeper.sh/v1alpha1
+kind: AssignImage
+metadata:
+  name: registry
+spec:
+  applyTo:
+    - groups: ["apps"]
+      kinds: ["Deployment"]
+      versions: ["v1"]
+  location: spec.template.spec.containers[name:*].image
+  parameters:
+    assignDomain: us-docker.pkg.dev
+`
+
+func TestMutate(t *testing.T) {
+	input := mutatorPolicies + `---
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: nginx
+  annotations:
+    config.kubernetes.io/path: deployment.yaml
+spec:
+  replicas: 3 # kpt-set: ${replicas}
+  template:
+    spec:
+      containers:
+        - name: nginx
+          image: "gcr.io/nginx:1.7.1"
+          args: [--port=80]
+`
+	expected := `apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: nginx
+  annotations:
+    config.kubernetes.io/path: deployment.yaml
+  labels:
+    owner: team-a
+spec:
+  replicas: 3 # kpt-set: ${replicas}
+  template:
+    spec:
+      containers:
+        - name: nginx
+          image: us-docker.pkg.dev/nginx:1.7.1
+          args: [--port=80, --debug]
+      securityContext:
+        runAsNonRoot: true
+`
+	items, err := (&kio.ByteReader{Reader: strings.NewReader(input), OmitReaderAnnotations: true}).Read()
+	if err != nil {
+		t.Fatal(err)
+	}
+	objects, err := parseInventory([]byte(input), "input.yaml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	indices := make([]int, len(objects))
+	for i := range indices {
+		indices[i] = i
+	}
+
+	results, err := mutateItems(items, objects, indices)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var out bytes.Buffer
+	if err := (kio.ByteWriter{Writer: &out}).Write(items[len(items)-1:]); err != nil {
+		t.Fatal(err)
+	}
+	if out.String() != expected {
+		t.Errorf("expect:\n%s\nbut got:\n%s", expected, out.String())
+	}
+
+	ref := yaml.ResourceIdentifier{
+		TypeMeta: yaml.TypeMeta{APIVersion: "apps/v1", Kind: "Deployment"},
+		NameMeta: yaml.NameMeta{Name: "nginx"},
+	}
+	file := framework.File{Path: "deployment.yaml"}
+	expectedResults := []framework.ResultItem{
+		{Message: `mutated by AssignMetadata "owner"`, Severity: framework.Info, ResourceRef: ref, File: file,
+			Field: framework.Field{Path: "metadata.labels.owner"}},
+		{Message: `mutated by ModifySet "debug-args"`, Severity: framework.Info, ResourceRef: ref, File: file,
+			Field: framework.Field{Path: "spec.template.spec.containers[0].args"}},
+		{Message: `mutated by AssignImage "registry"`, Severity: framework.Info, ResourceRef: ref, File: file,
+			Field: framework.Field{Path: "spec.template.spec.containers[0].image"}},
+		{Message: `mutated by Assign "run-as-non-root"`, Severity: framework.Info, ResourceRef: ref, File: file,
+			Field: framework.Field{Path: "spec.template.spec.securityContext.runAsNonRoot"}},
+	}
+	if !reflect.DeepEqual(results, expectedResults) {
+		t.Errorf("expect: %#v, but got: %#v", expectedResults, results)
+	}
+}
+
+func TestSplitImage(t *testing.T) {
+	testcases := []struct {
+		image, domain, path, tag string
+	}{
+		{image: "nginx", path: "nginx"},
+		{image: "nginx:1.7.1", path: "nginx", tag: ":1.7.1"},
+		{image: "gcr.io/project/nginx:1.7.1", domain: "gcr.io", path: "project/nginx", tag: ":1.7.1"},
+		{image: "localhost:5000/nginx@sha256:abc", domain: "localhost:5000", path: "nginx", tag: "@sha256:abc"},
+		{image: "library/nginx", path: "library/nginx"},
+	}
+	for _, tc := range testcases {
+		domain, path, tag := splitImage(tc.image)
+		if domain != tc.domain || path != tc.path || tag != tc.tag {
+			t.Errorf("in testcase %q, expect: %q %q %q, but got: %q %q %q",
+				tc.image, tc.domain, tc.path, tc.tag, domain, path, tag)
+		}
+	}
+}
@@ -0,0 +1,510 @@
+// Copyright 2022 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/open-policy-agent/gatekeeper/pkg/mutation/match"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/kustomize/kyaml/fn/framework"
+	"sigs.k8s.io/kustomize/kyaml/kio/kioutil"
+	"sigs.k8s.io/kustomize/kyaml/yaml"
+)
+
+const (
+	// ReportFormatsKey is the key of the functionConfig data listing the comma
+	// separated formats of the validation reports, sarif and junit
+	ReportFormatsKey = "report-formats"
+
+	// ReportPathKey is the key of the functionConfig data providing the
+	// directory which the reports are written to. The reports are written to
+	// a local config ConfigMap in the package when it's empty.
+	ReportPathKey = "report-path"
+
+	// ReportAnnotation marks the ConfigMap holding the reports, which is
+	// replaced on each run and is not validated
+	ReportAnnotation = "gatekeeper.kpt.dev/report"
+
+	// SARIFFormat is the SARIF 2.1.0 report format
+	SARIFFormat = "sarif"
+
+	// JUnitFormat is the JUnit XML report format
+	JUnitFormat = "junit"
+
+	// reportName is the name of the ConfigMap and of the file holding the reports
+	reportName = "gatekeeper-report"
+)
+
+// reportFiles are the names of the report files, which are also the keys of
+// the report ConfigMap data
+var reportFiles = map[string]string{
+	SARIFFormat: "gatekeeper.sarif",
+	JUnitFormat: "gatekeeper.junit.xml",
+}
+
+// isReport returns true if the object is the ConfigMap holding the reports
+func isReport(u *unstructured.Unstructured) bool {
+	return u.GetAnnotations()[ReportAnnotation] == "true"
+}
+
+// constraintReport is the outcome of the validation of the package resources
+// against a constraint or a ValidatingAdmissionPolicyBinding
+type constraintReport struct {
+	kind string
+	name string
+	// matches returns true if the object is validated by the constraint or
+	// by the policy of the binding
+	matches func(obj *unstructured.Unstructured, ns *corev1.Namespace) (bool, error)
+	// passed and failed are the numbers of the resources matched by the
+	// constraint which pass and fail the validation
+	passed int
+	failed int
+	// violations are the violations of the constraint
+	violations []violation
+}
+
+// id returns the identifier of the constraint in the reports
+func (r *constraintReport) id() string {
+	return r.kind + "/" + r.name
+}
+
+// violation is a violation of a constraint or of a binding by a resource
+type violation struct {
+	// report is the id of the report of the violated constraint or binding
+	report string
+	item   framework.ResultItem
+	// message is the message of the violation, without the names of the
+	// violated constraint or binding which the result item message ends with
+	message string
+	line    int
+	column  int
+}
+
+// newReports returns the reports of the constraints and of the
+// ValidatingAdmissionPolicyBindings among the validated objects, from the
+// violations
+func newReports(objects []*unstructured.Unstructured, violations []violation, items []*yaml.RNode) ([]*constraintReport, error) {
+	namespaces, err := namespaceMap(objects)
+	if err != nil {
+		return nil, err
+	}
+	l, err := newFileLocator(items)
+	if err != nil {
+		return nil, err
+	}
+	reports, err := constraintReports(objects)
+	if err != nil {
+		return nil, err
+	}
+	bindings, err := bindingReports(objects)
+	if err != nil {
+		return nil, err
+	}
+	reports = append(reports, bindings...)
+
+	byID := make(map[string]*constraintReport)
+	failing := make(map[string]map[string]bool)
+	for _, r := range reports {
+		byID[r.id()] = r
+		failing[r.id()] = make(map[string]bool)
+	}
+
+	for _, v := range sortViolations(violations) {
+		r, found := byID[v.report]
+		if !found {
+			continue
+		}
+		if v.item.Field.Path != "" {
+			v.line, v.column, _ = l.locate(v.item.File, v.item.Field.Path)
+		}
+		r.violations = append(r.violations, v)
+		failing[r.id()][resourceKey(v.item.ResourceRef)] = true
+	}
+
+	for _, r := range reports {
+		matched := make(map[string]bool)
+		for _, o := range objects {
+			if isPolicy(o) || isAdmissionPolicy(o) {
+				continue
+			}
+			ok, err := r.matches(o, namespaceOf(o, namespaces))
+			if err != nil {
+				return nil, err
+			}
+			if ok {
+				matched[objectRefKey(o)] = true
+			}
+		}
+		for key := range failing[r.id()] {
+			matched[key] = true
+		}
+		r.failed = len(failing[r.id()])
+		r.passed = len(matched) - r.failed
+	}
+	return reports, nil
+}
+
+// constraintReports returns the empty reports of the constraints
+func constraintReports(objects []*unstructured.Unstructured) ([]*constraintReport, error) {
+	var reports []*constraintReport
+	seen := make(map[string]bool)
+	for _, obj := range objects {
+		if obj.GroupVersionKind().Group != "constraints.gatekeeper.sh" {
+			continue
+		}
+		r := &constraintReport{kind: obj.GetKind(), name: obj.GetName()}
+		if seen[r.id()] {
+			continue
+		}
+		seen[r.id()] = true
+		m := &match.Match{}
+		if spec, found, _ := unstructured.NestedMap(obj.Object, "spec", "match"); found {
+			if err := runtime.DefaultUnstructuredConverter.FromUnstructured(spec, m); err != nil {
+				return nil, fmt.Errorf("unable to parse the match of %s %q: %w", r.kind, r.name, err)
+			}
+		}
+		r.matches = func(o *unstructured.Unstructured, ns *corev1.Namespace) (bool, error) {
+			return match.Matches(m, o, ns)
+		}
+		reports = append(reports, r)
+	}
+	return reports, nil
+}
+
+// bindingReports returns the empty reports of the
+// ValidatingAdmissionPolicyBindings whose policy is among the objects
+func bindingReports(objects []*unstructured.Unstructured) ([]*constraintReport, error) {
+	policies := make(map[string]*admissionPolicy)
+	var bindings []*policyBinding
+	for _, obj := range objects {
+		if !isAdmissionPolicy(obj) {
+			continue
+		}
+		if obj.GetKind() == "ValidatingAdmissionPolicy" {
+			p := &admissionPolicy{}
+			if err := fromUnstructured(obj, p); err != nil {
+				return nil, fmt.Errorf("unable to parse ValidatingAdmissionPolicy %q: %w", obj.GetName(), err)
+			}
+			policies[p.Name] = p
+		} else {
+			b := &policyBinding{}
+			if err := fromUnstructured(obj, b); err != nil {
+				return nil, fmt.Errorf("unable to parse ValidatingAdmissionPolicyBinding %q: %w", obj.GetName(), err)
+			}
+			bindings = append(bindings, b)
+		}
+	}
+	sort.SliceStable(bindings, func(i, j int) bool { return bindings[i].Name < bindings[j].Name })
+
+	var reports []*constraintReport
+	for _, b := range bindings {
+		p, found := policies[b.Spec.PolicyName]
+		if !found {
+			// the binding has no effect until its policy is created
+			continue
+		}
+		b := b
+		reports = append(reports, &constraintReport{
+			kind: "ValidatingAdmissionPolicyBinding",
+			name: b.Name,
+			matches: func(o *unstructured.Unstructured, ns *corev1.Namespace) (bool, error) {
+				return p.Spec.MatchConstraints.matches(o, ns, false) && b.Spec.MatchResources.matches(o, ns, true), nil
+			},
+		})
+	}
+	return reports, nil
+}
+
+// sortViolations returns the violations in the order of their result items
+func sortViolations(violations []violation) []violation {
+	items := make([]framework.ResultItem, len(violations))
+	order := make([]int, len(violations))
+	for i, v := range violations {
+		items[i] = v.item
+		order[i] = i
+	}
+	sort.SliceStable(order, func(i, j int) bool {
+		return resultItemLess(items, order[i], order[j])
+	})
+	sorted := make([]violation, len(violations))
+	for i, o := range order {
+		sorted[i] = violations[o]
+	}
+	return sorted
+}
+
+// resourceKey returns the key identifying the resource of the result item
+func resourceKey(ref yaml.ResourceIdentifier) string {
+	return fmt.Sprintf("%s/%s/%s/%s", ref.APIVersion, ref.Kind, ref.Namespace, ref.Name)
+}
+
+// objectRefKey returns the key identifying the object, same as the key of the
+// resource referenced by its result items
+func objectRefKey(u *unstructured.Unstructured) string {
+	return fmt.Sprintf("%s/%s/%s/%s", u.GetAPIVersion(), u.GetKind(), u.GetNamespace(), u.GetName())
+}
+
+// sarifLevels are the SARIF levels of the result severities
+var sarifLevels = map[framework.Severity]string{
+	framework.Error:   "error",
+	framework.Warning: "warning",
+	framework.Info:    "note",
+}
+
+type sarifLog struct {
+	Version string     `json:"version"`
+	Schema  string     `json:"$schema"`
+	Runs    []sarifRun `json:"runs"`
+}
+
+type sarifRun struct {
+	Tool    sarifTool     `json:"tool"`
+	Results []sarifResult `json:"results"`
+}
+
+type sarifTool struct {
+	Driver sarifDriver `json:"driver"`
+}
+
+type sarifDriver struct {
+	Name           string      `json:"name"`
+	InformationURI string      `json:"informationUri"`
+	Rules          []sarifRule `json:"rules"`
+}
+
+type sarifRule struct {
+	ID         string         `json:"id"`
+	Name       string         `json:"name"`
+	Properties map[string]int `json:"properties"`
+}
+
+type sarifResult struct {
+	RuleID    string          `json:"ruleId"`
+	Level     string          `json:"level"`
+	Message   sarifMessage    `json:"message"`
+	Locations []sarifLocation `json:"locations"`
+}
+
+type sarifMessage struct {
+	Text string `json:"text"`
+}
+
+type sarifLocation struct {
+	PhysicalLocation *sarifPhysicalLocation `json:"physicalLocation,omitempty"`
+	LogicalLocations []sarifLogicalLocation `json:"logicalLocations"`
+}
+
+type sarifPhysicalLocation struct {
+	ArtifactLocation sarifArtifactLocation `json:"artifactLocation"`
+	Region           *sarifRegion          `json:"region,omitempty"`
+}
+
+type sarifArtifactLocation struct {
+	URI string `json:"uri"`
+}
+
+type sarifRegion struct {
+	StartLine   int `json:"startLine"`
+	StartColumn int `json:"startColumn"`
+}
+
+type sarifLogicalLocation struct {
+	FullyQualifiedName string `json:"fullyQualifiedName"`
+	Kind               string `json:"kind"`
+}
+
+// sarifReport returns the SARIF 2.1.0 report of the constraints and the
+// bindings. Each constraint or binding is a rule, whose properties hold the
+// numbers of the passed and failed resources.
+func sarifReport(reports []*constraintReport) ([]byte, error) {
+	run := sarifRun{
+		Tool: sarifTool{Driver: sarifDriver{
+			Name:           "gatekeeper",
+			InformationURI: "https://github.com/GoogleContainerTools/kpt-functions-catalog/tree/master/functions/go/gatekeeper",
+			Rules:          []sarifRule{},
+		}},
+		Results: []sarifResult{},
+	}
+	for _, r := range reports {
+		run.Tool.Driver.Rules = append(run.Tool.Driver.Rules, sarifRule{
+			ID:         r.id(),
+			Name:       r.name,
+			Properties: map[string]int{"passed": r.passed, "failed": r.failed},
+		})
+		for _, v := range r.violations {
+			location := sarifLocation{
+				LogicalLocations: []sarifLogicalLocation{{
+					FullyQualifiedName: resourceKey(v.item.ResourceRef),
+					Kind:               "resource",
+				}},
+			}
+			if v.item.File.Path != "" {
+				location.PhysicalLocation = &sarifPhysicalLocation{
+					ArtifactLocation: sarifArtifactLocation{URI: v.item.File.Path},
+				}
+				if v.line > 0 {
+					location.PhysicalLocation.Region = &sarifRegion{StartLine: v.line, StartColumn: v.column}
+				}
+			}
+			run.Results = append(run.Results, sarifResult{
+				RuleID:    r.id(),
+				Level:     sarifLevels[v.item.Severity],
+				Message:   sarifMessage{Text: v.message},
+				Locations: []sarifLocation{location},
+			})
+		}
+	}
+	return json.MarshalIndent(sarifLog{
+		Version: "2.1.0",
+		Schema:  "https://json.schemastore.org/sarif-2.1.0.json",
+		Runs:    []sarifRun{run},
+	}, "", "  ")
+}
+
+type junitTestSuites struct {
+	XMLName  xml.Name         `xml:"testsuites"`
+	Name     string           `xml:"name,attr"`
+	Tests    int              `xml:"tests,attr"`
+	Failures int              `xml:"failures,attr"`
+	Suites   []junitTestSuite `xml:"testsuite"`
+}
+
+type junitTestSuite struct {
+	Name     string          `xml:"name,attr"`
+	Tests    int             `xml:"tests,attr"`
+	Failures int             `xml:"failures,attr"`
+	Cases    []junitTestCase `xml:"testcase"`
+}
+
+type junitTestCase struct {
+	Name      string         `xml:"name,attr"`
+	ClassName string         `xml:"classname,attr"`
+	Failures  []junitFailure `xml:"failure"`
+}
+
+type junitFailure struct {
+	Message string `xml:"message,attr"`
+	Type    string `xml:"type,attr"`
+	Text    string `xml:",chardata"`
+}
+
+// junitReport returns the JUnit XML report of the constraints and the
+// bindings. Each constraint or binding is a test suite, and each resource
+// failing it is a failed test case. The resources passing it are only counted
+// in the tests of the test suite.
+func junitReport(reports []*constraintReport) ([]byte, error) {
+	suites := junitTestSuites{Name: "gatekeeper"}
+	for _, r := range reports {
+		suite := junitTestSuite{Name: r.id(), Tests: r.passed + r.failed, Failures: r.failed}
+		cases := make(map[string]*junitTestCase)
+		var keys []string
+		for _, v := range r.violations {
+			key := resourceKey(v.item.ResourceRef)
+			if cases[key] == nil {
+				cases[key] = &junitTestCase{Name: key, ClassName: r.id()}
+				keys = append(keys, key)
+			}
+			text := v.message
+			if v.item.File.Path != "" {
+				text = fmt.Sprintf("%s\nfile: %s", text, v.item.File.Path)
+				if v.line > 0 {
+					text = fmt.Sprintf("%s:%d:%d", text, v.line, v.column)
+				}
+			}
+			cases[key].Failures = append(cases[key].Failures, junitFailure{
+				Message: v.message,
+				Type:    string(v.item.Severity),
+				Text:    text,
+			})
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			suite.Cases = append(suite.Cases, *cases[key])
+		}
+		suites.Suites = append(suites.Suites, suite)
+		suites.Tests += suite.Tests
+		suites.Failures += suite.Failures
+	}
+	out, err := xml.MarshalIndent(suites, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append([]byte(xml.Header), out...), nil
+}
+
+// renderReports returns the reports in the formats by their file names
+func renderReports(formats []string, reports []*constraintReport) (map[string]string, error) {
+	files := make(map[string]string)
+	for _, format := range formats {
+		var content []byte
+		var err error
+		switch format {
+		case SARIFFormat:
+			content, err = sarifReport(reports)
+		case JUnitFormat:
+			content, err = junitReport(reports)
+		default:
+			return nil, fmt.Errorf("unknown report format %q, must be one of %s or %s",
+				format, SARIFFormat, JUnitFormat)
+		}
+		if err != nil {
+			return nil, err
+		}
+		files[reportFiles[format]] = string(content)
+	}
+	return files, nil
+}
+
+// writeReports writes the report files to the directory
+func writeReports(dir string, files map[string]string) error {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return fmt.Errorf("unable to write reports: %w", err)
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+			return fmt.Errorf("unable to write reports: %w", err)
+		}
+	}
+	return nil
+}
+
+// newReportConfigMap returns the local config ConfigMap holding the report files
+func newReportConfigMap(files map[string]string) (*yaml.RNode, error) {
+	cm := yaml.NewMapRNode(nil)
+	for _, f := range []yaml.Filter{
+		yaml.SetField(yaml.APIVersionField, yaml.NewScalarRNode("v1")),
+		yaml.SetField(yaml.KindField, yaml.NewScalarRNode("ConfigMap")),
+		yaml.SetK8sName(reportName),
+		yaml.SetAnnotation("config.kubernetes.io/local-config", "true"),
+		yaml.SetAnnotation(ReportAnnotation, "true"),
+		yaml.SetAnnotation(kioutil.PathAnnotation, reportName+".yaml"),
+	} {
+		if _, err := cm.Pipe(f); err != nil {
+			return nil, err
+		}
+	}
+	if err := cm.LoadMapIntoConfigMapData(files); err != nil {
+		return nil, err
+	}
+	return cm, nil
+}
@@ -0,0 +1,257 @@
+package main
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"sigs.k8s.io/kustomize/kyaml/fn/framework"
+	"sigs.k8s.io/kustomize/kyaml/kio"
+	"sigs.k8s.io/kustomize/kyaml/yaml"
+)
+
+const reportInput = requiredNamespacePolicy + `---
+apiVersion: v1
+kind: Namespace
+metadata:
+  name: dev
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: dev-config
+  namespace: dev
+---
+apiVersion: v1
+kind: Secret
+metadata:
+  name: dev-secret
+  namespace: dev
+`
+
+const expectedJUnit = `<?xml version="1.0" encoding="UTF-8"?>
+<testsuites name="gatekeeper" tests="2" failures="1">
+  <testsuite name="K8sRequiredNamespace/required-namespace" tests="2" failures="1">
+    <testcase name="v1/ConfigMap/prod/app-config" classname="K8sRequiredNamespace/required-namespace">
+      <failure message="namespace prod does not exist" type="error">namespace prod does not exist</failure>
+    </testcase>
+  </testsuite>
+</testsuites>`
+
+func TestReports(t *testing.T) {
+	dir := t.TempDir()
+	testcases := []struct {
+		name string
+		data map[string]string
+	}{
+		{
+			name: "report ConfigMap",
+			data: map[string]string{ReportFormatsKey: "sarif, junit"},
+		},
+		{
+			name: "report path",
+			data: map[string]string{ReportFormatsKey: "sarif,junit", ReportPathKey: dir},
+		},
+	}
+
+	for _, tc := range testcases {
+		input := reportInput
+		if tc.data[ReportPathKey] == "" {
+			// the report of the previous run is replaced
+			input += `---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: gatekeeper-report
+  annotations:
+    gatekeeper.kpt.dev/report: "true"
+data:
+  gatekeeper.sarif: "{}"
+`
+		}
+		items, err := (&kio.ByteReader{Reader: strings.NewReader(input), OmitReaderAnnotations: true}).Read()
+		if err != nil {
+			t.Fatalf("in testcase %q, unexpected error: %v", tc.name, err)
+		}
+		fc := yaml.NewMapRNode(nil)
+		if err := fc.LoadMapIntoConfigMapData(tc.data); err != nil {
+			t.Fatalf("in testcase %q, unexpected error: %v", tc.name, err)
+		}
+		rl := &framework.ResourceList{Items: items, FunctionConfig: fc}
+		if err := (&GatekeeperProcessor{}).Process(rl); err == nil {
+			t.Fatalf("in testcase %q, expect violations", tc.name)
+		}
+
+		files := make(map[string]string)
+		if path := tc.data[ReportPathKey]; path != "" {
+			for _, name := range reportFiles {
+				content, err := os.ReadFile(filepath.Join(path, name))
+				if err != nil {
+					t.Fatalf("in testcase %q, unexpected error: %v", tc.name, err)
+				}
+				files[name] = string(content)
+			}
+		} else {
+			var reports []*yaml.RNode
+			for _, item := range rl.Items {
+				if item.GetName() == reportName {
+					reports = append(reports, item)
+				}
+			}
+			if len(reports) != 1 {
+				t.Fatalf("in testcase %q, expect 1 report ConfigMap, but got: %d", tc.name, len(reports))
+			}
+			files = reports[0].GetDataMap()
+		}
+
+		if files[reportFiles[JUnitFormat]] != expectedJUnit {
+			t.Errorf("in testcase %q, expect:\n%s\nbut got:\n%s", tc.name, expectedJUnit, files[reportFiles[JUnitFormat]])
+		}
+		var sarif sarifLog
+		if err := json.Unmarshal([]byte(files[reportFiles[SARIFFormat]]), &sarif); err != nil {
+			t.Fatalf("in testcase %q, unexpected error: %v", tc.name, err)
+		}
+		expectedRules := []sarifRule{{
+			ID:         "K8sRequiredNamespace/required-namespace",
+			Name:       "required-namespace",
+			Properties: map[string]int{"passed": 1, "failed": 1},
+		}}
+		if !reflect.DeepEqual(sarif.Runs[0].Tool.Driver.Rules, expectedRules) {
+			t.Errorf("in testcase %q, expect: %+v, but got: %+v", tc.name, expectedRules, sarif.Runs[0].Tool.Driver.Rules)
+		}
+		expectedResults := []sarifResult{{
+			RuleID:  "K8sRequiredNamespace/required-namespace",
+			Level:   "error",
+			Message: sarifMessage{Text: "namespace prod does not exist"},
+			Locations: []sarifLocation{{
+				LogicalLocations: []sarifLogicalLocation{{FullyQualifiedName: "v1/ConfigMap/prod/app-config", Kind: "resource"}},
+			}},
+		}}
+		if !reflect.DeepEqual(sarif.Runs[0].Results, expectedResults) {
+			t.Errorf("in testcase %q, expect: %+v, but got: %+v", tc.name, expectedResults, sarif.Runs[0].Results)
+		}
+	}
+}
+
+func TestReportsSameConstraintName(t *testing.T) {
+	objects, err := parseInventory([]byte(`apiVersion: constraints.gatekeeper.sh/v1beta1
+kind: K8sRequiredLabels
+metadata:
+  name: prod
+---
+apiVersion: constraints.gatekeeper.sh/v1beta1
+kind: K8sAllowedRepos
+metadata:
+  name: prod
+---
+apiVersion: v1
+kind: Pod
+metadata:
+  name: nginx
+  namespace: default
+`), "input.yaml")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	violations := []violation{{
+		report: "K8sAllowedRepos/prod",
+		item: framework.ResultItem{
+			Message:     "container nginx has an invalid image repo" + violatedConstraintPrefix + "prod",
+			Severity:    framework.Error,
+			ResourceRef: yaml.ResourceIdentifier{TypeMeta: yaml.TypeMeta{APIVersion: "v1", Kind: "Pod"}, NameMeta: yaml.NameMeta{Name: "nginx", Namespace: "default"}},
+		},
+		message: "container nginx has an invalid image repo",
+	}}
+	reports, err := newReports(objects, violations, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	type outcome struct {
+		passed, failed, violations int
+	}
+	got := make(map[string]outcome)
+	for _, r := range reports {
+		got[r.id()] = outcome{passed: r.passed, failed: r.failed, violations: len(r.violations)}
+	}
+	expected := map[string]outcome{
+		"K8sRequiredLabels/prod": {passed: 1},
+		"K8sAllowedRepos/prod":   {failed: 1, violations: 1},
+	}
+	if !reflect.DeepEqual(got, expected) {
+		t.Errorf("expect: %+v, but got: %+v", expected, got)
+	}
+}
+
+func TestReportsAdmissionPolicy(t *testing.T) {
+	objects, err := parseInventory([]byte(replicasPolicy+`---
+apiVersion: admissionregistration.k8s.io/v1
+kind: ValidatingAdmissionPolicyBinding
+metadata:
+  name: max-replicas-binding
+spec:
+  policyName: max-replicas
+  validationActions: [Deny]
+  paramRef:
+    name: replicas-limit
+    parameterNotFoundAction: Allow
+`), "input.yaml")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	_, violations, err := ValidateCEL(objects, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	reports, err := newReports(objects, violations, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	content, err := sarifReport(reports)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var sarif sarifLog
+	if err := json.Unmarshal(content, &sarif); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expectedRules := []sarifRule{{
+		ID:         "ValidatingAdmissionPolicyBinding/max-replicas-binding",
+		Name:       "max-replicas-binding",
+		Properties: map[string]int{"passed": 2, "failed": 1},
+	}}
+	if !reflect.DeepEqual(sarif.Runs[0].Tool.Driver.Rules, expectedRules) {
+		t.Errorf("expect: %+v, but got: %+v", expectedRules, sarif.Runs[0].Tool.Driver.Rules)
+	}
+	expectedResults := []sarifResult{{
+		RuleID:  "ValidatingAdmissionPolicyBinding/max-replicas-binding",
+		Level:   "error",
+		Message: sarifMessage{Text: "replicas must be no greater than 3"},
+		Locations: []sarifLocation{{
+			LogicalLocations: []sarifLogicalLocation{{FullyQualifiedName: "apps/v1/Deployment/prod/nginx", Kind: "resource"}},
+		}},
+	}}
+	if !reflect.DeepEqual(sarif.Runs[0].Results, expectedResults) {
+		t.Errorf("expect: %+v, but got: %+v", expectedResults, sarif.Runs[0].Results)
+	}
+
+	content, err = junitReport(reports)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expectedJUnit := `<?xml version="1.0" encoding="UTF-8"?>
+<testsuites name="gatekeeper" tests="3" failures="1">
+  <testsuite name="ValidatingAdmissionPolicyBinding/max-replicas-binding" tests="3" failures="1">
+    <testcase name="apps/v1/Deployment/prod/nginx" classname="ValidatingAdmissionPolicyBinding/max-replicas-binding">
+      <failure message="replicas must be no greater than 3" type="error">replicas must be no greater than 3</failure>
+    </testcase>
+  </testsuite>
+</testsuites>`
+	if string(content) != expectedJUnit {
+		t.Errorf("expect:\n%s\nbut got:\n%s", expectedJUnit, content)
+	}
+}
@@ -30,43 +30,66 @@ import (
 	"sigs.k8s.io/kustomize/kyaml/yaml"
 )
 
+// violatedConstraintPrefix precedes the name of the violated constraint in the
+// message of the result items
+const violatedConstraintPrefix = "\nviolatedConstraint: "
+
 // Validate makes sure the configs passed to it comply with any Constraints and
-// Constraint Templates present in the list of configs
-func Validate(objects []*unstructured.Unstructured) (*framework.Result, error) {
-	resps, err := gatortest.Test(objects)
+// Constraint Templates present in the list of configs. The inventory objects
+// are only visible to the referential constraints through data.inventory,
+// they are not validated. The path of the offending field is read from the
+// fieldKey of the violation details when the template provides it. The
+// violations are returned along with the result for the reports.
+func Validate(objects, inventory []*unstructured.Unstructured, fieldKey string) (*framework.Result, []violation, error) {
+	keys := make(map[string]bool)
+	for _, obj := range objects {
+		keys[objectKey(obj)] = true
+	}
+	inventoryKeys := make(map[string]bool)
+	all := objects
+	for _, obj := range inventory {
+		// the package objects take precedence over the inventory objects, and
+		// the policies in the inventory are not enforced
+		if key := objectKey(obj); !keys[key] && !isPolicy(obj) {
+			inventoryKeys[key] = true
+			all = append(all, obj)
+		}
+	}
+
+	resps, err := gatortest.Test(all)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
-	results := resps.Results()
+	var results []*opatypes.Result
+	for _, r := range resps.Results() {
+		if u, ok := r.Resource.(*unstructured.Unstructured); ok && inventoryKeys[objectKey(u)] {
+			continue
+		}
+		results = append(results, r)
+	}
 	if len(results) > 0 {
-		return parseResults(results)
+		return parseResults(results, fieldKey)
 	}
-	return nil, nil
+	return nil, nil, nil
 }
 
-func parseResults(results []*opatypes.Result) (*framework.Result, error) {
+func parseResults(results []*opatypes.Result, fieldKey string) (*framework.Result, []violation, error) {
 	var items []framework.ResultItem
+	var violations []violation
 
 	for _, r := range results {
 		u, ok := r.Resource.(*unstructured.Unstructured)
 		if !ok {
-			return nil, fmt.Errorf("could not cast to unstructured: %+v", r.Resource)
+			return nil, nil, fmt.Errorf("could not cast to unstructured: %+v", r.Resource)
 		}
 
-		item := framework.ResultItem{
-			Message: fmt.Sprintf("%s\nviolatedConstraint: %s", r.Msg, r.Constraint.GetName()),
-			ResourceRef: yaml.ResourceIdentifier{
-				TypeMeta: yaml.TypeMeta{
-					APIVersion: u.GetAPIVersion(),
-					Kind:       u.GetKind(),
-				},
-				NameMeta: yaml.NameMeta{
-					Name:      u.GetName(),
-					Namespace: u.GetNamespace(),
-				},
-			},
+		item, err := newResultItem(u)
+		if err != nil {
+			return nil, nil, err
 		}
+		item.Message = r.Msg + violatedConstraintPrefix + r.Constraint.GetName()
+		item.Field.Path = violatedField(r.Metadata, fieldKey)
 
 		switch r.EnforcementAction {
 		case string(opautil.Dryrun):
@@ -77,43 +100,69 @@ func parseResults(results []*opatypes.Result) (*framework.Result, error) {
 			item.Severity = framework.Error
 		}
 
-		path, foundPath := u.GetAnnotations()[kioutil.PathAnnotation]
-		index, foundIndex := u.GetAnnotations()[kioutil.IndexAnnotation]
-		if foundPath {
-			item.File = framework.File{
-				Path: path,
-			}
-			if foundIndex {
-				idx, err := strconv.Atoi(index)
-				if err != nil {
-					return nil, err
-				}
-				item.File.Index = idx
-			}
-		}
-
 		items = append(items, item)
+		violations = append(violations, violation{
+			report:  r.Constraint.GetKind() + "/" + r.Constraint.GetName(),
+			item:    item,
+			message: r.Msg,
+		})
 	}
 	sortResultItems(items)
 
 	return &framework.Result{
 		Items: items,
-	}, nil
+	}, violations, nil
+}
+
+// newResultItem returns the result item referencing the object and its file
+func newResultItem(u *unstructured.Unstructured) (framework.ResultItem, error) {
+	item := framework.ResultItem{
+		ResourceRef: yaml.ResourceIdentifier{
+			TypeMeta: yaml.TypeMeta{
+				APIVersion: u.GetAPIVersion(),
+				Kind:       u.GetKind(),
+			},
+			NameMeta: yaml.NameMeta{
+				Name:      u.GetName(),
+				Namespace: u.GetNamespace(),
+			},
+		},
+	}
+
+	path, foundPath := u.GetAnnotations()[kioutil.PathAnnotation]
+	index, foundIndex := u.GetAnnotations()[kioutil.IndexAnnotation]
+	if foundPath {
+		item.File = framework.File{
+			Path: path,
+		}
+		if foundIndex {
+			idx, err := strconv.Atoi(index)
+			if err != nil {
+				return item, err
+			}
+			item.File.Index = idx
+		}
+	}
+	return item, nil
 }
 
 // TODO(mengqiy): upstream this to the SDK
 func sortResultItems(items []framework.ResultItem) {
 	sort.SliceStable(items, func(i, j int) bool {
-		if fileLess(items, i, j) != 0 {
-			return fileLess(items, i, j) < 0
-		}
-		if severityLess(items, i, j) != 0 {
-			return severityLess(items, i, j) < 0
-		}
-		return resultItemToString(items[i]) < resultItemToString(items[j])
+		return resultItemLess(items, i, j)
 	})
 }
 
+func resultItemLess(items []framework.ResultItem, i, j int) bool {
+	if fileLess(items, i, j) != 0 {
+		return fileLess(items, i, j) < 0
+	}
+	if severityLess(items, i, j) != 0 {
+		return severityLess(items, i, j) < 0
+	}
+	return resultItemToString(items[i]) < resultItemToString(items[j])
+}
+
 func severityLess(items []framework.ResultItem, i, j int) int {
 	severityToNumber := map[framework.Severity]int{
 		framework.Error:   0,
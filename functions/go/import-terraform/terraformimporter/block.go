@@ -0,0 +1,394 @@
+// Copyright 2022 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package terraformimporter
+
+import (
+	"fmt"
+	"strings"
+
+	sdk "github.com/GoogleContainerTools/kpt-functions-catalog/thirdparty/kyaml/fnsdk"
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/zclconf/go-cty/cty"
+	"github.com/zclconf/go-cty/cty/convert"
+)
+
+type terraformBlock struct {
+	Type      string               // The type of the block: resource, data or module
+	Labels    []string             // The labels of the block, e.g. the resource type and name
+	file      string               // The Terraform file the block is declared in
+	line      int                  // The line the block starts at
+	body      *hclsyntax.Body      // The body of the block
+	module    *terraformModule     // A back-reference to the module the block is part of
+	configMap *sdk.KubeObject      // The ConfigMap holding the Terraform file
+	path      string               // The path of a nested block within its top-level block
+	root      *terraformBlock      // The top-level block of a nested block
+	nested    []*terraformBlock    // The nested blocks read from a top-level block
+	used      map[string]bool      // The attributes and the nested blocks which are translated
+	problems  []translationProblem // The attributes and the nested blocks which can't be translated
+}
+
+// translationProblem is an attribute or a nested block left out of the
+// Config Connector resources
+type translationProblem struct {
+	field  string
+	reason string
+}
+
+// metaArguments are the arguments of Terraform itself, which don't need to
+// be translated
+var metaArguments = map[string]bool{
+	"depends_on": true,
+	"provider":   true,
+	"providers":  true,
+	"lifecycle":  true,
+	"source":     true,
+	"version":    true,
+}
+
+func (block *terraformBlock) String() string {
+	labels := make([]string, len(block.Labels))
+	for i, label := range block.Labels {
+		labels[i] = fmt.Sprintf("%q", label)
+	}
+	return fmt.Sprintf("%s %s (%s:%d)", block.Type, strings.Join(labels, " "), block.file, block.line)
+}
+
+// address returns the Terraform address of a top-level block
+func (block *terraformBlock) address() string {
+	if block.Type == "resource" {
+		return strings.Join(block.Labels, ".")
+	}
+	return fmt.Sprintf("%s.%s", block.Type, strings.Join(block.Labels, "."))
+}
+
+// name returns the name of the Config Connector resource for the block
+func (block *terraformBlock) name() string {
+	return kccName(block.Labels[len(block.Labels)-1])
+}
+
+// translator returns how to translate a top-level block, or why it can't be
+func (block *terraformBlock) translator() (*translator, string) {
+	switch block.Type {
+	case "resource":
+		if t, found := resourceTranslators[block.Labels[0]]; found {
+			return t, ""
+		}
+		return nil, fmt.Sprintf("the resource type %s is not supported", block.Labels[0])
+	case "module":
+		source := ""
+		if attr, found := block.body.Attributes["source"]; found {
+			value, diags := attr.Expr.Value(nil)
+			if !diags.HasErrors() && value.Type() == cty.String {
+				source = strings.TrimPrefix(value.AsString(), "registry.terraform.io/")
+			}
+		}
+		if t, found := moduleTranslators[source]; found {
+			return t, ""
+		}
+		return nil, fmt.Sprintf("the module source %q is not supported", source)
+	}
+	return nil, "data sources are not supported"
+}
+
+// problem records an attribute or a nested block which can't be translated
+func (block *terraformBlock) problem(fieldType, name, reason string) {
+	root := block
+	if block.root != nil {
+		root = block.root
+		name = fmt.Sprintf("%s.%s", block.path, name)
+	}
+	root.problems = append(root.problems, translationProblem{
+		field:  fmt.Sprintf("%s %s", fieldType, name),
+		reason: reason,
+	})
+}
+
+// checkUnused records the attributes and the nested blocks which are not
+// translated
+func (block *terraformBlock) checkUnused() {
+	for _, b := range append([]*terraformBlock{block}, block.nested...) {
+		for _, attr := range sortedAttributes(b.body) {
+			if !b.used[attr.Name] && !metaArguments[attr.Name] {
+				b.problem("attribute", attr.Name, "the attribute is not supported")
+			}
+		}
+		for _, nested := range b.body.Blocks {
+			if !b.used[nested.Type] && !metaArguments[nested.Type] {
+				b.problem("block", nested.Type, "the block is not supported")
+			}
+		}
+	}
+}
+
+// blocks returns the nested blocks of a type
+func (block *terraformBlock) blocks(blockType string) []*terraformBlock {
+	block.used[blockType] = true
+	root := block
+	if block.root != nil {
+		root = block.root
+	}
+	var blocks []*terraformBlock
+	for _, nested := range block.body.Blocks {
+		if nested.Type != blockType {
+			continue
+		}
+		path := blockType
+		if block.root != nil {
+			path = fmt.Sprintf("%s.%s", block.path, blockType)
+		}
+		b := &terraformBlock{
+			Type:      nested.Type,
+			file:      block.file,
+			line:      nested.TypeRange.Start.Line,
+			body:      nested.Body,
+			module:    block.module,
+			configMap: block.configMap,
+			path:      path,
+			root:      root,
+			used:      make(map[string]bool),
+		}
+		root.nested = append(root.nested, b)
+		blocks = append(blocks, b)
+	}
+	return blocks
+}
+
+// block returns the first nested block of a type, if there is one
+func (block *terraformBlock) block(blockType string) *terraformBlock {
+	blocks := block.blocks(blockType)
+	if len(blocks) == 0 {
+		return nil
+	}
+	return blocks[0]
+}
+
+// attribute returns the expression of an attribute, marking it as translated
+func (block *terraformBlock) attribute(name string) (hclsyntax.Expression, bool) {
+	attr, found := block.body.Attributes[name]
+	if !found {
+		return nil, false
+	}
+	block.used[name] = true
+	return attr.Expr, true
+}
+
+// value returns the value of an attribute, if it is known without applying
+// the Terraform configuration
+func (block *terraformBlock) value(name string) (cty.Value, bool) {
+	expr, found := block.attribute(name)
+	if !found {
+		return cty.NilVal, false
+	}
+	return block.expressionValue(name, expr)
+}
+
+// expressionValue returns the value of an expression of an attribute
+func (block *terraformBlock) expressionValue(name string, expr hclsyntax.Expression) (cty.Value, bool) {
+	for _, traversal := range expr.Variables() {
+		if address := traversalAddress(traversal); address != "" {
+			block.problem("attribute", name, fmt.Sprintf("it references %s", address))
+			return cty.NilVal, false
+		}
+		switch root := traversal.RootName(); root {
+		case "var", "local":
+			if len(traversal) < 2 {
+				continue
+			}
+			attr, ok := traversal[1].(hcl.TraverseAttr)
+			if !ok {
+				continue
+			}
+			values := block.module.variables
+			if root == "local" {
+				values = block.module.locals
+			}
+			if _, found := values[attr.Name]; !found {
+				block.problem("attribute", name, fmt.Sprintf("%s.%s has no known value", root, attr.Name))
+				return cty.NilVal, false
+			}
+		}
+	}
+	value, diags := expr.Value(block.module.ctx)
+	if diags.HasErrors() {
+		block.problem("attribute", name, diags.Error())
+		return cty.NilVal, false
+	}
+	if value.IsNull() || !value.IsWhollyKnown() {
+		return cty.NilVal, false
+	}
+	return value, true
+}
+
+// convertedValue returns the value of an attribute converted to a type
+func (block *terraformBlock) convertedValue(name string, valueType cty.Type) (cty.Value, bool) {
+	value, found := block.value(name)
+	if !found {
+		return cty.NilVal, false
+	}
+	converted, err := convert.Convert(value, valueType)
+	if err != nil {
+		block.problem("attribute", name, fmt.Sprintf("the value is not a %s", valueType.FriendlyName()))
+		return cty.NilVal, false
+	}
+	return converted, true
+}
+
+// getString returns the value of an attribute as a string
+func (block *terraformBlock) getString(name string) (string, bool) {
+	value, found := block.convertedValue(name, cty.String)
+	if !found {
+		return "", false
+	}
+	return value.AsString(), true
+}
+
+// getBool returns the value of an attribute as a boolean
+func (block *terraformBlock) getBool(name string) (bool, bool) {
+	value, found := block.convertedValue(name, cty.Bool)
+	if !found {
+		return false, false
+	}
+	return value.True(), true
+}
+
+// getNumber returns the value of an attribute as an int, or as a float64
+// if it isn't a whole number
+func (block *terraformBlock) getNumber(name string) (interface{}, bool) {
+	value, found := block.convertedValue(name, cty.Number)
+	if !found {
+		return nil, false
+	}
+	return numberValue(value), true
+}
+
+// numberValue returns a number as an int, or as a float64 if it isn't a
+// whole number
+func numberValue(value cty.Value) interface{} {
+	number := value.AsBigFloat()
+	if number.IsInt() {
+		i, _ := number.Int64()
+		return int(i)
+	}
+	f, _ := number.Float64()
+	return f
+}
+
+// getStrings returns the value of an attribute as a list of strings
+func (block *terraformBlock) getStrings(name string) ([]string, bool) {
+	value, found := block.convertedValue(name, cty.List(cty.String))
+	if !found {
+		return nil, false
+	}
+	var values []string
+	for i, v := range value.AsValueSlice() {
+		if v.IsNull() {
+			block.problem("attribute", fmt.Sprintf("%s[%d]", name, i), "the value is null")
+			continue
+		}
+		values = append(values, v.AsString())
+	}
+	return values, true
+}
+
+// getStringMap returns the value of an attribute as a map of strings
+func (block *terraformBlock) getStringMap(name string) (map[string]string, bool) {
+	value, found := block.convertedValue(name, cty.Map(cty.String))
+	if !found {
+		return nil, false
+	}
+	values := make(map[string]string)
+	for k, v := range value.AsValueMap() {
+		if v.IsNull() {
+			block.problem("attribute", fmt.Sprintf("%s[%q]", name, k), "the value is null")
+			continue
+		}
+		values[k] = v.AsString()
+	}
+	return values, true
+}
+
+// getReference returns the reference to the resource of a kind an attribute
+// refers to: by name when the resource is translated from the Terraform
+// configuration, otherwise by its external value
+func (block *terraformBlock) getReference(name string, kind string) (map[string]interface{}, bool) {
+	expr, found := block.attribute(name)
+	if !found {
+		return nil, false
+	}
+	return block.expressionReference(name, expr, kind)
+}
+
+// getReferences returns the references to the resources of a kind a list
+// attribute refers to
+func (block *terraformBlock) getReferences(name string, kind string) ([]interface{}, bool) {
+	expr, found := block.attribute(name)
+	if !found {
+		return nil, false
+	}
+	var refs []interface{}
+	switch expr := expr.(type) {
+	case *hclsyntax.TupleConsExpr:
+		for _, item := range expr.Exprs {
+			ref, found := block.expressionReference(name, item, kind)
+			if !found {
+				return nil, false
+			}
+			refs = append(refs, ref)
+		}
+	case *hclsyntax.SplatExpr:
+		ref, found := block.expressionReference(name, expr, kind)
+		if !found {
+			return nil, false
+		}
+		refs = append(refs, ref)
+	default:
+		values, found := block.getStrings(name)
+		if !found {
+			return nil, false
+		}
+		for _, value := range values {
+			refs = append(refs, map[string]interface{}{"external": value})
+		}
+	}
+	return refs, len(refs) > 0
+}
+
+// expressionReference returns the reference to the resource of a kind an
+// expression refers to
+func (block *terraformBlock) expressionReference(name string, expr hclsyntax.Expression, kind string) (map[string]interface{}, bool) {
+	ref, address, isReference := block.module.reference(expr)
+	if !isReference {
+		value, found := block.expressionValue(name, expr)
+		if !found {
+			return nil, false
+		}
+		converted, err := convert.Convert(value, cty.String)
+		if err != nil {
+			block.problem("attribute", name, "the value is not a string")
+			return nil, false
+		}
+		return map[string]interface{}{"external": converted.AsString()}, true
+	}
+	if ref == nil {
+		block.problem("attribute", name, fmt.Sprintf("it references %s which is not translated", address))
+		return nil, false
+	}
+	if ref.Kind != kind {
+		block.problem("attribute", name, fmt.Sprintf("it references %s which is a %s, not a %s", address, ref.Kind, kind))
+		return nil, false
+	}
+	return map[string]interface{}{"name": ref.Name}, true
+}
@@ -0,0 +1,249 @@
+// Copyright 2022 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package terraformimporter
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	sdk "github.com/GoogleContainerTools/kpt-functions-catalog/thirdparty/kyaml/fnsdk"
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclparse"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/zclconf/go-cty/cty"
+)
+
+type terraformModule struct {
+	blocks    []*terraformBlock
+	resources map[string]*kccReference // The resources translated from each Terraform address
+	variables map[string]cty.Value     // The default values of the variables
+	locals    map[string]cty.Value     // The values of the locals which only depend on variables
+	ctx       *hcl.EvalContext
+}
+
+// kccReference is a Config Connector resource which is referenced by name
+type kccReference struct {
+	Kind string
+	Name string
+}
+
+// parseModule parses the Terraform files into a module, along with the
+// values of its variables and locals
+func parseModule(files map[string]string) (*terraformModule, error) {
+	module := &terraformModule{
+		resources: make(map[string]*kccReference),
+		variables: make(map[string]cty.Value),
+		locals:    make(map[string]cty.Value),
+	}
+	parser := hclparse.NewParser()
+	var locals []*hclsyntax.Attribute
+	for _, name := range terraformFiles(files) {
+		file, diags := parser.ParseHCL([]byte(files[name]), name)
+		if diags.HasErrors() {
+			return nil, diags
+		}
+		body := file.Body.(*hclsyntax.Body)
+		for _, block := range body.Blocks {
+			switch block.Type {
+			case "variable":
+				attr, found := block.Body.Attributes["default"]
+				if !found {
+					continue
+				}
+				value, diags := attr.Expr.Value(nil)
+				if !diags.HasErrors() && !value.IsNull() {
+					module.variables[block.Labels[0]] = value
+				}
+			case "locals":
+				for _, attr := range sortedAttributes(block.Body) {
+					locals = append(locals, attr)
+				}
+			case "resource", "data", "module":
+				module.blocks = append(module.blocks, &terraformBlock{
+					Type:   block.Type,
+					Labels: block.Labels,
+					file:   name,
+					line:   block.TypeRange.Start.Line,
+					body:   block.Body,
+					module: module,
+					used:   make(map[string]bool),
+				})
+			}
+		}
+	}
+
+	module.ctx = &hcl.EvalContext{Variables: map[string]cty.Value{
+		"var":   cty.ObjectVal(module.variables),
+		"local": cty.ObjectVal(module.locals),
+	}}
+	// locals may depend on each other, evaluate them until none is left to
+	// evaluate
+	for evaluated := true; evaluated; {
+		evaluated = false
+		for _, attr := range locals {
+			if _, found := module.locals[attr.Name]; found {
+				continue
+			}
+			value, diags := attr.Expr.Value(module.ctx)
+			if diags.HasErrors() {
+				continue
+			}
+			module.locals[attr.Name] = value
+			module.ctx.Variables["local"] = cty.ObjectVal(module.locals)
+			evaluated = true
+		}
+	}
+
+	for _, block := range module.blocks {
+		t, _ := block.translator()
+		if t == nil || t.kind == "" {
+			continue
+		}
+		name := block.name()
+		if t.name != nil {
+			name = t.name(block)
+		}
+		module.resources[block.address()] = &kccReference{Kind: t.kind, Name: name}
+	}
+	return module, nil
+}
+
+// translate returns the Config Connector resources for the blocks of the
+// module, and reports the blocks which can't be translated
+func (module *terraformModule) translate(report *importReport, configMap *sdk.KubeObject) []*kccObject {
+	var objects []*kccObject
+	for _, block := range module.blocks {
+		block.configMap = configMap
+		report.total++
+
+		t, reason := block.translator()
+		if t == nil {
+			report.untranslatedBlock(block, reason)
+			continue
+		}
+		if _, found := block.body.Attributes["count"]; found {
+			report.untranslatedBlock(block, "count is not supported")
+			continue
+		}
+		if _, found := block.body.Attributes["for_each"]; found {
+			report.untranslatedBlock(block, "for_each is not supported")
+			continue
+		}
+
+		translated := t.translate(block)
+		if len(translated) == 0 {
+			reason := "it doesn't declare any resource"
+			if len(block.problems) > 0 {
+				reason = fmt.Sprintf("%s: %s", block.problems[0].field, block.problems[0].reason)
+			}
+			report.untranslatedBlock(block, reason)
+			continue
+		}
+		block.checkUnused()
+		report.untranslatedFields(block)
+		objects = append(objects, translated...)
+	}
+	return objects
+}
+
+// reference returns the Config Connector resource an expression refers to,
+// along with the Terraform address it refers to. It returns false when the
+// expression doesn't refer to a Terraform resource or module.
+func (module *terraformModule) reference(expr hclsyntax.Expression) (*kccReference, string, bool) {
+	var traversal hcl.Traversal
+	switch expr := expr.(type) {
+	case *hclsyntax.ScopeTraversalExpr:
+		traversal = expr.Traversal
+	case *hclsyntax.SplatExpr:
+		source, ok := expr.Source.(*hclsyntax.ScopeTraversalExpr)
+		if !ok {
+			return nil, "", false
+		}
+		traversal = source.Traversal
+	case *hclsyntax.TemplateWrapExpr:
+		return module.reference(expr.Wrapped)
+	default:
+		return nil, "", false
+	}
+
+	address := traversalAddress(traversal)
+	if address == "" {
+		return nil, "", false
+	}
+	ref := module.resources[address]
+	// the subnets of the network module are referenced by their region and name
+	if ref != nil && ref.Kind == "ComputeNetwork" && len(traversal) > 3 {
+		if attr, ok := traversal[2].(hcl.TraverseAttr); ok && attr.Name == "subnets" {
+			if index, ok := traversal[3].(hcl.TraverseIndex); ok && index.Key.Type() == cty.String {
+				key := index.Key.AsString()
+				return &kccReference{Kind: "ComputeSubnetwork", Name: kccName(key[strings.LastIndex(key, "/")+1:])},
+					fmt.Sprintf("%s.subnets[%q]", address, key), true
+			}
+		}
+	}
+	return ref, address, true
+}
+
+// traversalAddress returns the address of the Terraform resource, data
+// source or module a traversal starts with. It is empty for the traversals
+// of variables, locals and other values known without applying Terraform.
+func traversalAddress(traversal hcl.Traversal) string {
+	var names []string
+	for _, step := range traversal {
+		switch step := step.(type) {
+		case hcl.TraverseRoot:
+			names = append(names, step.Name)
+		case hcl.TraverseAttr:
+			names = append(names, step.Name)
+		}
+		if len(names) == 3 {
+			break
+		}
+	}
+	if len(names) < 2 {
+		return ""
+	}
+	switch names[0] {
+	case "var", "local", "path", "terraform", "count", "each", "self":
+		return ""
+	case "data":
+		if len(names) < 3 {
+			return ""
+		}
+		return strings.Join(names[:3], ".")
+	}
+	return strings.Join(names[:2], ".")
+}
+
+var kccNameRegex = regexp.MustCompile(`[^a-z\d.-]+`)
+
+// kccName returns a valid Kubernetes name for a Terraform name
+func kccName(name string) string {
+	return strings.Trim(kccNameRegex.ReplaceAllString(strings.ToLower(name), "-"), "-.")
+}
+
+// sortedAttributes returns the attributes of a body in the order of the file
+func sortedAttributes(body *hclsyntax.Body) []*hclsyntax.Attribute {
+	var attrs []*hclsyntax.Attribute
+	for _, attr := range body.Attributes {
+		attrs = append(attrs, attr)
+	}
+	sort.Slice(attrs, func(i, j int) bool {
+		return attrs[i].SrcRange.Start.Byte < attrs[j].SrcRange.Start.Byte
+	})
+	return attrs
+}
@@ -0,0 +1,542 @@
+// Copyright 2022 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package terraformimporter
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/zclconf/go-cty/cty"
+	"github.com/zclconf/go-cty/cty/convert"
+)
+
+// moduleTranslators are the translators of the Cloud Foundation Toolkit
+// modules, by their source
+var moduleTranslators = map[string]*translator{
+	"terraform-google-modules/project-factory/google":                {kind: "Project", name: attributeName("project_id", "name"), translate: translateProjectFactory},
+	"terraform-google-modules/network/google":                        {kind: "ComputeNetwork", translate: translateNetworkModule},
+	"terraform-google-modules/iam/google//modules/organizations_iam": {translate: translateIAMModule("Organization", "organizations")},
+	"terraform-google-modules/iam/google//modules/folders_iam":       {translate: translateIAMModule("Folder", "folders")},
+	"terraform-google-modules/iam/google//modules/projects_iam":      {translate: translateIAMModule("Project", "projects")},
+	"terraform-google-modules/log-export/google":                     {kind: "LoggingLogSink", name: attributeName("log_sink_name"), translate: translateLogSinkModule},
+	"terraform-google-modules/log-export/google//modules/bigquery":   {kind: "BigQueryDataset", name: attributeName("dataset_name"), translate: translateLogBigQueryModule},
+	"terraform-google-modules/log-export/google//modules/pubsub":     {kind: "PubSubTopic", name: attributeName("topic_name"), translate: translateLogPubSubModule},
+	"terraform-google-modules/log-export/google//modules/storage":    {kind: "StorageBucket", name: attributeName("storage_bucket_name"), translate: translateLogStorageModule},
+	"terraform-google-modules/log-export/google//modules/logbucket":  {kind: "LoggingLogBucket", name: attributeName("name"), translate: translateLogBucketModule},
+}
+
+func translateProjectFactory(block *terraformBlock) []*kccObject {
+	project := newObject(block, resourceManagerAPI, "Project", attributeName("project_id", "name")(block))
+	block.used["project_id"] = true
+	block.setString(project.Spec, "name", "name")
+	block.setHierarchy(project)
+	block.setReference(project.Spec, "billing_account", "BillingAccount", "billingAccountRef")
+	// the module doesn't create the default network unless asked to
+	autoCreateNetwork, _ := block.getBool("auto_create_network")
+	project.Annotations["cnrm.cloud.google.com/auto-create-network"] = fmt.Sprint(autoCreateNetwork)
+	block.setLabels(project, "labels")
+	objects := []*kccObject{project}
+
+	if host, found := block.getBool("enable_shared_vpc_host_project"); found && host {
+		hostProject := newObject(block, computeAPI, "ComputeSharedVPCHostProject", fmt.Sprintf("%s-host", project.Name))
+		hostProject.Annotations[projectIDAnnotation] = project.Name
+		objects = append(objects, hostProject)
+	}
+	return objects
+}
+
+func translateNetworkModule(block *terraformBlock) []*kccObject {
+	network := newObject(block, computeAPI, "ComputeNetwork", block.name())
+	block.setResourceID(network, "network_name")
+	block.setProject(network, "project_id")
+	block.setString(network.Spec, "description", "description")
+	block.setString(network.Spec, "routing_mode", "routingMode")
+	// the module doesn't create the subnets unless asked to
+	autoCreateSubnetworks, _ := block.getBool("auto_create_subnetworks")
+	network.Spec["autoCreateSubnetworks"] = autoCreateSubnetworks
+	block.setBool(network.Spec, "delete_default_internet_gateway_routes", "deleteDefaultRoutesOnCreate")
+	block.setNumber(network.Spec, "mtu", "mtu")
+	objects := []*kccObject{network}
+
+	secondaryRanges := make(map[string][]*moduleObject)
+	if value, found := block.convertedValue("secondary_ranges", cty.Map(cty.List(cty.Map(cty.String)))); found {
+		for subnetName, ranges := range value.AsValueMap() {
+			field := fmt.Sprintf("secondary_ranges[%q]", subnetName)
+			if ranges.IsNull() {
+				block.problem("attribute", field, "the value is null")
+				continue
+			}
+			for i, r := range ranges.AsValueSlice() {
+				if r.IsNull() {
+					block.problem("attribute", fmt.Sprintf("%s[%d]", field, i), "the value is null")
+					continue
+				}
+				secondaryRanges[subnetName] = append(secondaryRanges[subnetName],
+					newModuleObject(block, fmt.Sprintf("%s[%d]", field, i), r))
+			}
+		}
+	}
+
+	for _, subnet := range block.moduleObjects("subnets") {
+		name, found := subnet.getString("subnet_name")
+		if !found {
+			block.problem("attribute", subnet.field, "the subnet has no subnet_name")
+			continue
+		}
+		s := newObject(block, computeAPI, "ComputeSubnetwork", kccName(name))
+		if s.Name != name {
+			s.Spec["resourceID"] = name
+		}
+		copyAnnotation(s, network, projectIDAnnotation)
+		s.Spec["networkRef"] = map[string]interface{}{"name": network.Name}
+		subnet.setString(s.Spec, "subnet_ip", "ipCidrRange")
+		subnet.setString(s.Spec, "subnet_region", "region")
+		subnet.setBool(s.Spec, "subnet_private_access", "privateIpGoogleAccess")
+		subnet.setString(s.Spec, "description", "description")
+		if flowLogs, _ := subnet.getBool("subnet_flow_logs"); flowLogs {
+			logConfig := make(map[string]interface{})
+			subnet.setNumber(logConfig, "subnet_flow_logs_sampling", "flowSampling")
+			subnet.setString(logConfig, "subnet_flow_logs_metadata", "metadata")
+			subnet.setString(logConfig, "subnet_flow_logs_interval", "aggregationInterval")
+			s.Spec["logConfig"] = logConfig
+		}
+		var ranges []interface{}
+		for _, r := range secondaryRanges[name] {
+			secondaryRange := make(map[string]interface{})
+			r.setString(secondaryRange, "range_name", "rangeName")
+			r.setString(secondaryRange, "ip_cidr_range", "ipCidrRange")
+			r.checkUnused()
+			ranges = append(ranges, secondaryRange)
+		}
+		if len(ranges) > 0 {
+			s.Spec["secondaryIpRange"] = ranges
+		}
+		subnet.checkUnused()
+		objects = append(objects, s)
+	}
+
+	for _, route := range block.moduleObjects("routes") {
+		name, found := route.getString("name")
+		if !found {
+			block.problem("attribute", route.field, "the route has no name")
+			continue
+		}
+		r := newObject(block, computeAPI, "ComputeRoute", kccName(name))
+		if r.Name != name {
+			r.Spec["resourceID"] = name
+		}
+		copyAnnotation(r, network, projectIDAnnotation)
+		r.Spec["networkRef"] = map[string]interface{}{"name": network.Name}
+		route.setString(r.Spec, "description", "description")
+		route.setString(r.Spec, "destination_range", "destRange")
+		route.setNumber(r.Spec, "priority", "priority")
+		if tags, found := route.getString("tags"); found {
+			r.Spec["tags"] = strings.Split(tags, ",")
+		}
+		if internet, _ := route.getBool("next_hop_internet"); internet {
+			r.Spec["nextHopGateway"] = "default-internet-gateway"
+		}
+		route.setString(r.Spec, "next_hop_ip", "nextHopIp")
+		route.checkUnused()
+		objects = append(objects, r)
+	}
+	return objects
+}
+
+// translateIAMModule returns the translation of the IAM module of a kind of
+// resource, whose list of resources is in an attribute. An IAMPolicyMember
+// is translated for each member of each binding of each resource.
+func translateIAMModule(kind string, attr string) func(block *terraformBlock) []*kccObject {
+	return func(block *terraformBlock) []*kccObject {
+		refs, found := block.getReferences(attr, kind)
+		if !found {
+			return nil
+		}
+		if mode, _ := block.getString("mode"); mode == "authoritative" {
+			block.problem("attribute", "mode", "the bindings are translated to IAMPolicyMember resources, which are additive")
+		}
+		value, found := block.convertedValue("bindings", cty.Map(cty.List(cty.String)))
+		if !found {
+			return nil
+		}
+		bindings := make(map[string][]string)
+		var roles []string
+		for role, members := range value.AsValueMap() {
+			roles = append(roles, role)
+			if members.IsNull() {
+				block.problem("attribute", fmt.Sprintf("bindings[%q]", role), "the value is null")
+				continue
+			}
+			for i, member := range members.AsValueSlice() {
+				if member.IsNull() {
+					block.problem("attribute", fmt.Sprintf("bindings[%q][%d]", role, i), "the value is null")
+					continue
+				}
+				bindings[role] = append(bindings[role], member.AsString())
+			}
+		}
+		sort.Strings(roles)
+
+		var objects []*kccObject
+		names := make(map[string]int)
+		prefix := strings.TrimSuffix(block.name(), "-iam")
+		for i, ref := range refs {
+			if len(refs) > 1 {
+				prefix = fmt.Sprintf("%s-%d", strings.TrimSuffix(block.name(), "-iam"), i+1)
+			}
+			for _, role := range roles {
+				for _, member := range bindings[role] {
+					name := kccName(fmt.Sprintf("%s-%s-%s", prefix, roleName(role), memberName(member)))
+					if names[name]++; names[name] > 1 {
+						name = fmt.Sprintf("%s-%d", name, names[name])
+					}
+					policyMember := newObject(block, iamAPI, "IAMPolicyMember", name)
+					policyMember.Spec["resourceRef"] = resourceRef(kind, copyReference(ref.(map[string]interface{})))
+					policyMember.Spec["role"] = role
+					policyMember.Spec["member"] = member
+					objects = append(objects, policyMember)
+				}
+			}
+		}
+		return objects
+	}
+}
+
+// roleName returns the short name of a role, e.g. "orgpolicy-policyAdmin"
+// for "roles/orgpolicy.policyAdmin"
+func roleName(role string) string {
+	return strings.ReplaceAll(role[strings.LastIndex(role, "/")+1:], ".", "-")
+}
+
+// memberName returns the short name of a member, e.g. "gcp-developers" for
+// "group:gcp-developers@example.com"
+func memberName(member string) string {
+	name := member[strings.Index(member, ":")+1:]
+	if at := strings.Index(name, "@"); at > 0 {
+		name = name[:at]
+	}
+	return name
+}
+
+// sinkParents are the reference fields of the parents of a log sink, by
+// the parent_resource_type of the log export module
+var sinkParents = map[string]struct{ kind, field string }{
+	"organization": {"Organization", "organizationRef"},
+	"folder":       {"Folder", "folderRef"},
+	"project":      {"Project", "projectRef"},
+}
+
+// sinkDestinations are the reference fields of the destinations of a log
+// sink, by the kind of the destination module
+var sinkDestinations = map[string]string{
+	"BigQueryDataset":  "bigQueryDatasetRef",
+	"PubSubTopic":      "pubSubTopicRef",
+	"StorageBucket":    "storageBucketRef",
+	"LoggingLogBucket": "loggingLogBucketRef",
+}
+
+func translateLogSinkModule(block *terraformBlock) []*kccObject {
+	sink := newObject(block, loggingAPI, "LoggingLogSink", attributeName("log_sink_name")(block))
+	block.setResourceID(sink, "log_sink_name")
+	// the module exports the logs of a project unless told otherwise
+	parentType, found := block.getString("parent_resource_type")
+	if !found {
+		parentType = "project"
+	}
+	if parent, found := sinkParents[parentType]; found {
+		if ref, found := block.getReference("parent_resource_id", parent.kind); found {
+			if external, ok := ref["external"].(string); ok {
+				ref["external"] = strings.TrimPrefix(external, strings.ToLower(parent.kind)+"s/")
+			}
+			sink.Spec[parent.field] = ref
+		}
+	} else {
+		block.problem("attribute", "parent_resource_type", fmt.Sprintf("the sinks of %s resources are not supported", parentType))
+	}
+
+	if expr, found := block.attribute("destination_uri"); found {
+		ref, address, isReference := block.module.reference(expr)
+		switch {
+		case !isReference:
+			block.problem("attribute", "destination_uri", "it doesn't reference a destination module of the log export")
+		case ref == nil:
+			block.problem("attribute", "destination_uri", fmt.Sprintf("it references %s which is not translated", address))
+		case sinkDestinations[ref.Kind] == "":
+			block.problem("attribute", "destination_uri", fmt.Sprintf("it references %s which is a %s, not a log sink destination", address, ref.Kind))
+		default:
+			setField(sink.Spec, map[string]interface{}{"name": ref.Name}, "destination", sinkDestinations[ref.Kind])
+		}
+	}
+	block.setString(sink.Spec, "filter", "filter")
+	block.setString(sink.Spec, "description", "description")
+	block.setBool(sink.Spec, "disabled", "disabled")
+	block.setBool(sink.Spec, "include_children", "includeChildren")
+	block.setBool(sink.Spec, "unique_writer_identity", "uniqueWriterIdentity")
+	if value, found := block.value("bigquery_options"); found {
+		options := newModuleObject(block, "bigquery_options", value)
+		options.setBool(sink.Spec, "use_partitioned_tables", "bigqueryOptions", "usePartitionedTables")
+		options.checkUnused()
+	}
+	return []*kccObject{sink}
+}
+
+func translateLogBigQueryModule(block *terraformBlock) []*kccObject {
+	dataset := newObject(block, bigQueryAPI, "BigQueryDataset", attributeName("dataset_name")(block))
+	block.setResourceID(dataset, "dataset_name")
+	block.setProject(dataset, "project_id")
+	block.setString(dataset.Spec, "location", "location")
+	block.setString(dataset.Spec, "description", "description")
+	if value, found := block.convertedValue("expiration_days", cty.Number); found {
+		days, _ := value.AsBigFloat().Int64()
+		dataset.Spec["defaultTableExpirationMs"] = int(days) * 24 * 60 * 60 * 1000
+	}
+	block.setReference(dataset.Spec, "kms_key_name", "KMSCryptoKey", "defaultEncryptionConfiguration", "kmsKeyRef")
+	if deleteContents, found := block.getBool("delete_contents_on_destroy"); found {
+		dataset.Annotations["cnrm.cloud.google.com/delete-contents-on-destroy"] = fmt.Sprint(deleteContents)
+	}
+	block.setLabels(dataset, "labels")
+	objects := []*kccObject{dataset}
+
+	// the sink writes to the dataset as an editor of the datasets of the project
+	if _, found := dataset.Annotations[projectIDAnnotation]; found {
+		project, _ := block.getResourceRef("project_id", "Project")
+		objects = append(objects, sinkWriter(block, "roles/bigquery.dataEditor", project)...)
+	}
+	return objects
+}
+
+func translateLogPubSubModule(block *terraformBlock) []*kccObject {
+	topic := newObject(block, pubSubAPI, "PubSubTopic", attributeName("topic_name")(block))
+	block.setResourceID(topic, "topic_name")
+	block.setProject(topic, "project_id")
+	block.setReference(topic.Spec, "kms_key_name", "KMSCryptoKey", "kmsKeyRef")
+	block.setLabels(topic, "topic_labels")
+	return append([]*kccObject{topic}, sinkWriter(block, "roles/pubsub.publisher", map[string]interface{}{
+		"apiVersion": pubSubAPI,
+		"kind":       "PubSubTopic",
+		"name":       topic.Name,
+	})...)
+}
+
+func translateLogStorageModule(block *terraformBlock) []*kccObject {
+	bucket := newObject(block, storageAPI, "StorageBucket", attributeName("storage_bucket_name")(block))
+	block.setResourceID(bucket, "storage_bucket_name")
+	block.setProject(bucket, "project_id")
+	block.setString(bucket.Spec, "location", "location")
+	block.setString(bucket.Spec, "storage_class", "storageClass")
+	// the module enables the uniform bucket-level access unless told otherwise
+	uniformAccess, found := block.getBool("uniform_bucket_level_access")
+	bucket.Spec["uniformBucketLevelAccess"] = uniformAccess || !found
+	block.setBool(bucket.Spec, "versioning", "versioning", "enabled")
+	if value, found := block.value("retention_policy"); found {
+		policy := newModuleObject(block, "retention_policy", value)
+		if value, found := policy.convertedValue("retention_period_days", cty.Number); found {
+			days, _ := value.AsBigFloat().Int64()
+			setField(bucket.Spec, int(days)*24*60*60, "retentionPolicy", "retentionPeriod")
+		}
+		policy.setBool(bucket.Spec, "is_locked", "retentionPolicy", "isLocked")
+		policy.checkUnused()
+	}
+	block.setReference(bucket.Spec, "kms_key_name", "KMSCryptoKey", "encryption", "kmsKeyRef")
+	if forceDestroy, found := block.getBool("force_destroy"); found {
+		bucket.Annotations["cnrm.cloud.google.com/force-destroy"] = fmt.Sprint(forceDestroy)
+	}
+	block.setLabels(bucket, "storage_bucket_labels")
+	return append([]*kccObject{bucket}, sinkWriter(block, "roles/storage.objectCreator", map[string]interface{}{
+		"apiVersion": storageAPI,
+		"kind":       "StorageBucket",
+		"name":       bucket.Name,
+	})...)
+}
+
+func translateLogBucketModule(block *terraformBlock) []*kccObject {
+	bucket := newObject(block, loggingAPI, "LoggingLogBucket", attributeName("name")(block))
+	block.setResourceID(bucket, "name")
+	if ref, found := block.getReference("project_id", "Project"); found {
+		if external, ok := ref["external"].(string); ok {
+			ref["external"] = strings.TrimPrefix(external, "projects/")
+		}
+		bucket.Spec["projectRef"] = ref
+	}
+	// the module creates the bucket in the global location unless told otherwise
+	location, found := block.getString("location")
+	if !found {
+		location = "global"
+	}
+	bucket.Spec["location"] = location
+	block.setNumber(bucket.Spec, "retention_days", "retentionDays")
+	block.setBool(bucket.Spec, "locked", "locked")
+	objects := []*kccObject{bucket}
+
+	// a sink needs no role to write to a bucket of its own project, the
+	// module only grants one when asked to
+	if grant, _ := block.getBool("grant_write_permission_on_bkt_project"); grant && bucket.Spec["projectRef"] != nil {
+		project, _ := block.getResourceRef("project_id", "Project")
+		objects = append(objects, sinkWriter(block, "roles/logging.bucketWriter", project)...)
+	} else {
+		block.used["log_sink_writer_identity"] = true
+	}
+	return objects
+}
+
+// sinkWriter returns the IAMPolicyMember a destination module of the log
+// export creates, which grants a role on the destination to the writer
+// identity of the log sink
+func sinkWriter(block *terraformBlock, role string, resourceRef map[string]interface{}) []*kccObject {
+	ref, found := block.getReference("log_sink_writer_identity", "LoggingLogSink")
+	if !found {
+		return nil
+	}
+	member := newObject(block, iamAPI, "IAMPolicyMember", fmt.Sprintf("%s-writer", block.name()))
+	if sinkName, ok := ref["name"]; ok {
+		member.Spec["memberFrom"] = map[string]interface{}{"logSinkRef": map[string]interface{}{"name": sinkName}}
+	} else {
+		member.Spec["member"] = ref["external"]
+	}
+	member.Spec["resourceRef"] = resourceRef
+	member.Spec["role"] = role
+	return []*kccObject{member}
+}
+
+// copyReference returns a copy of a reference, so that it can be changed
+// for each resource it is set on
+func copyReference(ref map[string]interface{}) map[string]interface{} {
+	copied := make(map[string]interface{}, len(ref))
+	for k, v := range ref {
+		copied[k] = v
+	}
+	return copied
+}
+
+// copyAnnotation copies an annotation from a resource, if it is set
+func copyAnnotation(to *kccObject, from *kccObject, annotation string) {
+	if value, found := from.Annotations[annotation]; found {
+		to.Annotations[annotation] = value
+	}
+}
+
+// moduleObject is an object in a list input of a module, e.g. a subnet of
+// the network module
+type moduleObject struct {
+	block  *terraformBlock      // The module block
+	field  string               // The path of the object in the module inputs
+	values map[string]cty.Value // The attributes of the object
+	used   map[string]bool      // The attributes which are translated
+}
+
+func newModuleObject(block *terraformBlock, field string, value cty.Value) *moduleObject {
+	object := &moduleObject{
+		block:  block,
+		field:  field,
+		values: make(map[string]cty.Value),
+		used:   make(map[string]bool),
+	}
+	if value.CanIterateElements() && !value.IsNull() {
+		object.values = value.AsValueMap()
+	}
+	return object
+}
+
+// moduleObjects returns the objects of a list input of a module
+func (block *terraformBlock) moduleObjects(name string) []*moduleObject {
+	value, found := block.value(name)
+	if !found {
+		return nil
+	}
+	if !value.CanIterateElements() {
+		block.problem("attribute", name, "the value is not a list")
+		return nil
+	}
+	var objects []*moduleObject
+	for i, item := range value.AsValueSlice() {
+		if !item.Type().IsObjectType() && !item.Type().IsMapType() {
+			block.problem("attribute", fmt.Sprintf("%s[%d]", name, i), "the value is not an object")
+			continue
+		}
+		objects = append(objects, newModuleObject(block, fmt.Sprintf("%s[%d]", name, i), item))
+	}
+	return objects
+}
+
+// convertedValue returns the value of an attribute of the object converted
+// to a type
+func (object *moduleObject) convertedValue(name string, valueType cty.Type) (cty.Value, bool) {
+	value, found := object.values[name]
+	if !found || value.IsNull() {
+		return cty.NilVal, false
+	}
+	object.used[name] = true
+	converted, err := convert.Convert(value, valueType)
+	if err != nil {
+		object.block.problem("attribute", fmt.Sprintf("%s.%s", object.field, name),
+			fmt.Sprintf("the value is not a %s", valueType.FriendlyName()))
+		return cty.NilVal, false
+	}
+	return converted, true
+}
+
+// getString returns the value of an attribute of the object as a string
+func (object *moduleObject) getString(name string) (string, bool) {
+	value, found := object.convertedValue(name, cty.String)
+	if !found {
+		return "", false
+	}
+	return value.AsString(), true
+}
+
+// getBool returns the value of an attribute of the object as a boolean
+func (object *moduleObject) getBool(name string) (bool, bool) {
+	value, found := object.convertedValue(name, cty.Bool)
+	if !found {
+		return false, false
+	}
+	return value.True(), true
+}
+
+// setString sets a field to the value of a string attribute of the object
+func (object *moduleObject) setString(fields map[string]interface{}, name string, path ...string) {
+	if value, found := object.getString(name); found {
+		setField(fields, value, path...)
+	}
+}
+
+// setBool sets a field to the value of a boolean attribute of the object
+func (object *moduleObject) setBool(fields map[string]interface{}, name string, path ...string) {
+	if value, found := object.getBool(name); found {
+		setField(fields, value, path...)
+	}
+}
+
+// setNumber sets a field to the value of a number attribute of the object
+func (object *moduleObject) setNumber(fields map[string]interface{}, name string, path ...string) {
+	if value, found := object.convertedValue(name, cty.Number); found {
+		setField(fields, numberValue(value), path...)
+	}
+}
+
+// checkUnused records the attributes of the object which are not translated
+func (object *moduleObject) checkUnused() {
+	names := make([]string, 0, len(object.values))
+	for name := range object.values {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if !object.used[name] && !object.values[name].IsNull() {
+			object.block.problem("attribute", fmt.Sprintf("%s.%s", object.field, name), "the attribute is not supported")
+		}
+	}
+}
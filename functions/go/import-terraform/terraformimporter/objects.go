@@ -0,0 +1,173 @@
+// Copyright 2022 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package terraformimporter
+
+import (
+	"math"
+	"path"
+	"strconv"
+	"strings"
+
+	sdk "github.com/GoogleContainerTools/kpt-functions-catalog/thirdparty/kyaml/fnsdk"
+	"sigs.k8s.io/kustomize/kyaml/kio/kioutil"
+	"sigs.k8s.io/kustomize/kyaml/yaml"
+)
+
+type kccObject struct {
+	APIVersion  string                 // The API version of the Config Connector resource
+	Kind        string                 // The Kubernetes Kind of the resource
+	Name        string                 // The name of the resource (for metadata.name)
+	Labels      map[string]string      // The labels of the resource
+	Annotations map[string]string      // The annotations of the resource, e.g. its project
+	Spec        map[string]interface{} // The spec of the resource
+	file        string                 // The Terraform file the resource is translated from
+}
+
+// newObject returns a resource translated from a block of a Terraform file
+func newObject(block *terraformBlock, apiVersion, kind, name string) *kccObject {
+	return &kccObject{
+		APIVersion:  apiVersion,
+		Kind:        kind,
+		Name:        name,
+		Annotations: make(map[string]string),
+		Spec:        make(map[string]interface{}),
+		file:        block.file,
+	}
+}
+
+// setField sets a nested field, creating the maps along the path
+func setField(fields map[string]interface{}, value interface{}, path ...string) {
+	for _, field := range path[:len(path)-1] {
+		next, ok := fields[field].(map[string]interface{})
+		if !ok {
+			next = make(map[string]interface{})
+			fields[field] = next
+		}
+		fields = next
+	}
+	fields[path[len(path)-1]] = value
+}
+
+// toKubeObject returns the resource as a KubeObject saved next to the
+// ConfigMap holding the Terraform file, in a file named after it
+func (object *kccObject) toKubeObject(dir string) (*sdk.KubeObject, error) {
+	annotations := map[string]string{
+		kioutil.PathAnnotation: path.Join(dir, strings.TrimSuffix(object.file, ".tf")+".yaml"),
+	}
+	for k, v := range object.Annotations {
+		annotations[k] = v
+	}
+	resource := map[string]interface{}{
+		"apiVersion": object.APIVersion,
+		"kind":       object.Kind,
+		"metadata": map[string]interface{}{
+			"name":        object.Name,
+			"annotations": annotations,
+		},
+	}
+	if len(object.Labels) > 0 {
+		resource["metadata"].(map[string]interface{})["labels"] = object.Labels
+	}
+	if len(object.Spec) > 0 {
+		resource["spec"] = object.Spec
+	}
+	o, err := sdk.NewFromTypedObject(resource)
+	if err != nil {
+		return nil, err
+	}
+	wholeNumbersToInts(o.ToRNode().YNode())
+	return o, nil
+}
+
+// wholeNumbersToInts turns the whole numbers of a node back into integers.
+// The SDK converts the resources through JSON, which turns the numbers into
+// floats, and the large ones would be written as e.g. 3.1536e+10.
+func wholeNumbersToInts(node *yaml.Node) {
+	if node.Kind == yaml.ScalarNode && node.Tag == yaml.NodeTagFloat {
+		if f, err := strconv.ParseFloat(node.Value, 64); err == nil && f == math.Trunc(f) && math.Abs(f) < 1<<53 {
+			node.Tag = yaml.NodeTagInt
+			node.Value = strconv.FormatInt(int64(f), 10)
+		}
+	}
+	for _, child := range node.Content {
+		wholeNumbersToInts(child)
+	}
+}
+
+// setString sets a field to the value of a string attribute
+func (block *terraformBlock) setString(fields map[string]interface{}, name string, path ...string) {
+	if value, found := block.getString(name); found {
+		setField(fields, value, path...)
+	}
+}
+
+// setBool sets a field to the value of a boolean attribute
+func (block *terraformBlock) setBool(fields map[string]interface{}, name string, path ...string) {
+	if value, found := block.getBool(name); found {
+		setField(fields, value, path...)
+	}
+}
+
+// setNumber sets a field to the value of a number attribute
+func (block *terraformBlock) setNumber(fields map[string]interface{}, name string, path ...string) {
+	if value, found := block.getNumber(name); found {
+		setField(fields, value, path...)
+	}
+}
+
+// setStrings sets a field to the value of a list attribute
+func (block *terraformBlock) setStrings(fields map[string]interface{}, name string, path ...string) {
+	if values, found := block.getStrings(name); found && len(values) > 0 {
+		setField(fields, values, path...)
+	}
+}
+
+// setReference sets a reference field to the resource of a kind
+// an attribute refers to
+func (block *terraformBlock) setReference(fields map[string]interface{}, name string, kind string, path ...string) {
+	if ref, found := block.getReference(name, kind); found {
+		setField(fields, ref, path...)
+	}
+}
+
+// setResourceID sets the resourceID of the spec when the name of the
+// resource in Google Cloud differs from the name of the Config Connector
+// resource
+func (block *terraformBlock) setResourceID(object *kccObject, name string) {
+	if value, found := block.getString(name); found && value != object.Name {
+		object.Spec["resourceID"] = value
+	}
+}
+
+// setProject sets the project annotation of the resource to the project an
+// attribute refers to
+func (block *terraformBlock) setProject(object *kccObject, name string) {
+	ref, found := block.getReference(name, "Project")
+	if !found {
+		return
+	}
+	if projectName, ok := ref["name"].(string); ok {
+		object.Annotations[projectIDAnnotation] = projectName
+		return
+	}
+	object.Annotations[projectIDAnnotation] = strings.TrimPrefix(ref["external"].(string), "projects/")
+}
+
+// setLabels sets the labels of the resource to the value of a map attribute
+func (block *terraformBlock) setLabels(object *kccObject, name string) {
+	if labels, found := block.getStringMap(name); found && len(labels) > 0 {
+		object.Labels = labels
+	}
+}
@@ -0,0 +1,590 @@
+// Copyright 2022 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package terraformimporter
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/zclconf/go-cty/cty"
+)
+
+const (
+	resourceManagerAPI   = "resourcemanager.cnrm.cloud.google.com/v1beta1"
+	iamAPI               = "iam.cnrm.cloud.google.com/v1beta1"
+	computeAPI           = "compute.cnrm.cloud.google.com/v1beta1"
+	serviceNetworkingAPI = "servicenetworking.cnrm.cloud.google.com/v1beta1"
+	kmsAPI               = "kms.cnrm.cloud.google.com/v1beta1"
+	containerAPI         = "container.cnrm.cloud.google.com/v1beta1"
+	sqlAPI               = "sql.cnrm.cloud.google.com/v1beta1"
+	pubSubAPI            = "pubsub.cnrm.cloud.google.com/v1beta1"
+	storageAPI           = "storage.cnrm.cloud.google.com/v1beta1"
+	bigQueryAPI          = "bigquery.cnrm.cloud.google.com/v1beta1"
+	loggingAPI           = "logging.cnrm.cloud.google.com/v1beta1"
+)
+
+type translator struct {
+	kind      string                                   // The Kind of the resource other blocks refer to by the address of the block, if any
+	name      func(block *terraformBlock) string       // The name of that resource, if it isn't the name of the block
+	translate func(block *terraformBlock) []*kccObject // Returns the resources for the block
+}
+
+var resourceTranslators = map[string]*translator{
+	"google_folder":                        {kind: "Folder", translate: translateFolder},
+	"google_project":                       {kind: "Project", name: attributeName("project_id"), translate: translateProject},
+	"google_organization_iam_member":       {translate: translateIAMMember("Organization", "org_id")},
+	"google_folder_iam_member":             {translate: translateIAMMember("Folder", "folder")},
+	"google_project_iam_member":            {translate: translateIAMMember("Project", "project")},
+	"google_organization_iam_audit_config": {translate: translateAuditConfig},
+	"google_compute_network":               {kind: "ComputeNetwork", translate: translateNetwork},
+	"google_compute_subnetwork":            {kind: "ComputeSubnetwork", translate: translateSubnetwork},
+	"google_compute_route":                 {kind: "ComputeRoute", translate: translateRoute},
+	"google_compute_firewall":              {kind: "ComputeFirewall", translate: translateFirewall},
+	"google_compute_router":                {kind: "ComputeRouter", translate: translateRouter},
+	"google_compute_router_nat":            {kind: "ComputeRouterNAT", translate: translateRouterNAT},
+	"google_compute_address":               {kind: "ComputeAddress", translate: translateAddress("region")},
+	"google_compute_global_address":        {kind: "ComputeAddress", translate: translateAddress("")},
+	"google_service_networking_connection": {kind: "ServiceNetworkingConnection", translate: translateServiceNetworkingConnection},
+	"google_service_account":               {kind: "IAMServiceAccount", translate: translateServiceAccount},
+	"google_kms_key_ring":                  {kind: "KMSKeyRing", translate: translateKeyRing},
+	"google_kms_crypto_key":                {kind: "KMSCryptoKey", translate: translateCryptoKey},
+	"google_container_cluster":             {kind: "ContainerCluster", translate: translateCluster},
+	"google_container_node_pool":           {kind: "ContainerNodePool", translate: translateNodePool},
+	"google_sql_database_instance":         {kind: "SQLInstance", translate: translateSQLInstance},
+	"google_sql_database":                  {kind: "SQLDatabase", translate: translateSQLDatabase},
+	"google_sql_user":                      {kind: "SQLUser", translate: translateSQLUser},
+	"google_pubsub_topic":                  {kind: "PubSubTopic", translate: translatePubSubTopic},
+	"google_storage_bucket":                {kind: "StorageBucket", translate: translateStorageBucket},
+	"google_bigquery_dataset":              {kind: "BigQueryDataset", translate: translateBigQueryDataset},
+}
+
+func translateFolder(block *terraformBlock) []*kccObject {
+	folder := newObject(block, resourceManagerAPI, "Folder", block.name())
+	block.setString(folder.Spec, "display_name", "displayName")
+	block.setParent(folder, "parent")
+	return []*kccObject{folder}
+}
+
+func translateProject(block *terraformBlock) []*kccObject {
+	project := newObject(block, resourceManagerAPI, "Project", attributeName("project_id")(block))
+	block.used["project_id"] = true
+	block.setString(project.Spec, "name", "name")
+	block.setHierarchy(project)
+	block.setReference(project.Spec, "billing_account", "BillingAccount", "billingAccountRef")
+	if autoCreateNetwork, found := block.getBool("auto_create_network"); found {
+		project.Annotations["cnrm.cloud.google.com/auto-create-network"] = fmt.Sprint(autoCreateNetwork)
+	}
+	block.setLabels(project, "labels")
+	return []*kccObject{project}
+}
+
+// translateIAMMember returns the translation of the IAM member resource of
+// a kind of resource, referred to by an attribute
+func translateIAMMember(kind string, attr string) func(block *terraformBlock) []*kccObject {
+	return func(block *terraformBlock) []*kccObject {
+		member := newObject(block, iamAPI, "IAMPolicyMember", block.name())
+		ref, found := block.getResourceRef(attr, kind)
+		if !found {
+			return nil
+		}
+		member.Spec["resourceRef"] = ref
+		block.setString(member.Spec, "role", "role")
+		block.setString(member.Spec, "member", "member")
+		return []*kccObject{member}
+	}
+}
+
+func translateAuditConfig(block *terraformBlock) []*kccObject {
+	auditConfig := newObject(block, iamAPI, "IAMAuditConfig", block.name())
+	ref, found := block.getResourceRef("org_id", "Organization")
+	if !found {
+		return nil
+	}
+	auditConfig.Spec["resourceRef"] = ref
+	block.setString(auditConfig.Spec, "service", "service")
+	var logConfigs []interface{}
+	for _, b := range block.blocks("audit_log_config") {
+		logConfig := make(map[string]interface{})
+		b.setString(logConfig, "log_type", "logType")
+		b.setStrings(logConfig, "exempted_members", "exemptedMembers")
+		logConfigs = append(logConfigs, logConfig)
+	}
+	if len(logConfigs) > 0 {
+		auditConfig.Spec["auditLogConfigs"] = logConfigs
+	}
+	return []*kccObject{auditConfig}
+}
+
+func translateNetwork(block *terraformBlock) []*kccObject {
+	network := newObject(block, computeAPI, "ComputeNetwork", block.name())
+	block.setResourceID(network, "name")
+	block.setProject(network, "project")
+	block.setString(network.Spec, "description", "description")
+	block.setBool(network.Spec, "auto_create_subnetworks", "autoCreateSubnetworks")
+	block.setString(network.Spec, "routing_mode", "routingMode")
+	block.setBool(network.Spec, "delete_default_routes_on_create", "deleteDefaultRoutesOnCreate")
+	block.setNumber(network.Spec, "mtu", "mtu")
+	return []*kccObject{network}
+}
+
+func translateSubnetwork(block *terraformBlock) []*kccObject {
+	subnet := newObject(block, computeAPI, "ComputeSubnetwork", block.name())
+	block.setResourceID(subnet, "name")
+	block.setProject(subnet, "project")
+	block.setReference(subnet.Spec, "network", "ComputeNetwork", "networkRef")
+	block.setString(subnet.Spec, "description", "description")
+	block.setString(subnet.Spec, "ip_cidr_range", "ipCidrRange")
+	block.setString(subnet.Spec, "region", "region")
+	block.setBool(subnet.Spec, "private_ip_google_access", "privateIpGoogleAccess")
+	var ranges []interface{}
+	for _, b := range block.blocks("secondary_ip_range") {
+		secondaryRange := make(map[string]interface{})
+		b.setString(secondaryRange, "range_name", "rangeName")
+		b.setString(secondaryRange, "ip_cidr_range", "ipCidrRange")
+		ranges = append(ranges, secondaryRange)
+	}
+	if len(ranges) > 0 {
+		subnet.Spec["secondaryIpRange"] = ranges
+	}
+	if b := block.block("log_config"); b != nil {
+		b.setString(subnet.Spec, "aggregation_interval", "logConfig", "aggregationInterval")
+		b.setNumber(subnet.Spec, "flow_sampling", "logConfig", "flowSampling")
+		b.setString(subnet.Spec, "metadata", "logConfig", "metadata")
+	}
+	return []*kccObject{subnet}
+}
+
+func translateRoute(block *terraformBlock) []*kccObject {
+	route := newObject(block, computeAPI, "ComputeRoute", block.name())
+	block.setResourceID(route, "name")
+	block.setProject(route, "project")
+	block.setReference(route.Spec, "network", "ComputeNetwork", "networkRef")
+	block.setString(route.Spec, "description", "description")
+	block.setString(route.Spec, "dest_range", "destRange")
+	block.setNumber(route.Spec, "priority", "priority")
+	block.setStrings(route.Spec, "tags", "tags")
+	block.setString(route.Spec, "next_hop_gateway", "nextHopGateway")
+	block.setString(route.Spec, "next_hop_ip", "nextHopIp")
+	return []*kccObject{route}
+}
+
+func translateFirewall(block *terraformBlock) []*kccObject {
+	firewall := newObject(block, computeAPI, "ComputeFirewall", block.name())
+	block.setResourceID(firewall, "name")
+	block.setProject(firewall, "project")
+	block.setReference(firewall.Spec, "network", "ComputeNetwork", "networkRef")
+	block.setString(firewall.Spec, "description", "description")
+	block.setString(firewall.Spec, "direction", "direction")
+	block.setNumber(firewall.Spec, "priority", "priority")
+	block.setBool(firewall.Spec, "disabled", "disabled")
+	block.setStrings(firewall.Spec, "source_ranges", "sourceRanges")
+	block.setStrings(firewall.Spec, "destination_ranges", "destinationRanges")
+	block.setStrings(firewall.Spec, "source_tags", "sourceTags")
+	block.setStrings(firewall.Spec, "target_tags", "targetTags")
+	for _, rule := range []string{"allow", "deny"} {
+		var rules []interface{}
+		for _, b := range block.blocks(rule) {
+			r := make(map[string]interface{})
+			b.setString(r, "protocol", "protocol")
+			b.setStrings(r, "ports", "ports")
+			rules = append(rules, r)
+		}
+		if len(rules) > 0 {
+			firewall.Spec[rule] = rules
+		}
+	}
+	if b := block.block("log_config"); b != nil {
+		b.setString(firewall.Spec, "metadata", "logConfig", "metadata")
+	}
+	return []*kccObject{firewall}
+}
+
+func translateRouter(block *terraformBlock) []*kccObject {
+	router := newObject(block, computeAPI, "ComputeRouter", block.name())
+	block.setResourceID(router, "name")
+	block.setProject(router, "project")
+	block.setReference(router.Spec, "network", "ComputeNetwork", "networkRef")
+	block.setString(router.Spec, "description", "description")
+	block.setString(router.Spec, "region", "region")
+	if b := block.block("bgp"); b != nil {
+		b.setNumber(router.Spec, "asn", "bgp", "asn")
+	}
+	return []*kccObject{router}
+}
+
+func translateRouterNAT(block *terraformBlock) []*kccObject {
+	nat := newObject(block, computeAPI, "ComputeRouterNAT", block.name())
+	block.setResourceID(nat, "name")
+	block.setProject(nat, "project")
+	block.setString(nat.Spec, "region", "region")
+	block.setReference(nat.Spec, "router", "ComputeRouter", "routerRef")
+	block.setString(nat.Spec, "nat_ip_allocate_option", "natIpAllocateOption")
+	if refs, found := block.getReferences("nat_ips", "ComputeAddress"); found {
+		nat.Spec["natIps"] = refs
+	}
+	block.setString(nat.Spec, "source_subnetwork_ip_ranges_to_nat", "sourceSubnetworkIpRangesToNat")
+	if b := block.block("log_config"); b != nil {
+		b.setBool(nat.Spec, "enable", "logConfig", "enable")
+		b.setString(nat.Spec, "filter", "logConfig", "filter")
+	}
+	return []*kccObject{nat}
+}
+
+// translateAddress returns the translation of the address resources, which
+// are regional when the location is given by an attribute, otherwise global
+func translateAddress(locationAttr string) func(block *terraformBlock) []*kccObject {
+	return func(block *terraformBlock) []*kccObject {
+		address := newObject(block, computeAPI, "ComputeAddress", block.name())
+		block.setResourceID(address, "name")
+		block.setProject(address, "project")
+		if locationAttr == "" {
+			address.Spec["location"] = "global"
+		} else {
+			block.setString(address.Spec, locationAttr, "location")
+		}
+		block.setString(address.Spec, "description", "description")
+		block.setString(address.Spec, "address", "address")
+		block.setString(address.Spec, "address_type", "addressType")
+		block.setString(address.Spec, "purpose", "purpose")
+		block.setNumber(address.Spec, "prefix_length", "prefixLength")
+		block.setReference(address.Spec, "network", "ComputeNetwork", "networkRef")
+		return []*kccObject{address}
+	}
+}
+
+func translateServiceNetworkingConnection(block *terraformBlock) []*kccObject {
+	connection := newObject(block, serviceNetworkingAPI, "ServiceNetworkingConnection", block.name())
+	block.setReference(connection.Spec, "network", "ComputeNetwork", "networkRef")
+	block.setString(connection.Spec, "service", "service")
+	if refs, found := block.getReferences("reserved_peering_ranges", "ComputeAddress"); found {
+		connection.Spec["reservedPeeringRanges"] = refs
+	}
+	return []*kccObject{connection}
+}
+
+func translateServiceAccount(block *terraformBlock) []*kccObject {
+	sa := newObject(block, iamAPI, "IAMServiceAccount", block.name())
+	block.setResourceID(sa, "account_id")
+	block.setProject(sa, "project")
+	block.setString(sa.Spec, "display_name", "displayName")
+	block.setString(sa.Spec, "description", "description")
+	block.setBool(sa.Spec, "disabled", "disabled")
+	return []*kccObject{sa}
+}
+
+func translateKeyRing(block *terraformBlock) []*kccObject {
+	keyRing := newObject(block, kmsAPI, "KMSKeyRing", block.name())
+	block.setResourceID(keyRing, "name")
+	block.setProject(keyRing, "project")
+	block.setString(keyRing.Spec, "location", "location")
+	return []*kccObject{keyRing}
+}
+
+func translateCryptoKey(block *terraformBlock) []*kccObject {
+	key := newObject(block, kmsAPI, "KMSCryptoKey", block.name())
+	block.setResourceID(key, "name")
+	block.setReference(key.Spec, "key_ring", "KMSKeyRing", "keyRingRef")
+	block.setString(key.Spec, "purpose", "purpose")
+	block.setString(key.Spec, "rotation_period", "rotationPeriod")
+	block.setBool(key.Spec, "import_only", "importOnly")
+	block.setBool(key.Spec, "skip_initial_version_creation", "skipInitialVersionCreation")
+	if b := block.block("version_template"); b != nil {
+		b.setString(key.Spec, "algorithm", "versionTemplate", "algorithm")
+		b.setString(key.Spec, "protection_level", "versionTemplate", "protectionLevel")
+	}
+	block.setLabels(key, "labels")
+	return []*kccObject{key}
+}
+
+func translateCluster(block *terraformBlock) []*kccObject {
+	cluster := newObject(block, containerAPI, "ContainerCluster", block.name())
+	block.setResourceID(cluster, "name")
+	block.setProject(cluster, "project")
+	if remove, found := block.getBool("remove_default_node_pool"); found && remove {
+		cluster.Annotations["cnrm.cloud.google.com/remove-default-node-pool"] = "true"
+	}
+	block.setString(cluster.Spec, "description", "description")
+	block.setString(cluster.Spec, "location", "location")
+	block.setNumber(cluster.Spec, "initial_node_count", "initialNodeCount")
+	block.setString(cluster.Spec, "min_master_version", "minMasterVersion")
+	block.setReference(cluster.Spec, "network", "ComputeNetwork", "networkRef")
+	block.setReference(cluster.Spec, "subnetwork", "ComputeSubnetwork", "subnetworkRef")
+	block.setLabels(cluster, "resource_labels")
+	if b := block.block("release_channel"); b != nil {
+		b.setString(cluster.Spec, "channel", "releaseChannel", "channel")
+	}
+	if b := block.block("workload_identity_config"); b != nil {
+		b.setString(cluster.Spec, "workload_pool", "workloadIdentityConfig", "workloadPool")
+	}
+	if b := block.block("ip_allocation_policy"); b != nil {
+		b.setString(cluster.Spec, "cluster_ipv4_cidr_block", "ipAllocationPolicy", "clusterIpv4CidrBlock")
+		b.setString(cluster.Spec, "services_ipv4_cidr_block", "ipAllocationPolicy", "servicesIpv4CidrBlock")
+		b.setString(cluster.Spec, "cluster_secondary_range_name", "ipAllocationPolicy", "clusterSecondaryRangeName")
+		b.setString(cluster.Spec, "services_secondary_range_name", "ipAllocationPolicy", "servicesSecondaryRangeName")
+	}
+	if b := block.block("private_cluster_config"); b != nil {
+		b.setBool(cluster.Spec, "enable_private_nodes", "privateClusterConfig", "enablePrivateNodes")
+		b.setBool(cluster.Spec, "enable_private_endpoint", "privateClusterConfig", "enablePrivateEndpoint")
+		b.setString(cluster.Spec, "master_ipv4_cidr_block", "privateClusterConfig", "masterIpv4CidrBlock")
+	}
+	if b := block.block("master_authorized_networks_config"); b != nil {
+		var cidrBlocks []interface{}
+		for _, cidr := range b.blocks("cidr_blocks") {
+			cidrBlock := make(map[string]interface{})
+			cidr.setString(cidrBlock, "cidr_block", "cidrBlock")
+			cidr.setString(cidrBlock, "display_name", "displayName")
+			cidrBlocks = append(cidrBlocks, cidrBlock)
+		}
+		setField(cluster.Spec, cidrBlocks, "masterAuthorizedNetworksConfig", "cidrBlocks")
+	}
+	return []*kccObject{cluster}
+}
+
+func translateNodePool(block *terraformBlock) []*kccObject {
+	pool := newObject(block, containerAPI, "ContainerNodePool", block.name())
+	block.setResourceID(pool, "name")
+	block.setProject(pool, "project")
+	block.setString(pool.Spec, "location", "location")
+	block.setReference(pool.Spec, "cluster", "ContainerCluster", "clusterRef")
+	block.setNumber(pool.Spec, "initial_node_count", "initialNodeCount")
+	block.setNumber(pool.Spec, "node_count", "nodeCount")
+	block.setString(pool.Spec, "version", "version")
+	if b := block.block("autoscaling"); b != nil {
+		b.setNumber(pool.Spec, "min_node_count", "autoscaling", "minNodeCount")
+		b.setNumber(pool.Spec, "max_node_count", "autoscaling", "maxNodeCount")
+	}
+	if b := block.block("management"); b != nil {
+		b.setBool(pool.Spec, "auto_repair", "management", "autoRepair")
+		b.setBool(pool.Spec, "auto_upgrade", "management", "autoUpgrade")
+	}
+	if b := block.block("node_config"); b != nil {
+		b.setString(pool.Spec, "machine_type", "nodeConfig", "machineType")
+		b.setNumber(pool.Spec, "disk_size_gb", "nodeConfig", "diskSizeGb")
+		b.setString(pool.Spec, "disk_type", "nodeConfig", "diskType")
+		b.setString(pool.Spec, "image_type", "nodeConfig", "imageType")
+		b.setBool(pool.Spec, "preemptible", "nodeConfig", "preemptible")
+		b.setReference(pool.Spec, "service_account", "IAMServiceAccount", "nodeConfig", "serviceAccountRef")
+		b.setStrings(pool.Spec, "oauth_scopes", "nodeConfig", "oauthScopes")
+		b.setStrings(pool.Spec, "tags", "nodeConfig", "tags")
+		if labels, found := b.getStringMap("labels"); found {
+			setField(pool.Spec, labels, "nodeConfig", "labels")
+		}
+	}
+	return []*kccObject{pool}
+}
+
+func translateSQLInstance(block *terraformBlock) []*kccObject {
+	instance := newObject(block, sqlAPI, "SQLInstance", block.name())
+	block.setResourceID(instance, "name")
+	block.setProject(instance, "project")
+	block.setString(instance.Spec, "database_version", "databaseVersion")
+	block.setString(instance.Spec, "region", "region")
+	block.setReference(instance.Spec, "encryption_key_name", "KMSCryptoKey", "encryptionKMSCryptoKeyRef")
+	settings := block.block("settings")
+	if settings == nil {
+		return []*kccObject{instance}
+	}
+	settings.setString(instance.Spec, "tier", "settings", "tier")
+	settings.setString(instance.Spec, "availability_type", "settings", "availabilityType")
+	settings.setNumber(instance.Spec, "disk_size", "settings", "diskSize")
+	settings.setString(instance.Spec, "disk_type", "settings", "diskType")
+	settings.setBool(instance.Spec, "disk_autoresize", "settings", "diskAutoresize")
+	settings.setLabels(instance, "user_labels")
+	if b := settings.block("backup_configuration"); b != nil {
+		b.setBool(instance.Spec, "enabled", "settings", "backupConfiguration", "enabled")
+		b.setString(instance.Spec, "start_time", "settings", "backupConfiguration", "startTime")
+		b.setBool(instance.Spec, "point_in_time_recovery_enabled", "settings", "backupConfiguration", "pointInTimeRecoveryEnabled")
+		b.setBool(instance.Spec, "binary_log_enabled", "settings", "backupConfiguration", "binaryLogEnabled")
+	}
+	if b := settings.block("ip_configuration"); b != nil {
+		b.setBool(instance.Spec, "ipv4_enabled", "settings", "ipConfiguration", "ipv4Enabled")
+		b.setReference(instance.Spec, "private_network", "ComputeNetwork", "settings", "ipConfiguration", "privateNetworkRef")
+		b.setBool(instance.Spec, "require_ssl", "settings", "ipConfiguration", "requireSsl")
+		var networks []interface{}
+		for _, n := range b.blocks("authorized_networks") {
+			network := make(map[string]interface{})
+			n.setString(network, "name", "name")
+			n.setString(network, "value", "value")
+			networks = append(networks, network)
+		}
+		if len(networks) > 0 {
+			setField(instance.Spec, networks, "settings", "ipConfiguration", "authorizedNetworks")
+		}
+	}
+	var flags []interface{}
+	for _, b := range settings.blocks("database_flags") {
+		flag := make(map[string]interface{})
+		b.setString(flag, "name", "name")
+		b.setString(flag, "value", "value")
+		flags = append(flags, flag)
+	}
+	if len(flags) > 0 {
+		setField(instance.Spec, flags, "settings", "databaseFlags")
+	}
+	return []*kccObject{instance}
+}
+
+func translateSQLDatabase(block *terraformBlock) []*kccObject {
+	database := newObject(block, sqlAPI, "SQLDatabase", block.name())
+	block.setResourceID(database, "name")
+	block.setProject(database, "project")
+	block.setReference(database.Spec, "instance", "SQLInstance", "instanceRef")
+	block.setString(database.Spec, "charset", "charset")
+	block.setString(database.Spec, "collation", "collation")
+	return []*kccObject{database}
+}
+
+func translateSQLUser(block *terraformBlock) []*kccObject {
+	user := newObject(block, sqlAPI, "SQLUser", block.name())
+	block.setResourceID(user, "name")
+	block.setProject(user, "project")
+	block.setReference(user.Spec, "instance", "SQLInstance", "instanceRef")
+	block.setString(user.Spec, "host", "host")
+	// passwords are never imported, they must be provided in a Secret named
+	// after the user instead
+	if _, found := block.attribute("password"); found {
+		setField(user.Spec, map[string]interface{}{
+			"name": user.Name,
+			"key":  "password",
+		}, "password", "valueFrom", "secretKeyRef")
+	}
+	return []*kccObject{user}
+}
+
+func translatePubSubTopic(block *terraformBlock) []*kccObject {
+	topic := newObject(block, pubSubAPI, "PubSubTopic", block.name())
+	block.setResourceID(topic, "name")
+	block.setProject(topic, "project")
+	block.setReference(topic.Spec, "kms_key_name", "KMSCryptoKey", "kmsKeyRef")
+	block.setString(topic.Spec, "message_retention_duration", "messageRetentionDuration")
+	block.setLabels(topic, "labels")
+	return []*kccObject{topic}
+}
+
+func translateStorageBucket(block *terraformBlock) []*kccObject {
+	bucket := newObject(block, storageAPI, "StorageBucket", block.name())
+	block.setResourceID(bucket, "name")
+	block.setProject(bucket, "project")
+	block.setString(bucket.Spec, "location", "location")
+	block.setString(bucket.Spec, "storage_class", "storageClass")
+	block.setBool(bucket.Spec, "uniform_bucket_level_access", "uniformBucketLevelAccess")
+	if b := block.block("versioning"); b != nil {
+		b.setBool(bucket.Spec, "enabled", "versioning", "enabled")
+	}
+	block.setLabels(bucket, "labels")
+	return []*kccObject{bucket}
+}
+
+func translateBigQueryDataset(block *terraformBlock) []*kccObject {
+	dataset := newObject(block, bigQueryAPI, "BigQueryDataset", block.name())
+	block.setResourceID(dataset, "dataset_id")
+	block.setProject(dataset, "project")
+	block.setString(dataset.Spec, "location", "location")
+	block.setString(dataset.Spec, "friendly_name", "friendlyName")
+	block.setString(dataset.Spec, "description", "description")
+	block.setNumber(dataset.Spec, "default_table_expiration_ms", "defaultTableExpirationMs")
+	block.setLabels(dataset, "labels")
+	return []*kccObject{dataset}
+}
+
+// attributeName returns the name of the resource for a block, which is the
+// value of the first attribute set, e.g. the ID of a project, or the name of
+// the block
+func attributeName(attrs ...string) func(block *terraformBlock) string {
+	return func(block *terraformBlock) string {
+		for _, attr := range attrs {
+			expr, found := block.body.Attributes[attr]
+			if !found {
+				continue
+			}
+			value, diags := expr.Expr.Value(block.module.ctx)
+			if !diags.HasErrors() && !value.IsNull() && value.IsWhollyKnown() && value.Type() == cty.String {
+				return kccName(value.AsString())
+			}
+		}
+		return block.name()
+	}
+}
+
+// setParent sets the parent of a resource to the folder or the organization
+// an attribute refers to, e.g. "organizations/123456789012"
+func (block *terraformBlock) setParent(object *kccObject, attr string) {
+	expr, found := block.attribute(attr)
+	if !found {
+		return
+	}
+	if _, _, isReference := block.module.reference(expr); isReference {
+		block.setReference(object.Spec, attr, "Folder", "folderRef")
+		return
+	}
+	parent, found := block.getString(attr)
+	if !found {
+		return
+	}
+	switch {
+	case strings.HasPrefix(parent, "organizations/"):
+		object.Spec["organizationRef"] = map[string]interface{}{"external": strings.TrimPrefix(parent, "organizations/")}
+	case strings.HasPrefix(parent, "folders/"):
+		object.Spec["folderRef"] = map[string]interface{}{"external": strings.TrimPrefix(parent, "folders/")}
+	default:
+		block.problem("attribute", attr, fmt.Sprintf("the parent %q is neither an organization nor a folder", parent))
+	}
+}
+
+// setHierarchy sets the parent of a project to its folder, or to its
+// organization when it isn't in a folder
+func (block *terraformBlock) setHierarchy(object *kccObject) {
+	if ref, found := block.getReference("folder_id", "Folder"); found {
+		if external, ok := ref["external"].(string); ok {
+			ref["external"] = strings.TrimPrefix(external, "folders/")
+		}
+		object.Spec["folderRef"] = ref
+		// the organization is implied by the folder
+		block.used["org_id"] = true
+		return
+	}
+	if ref, found := block.getReference("org_id", "Organization"); found {
+		if external, ok := ref["external"].(string); ok {
+			ref["external"] = strings.TrimPrefix(external, "organizations/")
+		}
+		object.Spec["organizationRef"] = ref
+	}
+}
+
+// getResourceRef returns the resourceRef of an IAM resource to the
+// resource of a kind an attribute refers to
+func (block *terraformBlock) getResourceRef(attr string, kind string) (map[string]interface{}, bool) {
+	ref, found := block.getReference(attr, kind)
+	if !found {
+		return nil, false
+	}
+	return resourceRef(kind, ref), true
+}
+
+// resourceRef returns the resourceRef of an IAM resource from a reference
+// to a resource of a kind
+func resourceRef(kind string, ref map[string]interface{}) map[string]interface{} {
+	if external, ok := ref["external"].(string); ok {
+		prefix := map[string]string{
+			"Organization": "organizations/",
+			"Folder":       "folders/",
+			"Project":      "projects/",
+		}[kind]
+		ref["external"] = strings.TrimPrefix(external, prefix)
+	}
+	ref["apiVersion"] = resourceManagerAPI
+	ref["kind"] = kind
+	return ref
+}
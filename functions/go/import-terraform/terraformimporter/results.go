@@ -0,0 +1,67 @@
+// Copyright 2022 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package terraformimporter
+
+import (
+	"fmt"
+
+	sdk "github.com/GoogleContainerTools/kpt-functions-catalog/thirdparty/kyaml/fnsdk"
+)
+
+// importReport collects the results for the Terraform blocks which didn't
+// make it into the Config Connector resources
+type importReport struct {
+	results      sdk.Results
+	total        int
+	untranslated int
+	partial      int
+}
+
+// untranslatedBlock reports a block which no resource is generated for
+func (report *importReport) untranslatedBlock(block *terraformBlock, reason string) {
+	report.untranslated++
+	report.results = append(report.results, configMapResult(
+		fmt.Sprintf("%s is not translated: %s", block, reason),
+		block.configMap, sdk.Warning))
+}
+
+// untranslatedFields reports the attributes and the nested blocks left out
+// of the resources generated for a block
+func (report *importReport) untranslatedFields(block *terraformBlock) {
+	if len(block.problems) == 0 {
+		return
+	}
+	report.partial++
+	for _, problem := range block.problems {
+		report.results = append(report.results, configMapResult(
+			fmt.Sprintf("%s is translated without %s: %s", block, problem.field, problem.reason),
+			block.configMap, sdk.Warning))
+	}
+}
+
+// summary returns the result with the counts of the report
+func (report *importReport) summary() *sdk.Result {
+	return sdk.GeneralResult(fmt.Sprintf(
+		"translated %d of %d Terraform blocks to Config Connector resources: %d not translated, %d translated partially",
+		report.total-report.untranslated, report.total, report.untranslated, report.partial), sdk.Info)
+}
+
+// configMapResult returns a result for the ConfigMap holding the Terraform
+// configuration, with the path of its file
+func configMapResult(msg string, item *sdk.KubeObject, severity sdk.Severity) *sdk.Result {
+	result := sdk.ConfigObjectResult(msg, item, severity)
+	result.File.Path = configMapPath(item)
+	return result
+}
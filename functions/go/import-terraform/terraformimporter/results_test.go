@@ -0,0 +1,78 @@
+// Copyright 2022 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package terraformimporter
+
+import (
+	"path"
+	"testing"
+
+	sdk "github.com/GoogleContainerTools/kpt-functions-catalog/thirdparty/kyaml/fnsdk"
+	"github.com/stretchr/testify/require"
+)
+
+type importResult struct {
+	Severity sdk.Severity
+	File     string
+	Message  string
+}
+
+func TestProcessorResults(t *testing.T) {
+	testCases := []struct {
+		name    string
+		results []importResult
+	}{
+		{
+			name: "sql",
+			results: []importResult{
+				{sdk.Info, "", "translated 7 of 7 Terraform blocks to Config Connector resources: 0 not translated, 0 translated partially"},
+			},
+		},
+		{
+			name: "unsupported",
+			results: []importResult{
+				{sdk.Warning, "terraform.yaml", `data "google_organization" "org" (main.tf:6) is not translated: data sources are not supported`},
+				{sdk.Warning, "terraform.yaml", `module "gke" (main.tf:91) is not translated: the module source "terraform-google-modules/kubernetes-engine/google" is not supported`},
+				{sdk.Warning, "terraform.yaml", `module "team-iam" (main.tf:73) is not translated: attribute mode: the bindings are translated to IAMPolicyMember resources, which are additive`},
+				{sdk.Warning, "terraform.yaml", `resource "google_compute_firewall" "allow_ssh" (main.tf:50) is translated without attribute source_ranges[1]: the value is null`},
+				{sdk.Warning, "terraform.yaml", `resource "google_compute_instance" "vm" (main.tf:61) is not translated: the resource type google_compute_instance is not supported`},
+				{sdk.Warning, "terraform.yaml", `resource "google_folder" "team" (main.tf:10) is translated without attribute parent: it references data.google_organization.org which is not translated`},
+				{sdk.Warning, "terraform.yaml", `resource "google_project" "labeled_project" (main.tf:27) is translated without attribute labels["a"]: the value is null`},
+				{sdk.Warning, "terraform.yaml", `resource "google_project" "team_project" (main.tf:15) is translated without attribute billing_account: var.billing_account has no known value`},
+				{sdk.Warning, "terraform.yaml", `resource "google_project" "team_project" (main.tf:15) is translated without attribute skip_delete: the attribute is not supported`},
+				{sdk.Warning, "terraform.yaml", `resource "google_project_iam_member" "viewers" (main.tf:66) is not translated: count is not supported`},
+				{sdk.Info, "", "translated 6 of 11 Terraform blocks to Config Connector resources: 5 not translated, 4 translated partially"},
+			},
+		},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			require := require.New(t)
+			rl, err := resourceListFromTerraform(path.Join("..", testDir, tt.name, "tf"))
+			require.NoError(err)
+			require.NoError(Processor(rl))
+
+			var results []importResult
+			for _, result := range rl.Results {
+				var file string
+				if result.File != nil {
+					file = result.File.Path
+				}
+				results = append(results, importResult{result.Severity, file, result.Message})
+			}
+			require.Equal(tt.results, results)
+		})
+	}
+}
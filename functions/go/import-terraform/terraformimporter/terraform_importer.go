@@ -0,0 +1,84 @@
+// Copyright 2022 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package terraformimporter
+
+import (
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+
+	sdk "github.com/GoogleContainerTools/kpt-functions-catalog/thirdparty/kyaml/fnsdk"
+	"sigs.k8s.io/kustomize/kyaml/kio/kioutil"
+)
+
+const (
+	syntaxAnnotation    = "blueprints.cloud.google.com/syntax"
+	projectIDAnnotation = "cnrm.cloud.google.com/project-id"
+)
+
+func Processor(rl *sdk.ResourceList) error {
+	report := &importReport{}
+	for _, item := range rl.Items {
+		if item.Kind() != "ConfigMap" || item.Annotation(syntaxAnnotation) != "hcl" {
+			continue
+		}
+		files := make(map[string]string)
+		if _, err := item.Get(&files, "data"); err != nil {
+			return err
+		}
+
+		module, err := parseModule(files)
+		if err != nil {
+			return fmt.Errorf("unable to parse the Terraform configuration of ConfigMap %q: %w", item.Name(), err)
+		}
+
+		dir := path.Dir(configMapPath(item))
+		for _, object := range module.translate(report, item) {
+			ko, err := object.toKubeObject(dir)
+			if err != nil {
+				return err
+			}
+			if err := rl.UpsertObjectToItems(ko, nil, true); err != nil {
+				return err
+			}
+		}
+	}
+
+	report.results.Sort()
+	rl.Results = append(rl.Results, report.results...)
+	rl.Results = append(rl.Results, report.summary())
+	return nil
+}
+
+// configMapPath returns the path of the file of the ConfigMap in the package
+func configMapPath(item *sdk.KubeObject) string {
+	if filePath := item.Annotation(kioutil.PathAnnotation); filePath != "" {
+		return filePath
+	}
+	return item.Annotation(kioutil.LegacyPathAnnotation)
+}
+
+// terraformFiles returns the names of the Terraform files in a stable order
+func terraformFiles(files map[string]string) []string {
+	var names []string
+	for name := range files {
+		if strings.HasSuffix(name, ".tf") {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,130 @@
+// Copyright 2022 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package terraformimporter
+
+import (
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	sdk "github.com/GoogleContainerTools/kpt-functions-catalog/thirdparty/kyaml/fnsdk"
+	"github.com/GoogleContainerTools/kpt-functions-catalog/thirdparty/kyaml/fnsdk/testutil"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/kustomize/kyaml/kio/kioutil"
+)
+
+const (
+	testDir                    = "testdata"
+	updateExpectedOutputEnvVar = "UPDATE_EXPECTED_OUTPUT"
+)
+
+var testCases = []string{
+	"iam",
+	"projects",
+	"network",
+	"gke",
+	"sql",
+	"log",
+	"unsupported",
+}
+
+func TestTerraformImport(t *testing.T) {
+	for _, name := range testCases {
+		t.Run(name, func(t *testing.T) {
+			require := require.New(t)
+			actualRL, err := resourceListFromTerraform(path.Join("..", testDir, name, "tf"))
+			require.NoError(err)
+
+			err = Processor(actualRL)
+			require.NoError(err)
+
+			// only compare the resources translated from the Terraform
+			var translated []*sdk.KubeObject
+			for _, item := range actualRL.Items {
+				if item.Kind() != "ConfigMap" {
+					translated = append(translated, item)
+				}
+			}
+			actualRL.Items = translated
+
+			outDir := path.Join("..", testDir, name, "output")
+			// update expected output if env var set
+			if strings.ToLower(os.Getenv(updateExpectedOutputEnvVar)) == "true" {
+				t.Logf("Updating expected output for %s", name)
+				require.NoError(os.RemoveAll(outDir))
+				require.NoError(os.MkdirAll(outDir, 0755))
+				require.NoError(testutil.ResourceListToDirectory(actualRL, outDir))
+			}
+
+			tmpDir, err := os.MkdirTemp("", "import-terraform-test-*")
+			defer os.RemoveAll(tmpDir)
+			require.NoError(err)
+			err = testutil.ResourceListToDirectory(actualRL, tmpDir)
+			require.NoError(err)
+
+			tmpDirRL, err := testutil.ResourceListFromDirectory(tmpDir, "")
+			require.NoError(err)
+			expectedRL, err := testutil.ResourceListFromDirectory(outDir, "")
+			require.NoError(err)
+
+			actualYAML, err := tmpDirRL.ToYAML()
+			require.NoError(err)
+			expectedYAML, err := expectedRL.ToYAML()
+			require.NoError(err)
+			require.YAMLEqf(string(expectedYAML), string(actualYAML), "output yaml doesn't match")
+		})
+	}
+}
+
+// resourceListFromTerraform returns a ResourceList with the Terraform files
+// of a directory in a ConfigMap, as export-terraform generates it
+func resourceListFromTerraform(sourceDir string) (*sdk.ResourceList, error) {
+	files, err := filepath.Glob(path.Join(sourceDir, "*.tf"))
+	if err != nil {
+		return nil, err
+	}
+
+	data := make(map[string]string)
+	for _, file := range files {
+		contents, err := os.ReadFile(file)
+		if err != nil {
+			return nil, err
+		}
+		data[filepath.Base(file)] = string(contents)
+	}
+
+	rl := &sdk.ResourceList{}
+	err = rl.UpsertObjectToItems(corev1.ConfigMap{
+		TypeMeta: metav1.TypeMeta{
+			Kind:       "ConfigMap",
+			APIVersion: "v1",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "terraform",
+			Annotations: map[string]string{
+				"config.kubernetes.io/local-config":  "true",
+				"blueprints.cloud.google.com/syntax": "hcl",
+				"blueprints.cloud.google.com/flavor": "terraform",
+				kioutil.PathAnnotation:               "terraform.yaml",
+			},
+		},
+		Data: data,
+	}, nil, false)
+	return rl, err
+}
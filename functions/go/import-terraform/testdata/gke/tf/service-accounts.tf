@@ -0,0 +1,5 @@
+resource "google_service_account" "gke-nodes" {
+  account_id   = "gke-nodes"
+  project      = module.prj-gke.project_id
+  display_name = "GKE node service account"
+}
@@ -0,0 +1,5 @@
+variable "org_id" {
+  description = "The organization id for the associated resources"
+  type        = string
+  default     = "11111111111"
+}
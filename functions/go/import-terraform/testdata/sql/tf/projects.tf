@@ -0,0 +1,9 @@
+module "prj-sql" {
+  source  = "terraform-google-modules/project-factory/google"
+  version = "~> 12.0"
+
+  name       = "prj-sql"
+  org_id     = var.org_id
+
+  billing_account = var.billing_account
+}
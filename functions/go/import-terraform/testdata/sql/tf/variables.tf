@@ -0,0 +1,16 @@
+variable "billing_account" {
+  description = "The ID of the billing account to associate projects with"
+  type        = string
+  default     = "AAAAAA-AAAAAA-AAAAAA"
+}
+
+variable "org_id" {
+  description = "The organization id for the associated resources"
+  type        = string
+  default     = "123456789012"
+}
+
+variable "sql_main_app_password" {
+  description = "The password of the SQL user app"
+  type        = string
+}
@@ -0,0 +1,94 @@
+locals {
+  sa_name = "sa-${local.env}"
+  env     = var.env
+}
+
+data "google_organization" "org" {
+  domain = "example.com"
+}
+
+resource "google_folder" "team" {
+  display_name = "Team"
+  parent       = data.google_organization.org.name
+}
+
+resource "google_project" "team_project" {
+  name            = "Team Project"
+  project_id      = "prj-team"
+  folder_id       = google_folder.team.name
+  billing_account = var.billing_account
+  skip_delete     = true
+
+  lifecycle {
+    prevent_destroy = true
+  }
+}
+
+resource "google_project" "labeled_project" {
+  name       = "Labeled Project"
+  project_id = "prj-labeled"
+  folder_id  = google_folder.team.name
+
+  labels = {
+    team = "infra"
+    a    = null
+  }
+}
+
+resource "google_service_account" "sa" {
+  account_id   = local.sa_name
+  project      = google_project.team_project.project_id
+  display_name = "Team service account"
+}
+
+resource "google_compute_network" "net" {
+  name                    = "Net_1"
+  project                 = "external-project"
+  auto_create_subnetworks = false
+}
+
+resource "google_compute_firewall" "allow_ssh" {
+  name          = "allow-ssh"
+  network       = google_compute_network.net.name
+  source_ranges = ["1.1.1.1/32", null]
+
+  allow {
+    protocol = "tcp"
+    ports    = ["22"]
+  }
+}
+
+resource "google_compute_instance" "vm" {
+  name         = "vm"
+  machine_type = "e2-medium"
+}
+
+resource "google_project_iam_member" "viewers" {
+  count   = 2
+  project = google_project.team_project.project_id
+  role    = "roles/viewer"
+  member  = "group:viewers-${count.index}@example.com"
+}
+
+module "team-iam" {
+  source  = "terraform-google-modules/iam/google//modules/projects_iam"
+  version = "~> 7.4"
+
+  projects = [google_project.team_project.project_id]
+  mode     = "authoritative"
+
+  bindings = {
+    "roles/editor" = [
+      "serviceAccount:${google_service_account.sa.email}",
+    ]
+    "roles/viewer" = [
+      "group:viewers@example.com",
+      null,
+    ]
+  }
+}
+
+module "gke" {
+  source  = "terraform-google-modules/kubernetes-engine/google"
+  version = "~> 24.0"
+}
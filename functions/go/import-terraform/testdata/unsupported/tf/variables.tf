@@ -0,0 +1,10 @@
+variable "env" {
+  description = "The environment of the team"
+  type        = string
+  default     = "dev"
+}
+
+variable "billing_account" {
+  description = "The ID of the billing account to associate projects with"
+  type        = string
+}
@@ -17,6 +17,52 @@ var ListSettersLong = `
 
 1. Searches for setter comments in input list of resources.
 1. Lists discovered setters and related information.
+
+Setters parameterizing array and map values are listed with ` + "`" + `array` + "`" + ` and ` + "`" + `map` + "`" + `
+types respectively, and the map values are listed in flow style e.g. ` + "`" + `{cpu: 500m}` + "`" + `.
+
+The current value of a setter is derived from the field value, including the setter
+references which apply transforms such as ` + "`" + `${app|lower}` + "`" + ` or ` + "`" + `${env|default:dev}` + "`" + `.
+The value is not listed if it can't be derived, e.g. ` + "`" + `My-App` + "`" + ` can't be the value of
+the setter referenced as ` + "`" + `${app|lower}` + "`" + `.
+
+If the fields parameterized by a setter have different values for the setter, e.g.
+after a field is edited manually, the first discovered value is listed and the conflict
+is reported as a warning naming the files and fields for each of the values. The values
+of the setters interpolated in a field, e.g. ` + "`" + `${image}:${tag}` + "`" + `, are compared as well.
+
+      [WARNING] setter "tag" has conflicting values: "1.16.1" in app.yaml (spec.template.spec.containers[0].image); "1.17.0" in sidecar.yaml (spec.template.spec.containers[0].image)
+
+The setters tagged inside YAML or JSON documents embedded in string field values,
+e.g. the application config in the ` + "`" + `data` + "`" + ` of a ` + "`" + `ConfigMap` + "`" + `, are listed if the
+resource is annotated with ` + "`" + `kpt.dev/embedded-setters: "true"` + "`" + `.
+
+The setters can also be exported as a [JSON Schema], which is also a valid OpenAPI v3
+schema, e.g. to render a form for the package. The schema is written to the file
+path provided using ` + "`" + `schema-path` + "`" + `, as a ` + "`" + `SettersSchema` + "`" + ` resource annotated with
+` + "`" + `config.kubernetes.io/local-config: "true"` + "`" + `.
+
+  $ kpt fn eval -i list-setters:unstable -- schema-path=setters-schema.yaml
+
+The schema declares a property for each setter with the type inferred from the
+field values, the current value of the setter as the ` + "`" + `default` + "`" + ` value, and the
+fields parameterized by the setter using the ` + "`" + `x-kpt-fields` + "`" + ` extension.
+
+  apiVersion: fn.kpt.dev/v1alpha1
+  kind: SettersSchema
+  metadata:
+    name: setters-schema
+    annotations:
+      config.kubernetes.io/local-config: "true"
+  schema:
+    type: object
+    properties:
+      nginx-replicas:
+        type: integer
+        default: 4
+        x-kpt-fields:
+          - file: resources.yaml
+            path: spec.replicas
 `
 var ListSettersExamples = `
 ### Listing setters in a package
@@ -0,0 +1,99 @@
+package listsetters
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// Conflict is a setter which has different values in the fields it parameterizes
+type Conflict struct {
+	// Name is the name of the setter
+	Name string
+
+	// Value is the value listed for the setter
+	Value string
+
+	// Fields are all the fields parameterized by the setter along with the
+	// value of the setter in each field
+	Fields []Field
+}
+
+func (c Conflict) String() string {
+	// group the fields by the value of the setter in the order of discovery
+	var values []string
+	fields := make(map[string][]string)
+	for _, f := range c.Fields {
+		if _, ok := fields[f.Value]; !ok {
+			values = append(values, f.Value)
+		}
+		fields[f.Value] = append(fields[f.Value], fmt.Sprintf("%s (%s)", f.FilePath, f.FieldPath))
+	}
+	var groups []string
+	for _, v := range values {
+		groups = append(groups, fmt.Sprintf("%q in %s", v, strings.Join(fields[v], ", ")))
+	}
+	return fmt.Sprintf("setter %q has conflicting values: %s", c.Name, strings.Join(groups, "; "))
+}
+
+// Conflicts returns the setters whose values derived from the fields they
+// parameterize are different, sorted by the setter name
+func (ls *ListSetters) Conflicts() []*Conflict {
+	var out []*Conflict
+	values := make(map[string]string)
+	for _, s := range ls.ScalarSetters {
+		values[s.Name] = s.Value
+	}
+	for _, s := range ls.ScalarSetters {
+		fields := make([]Field, len(s.Fields))
+		for i, f := range s.Fields {
+			fields[i] = f
+			if f.Value != s.Value && render(f.pattern, values) == f.fieldValue {
+				// the setter value derived from the interpolated field is ambiguous,
+				// but the field is consistent with the listed setter values
+				// e.g. ${project}-${cluster} in the field my-project-us-east4
+				fields[i].Value = s.Value
+			}
+		}
+		out = appendConflict(out, s.Name, s.Value, fields)
+	}
+	for _, s := range ls.ArraySetters {
+		out = appendConflict(out, s.Name, fmt.Sprintf("[%s]", strings.Join(s.Values, ", ")), s.Fields)
+	}
+	for _, s := range ls.MapSetters {
+		out = appendConflict(out, s.Name, s.Value, s.Fields)
+	}
+	sort.SliceStable(out, func(i, j int) bool { return out[i].Name < out[j].Name })
+	return out
+}
+
+// appendConflict appends the Conflict to out if any of the fields has a
+// different value for the setter than the others
+func appendConflict(out []*Conflict, name, value string, fields []Field) []*Conflict {
+	for _, f := range fields {
+		if f.Value != fields[0].Value {
+			return append(out, &Conflict{Name: name, Value: value, Fields: fields})
+		}
+	}
+	return out
+}
+
+var setterRefPattern = regexp.MustCompile(`\$\{([^}]*)\}`)
+
+// render returns the field value for the setter pattern using the input setter
+// values, the setter references without values are left as is
+func render(pattern string, values map[string]string) string {
+	return setterRefPattern.ReplaceAllStringFunc(pattern, func(ref string) string {
+		r := parseSetterRef(ref)
+		v, ok := values[r.name]
+		if !ok {
+			return ref
+		}
+		ev, err := r.evaluate(v)
+		if err != nil {
+			return ref
+		}
+		return ev
+	})
+}
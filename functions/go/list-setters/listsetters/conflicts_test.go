@@ -0,0 +1,144 @@
+package listsetters
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"sigs.k8s.io/kustomize/kyaml/kio"
+)
+
+func TestConflicts(t *testing.T) {
+	var tests = []struct {
+		name     string
+		input    string
+		expected []string
+	}{
+		{
+			name: "consistent values",
+			input: `apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: my-app # kpt-set: ${app}
+  annotations:
+    config.kubernetes.io/path: deployment.yaml
+spec:
+  template:
+    spec:
+      containers:
+        - name: my-app # kpt-set: ${app}
+          image: nginx:1.16.1 # kpt-set: ${image}:${tag}
+`,
+		},
+		{
+			name: "conflicting scalar values",
+			input: `apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: my-app # kpt-set: ${app}
+  annotations:
+    config.kubernetes.io/path: deployment.yaml
+spec:
+  replicas: 3 # kpt-set: ${replicas}
+---
+apiVersion: v1
+kind: Service
+metadata:
+  name: my-service # kpt-set: ${app}
+  annotations:
+    config.kubernetes.io/path: service.yaml
+---
+apiVersion: v1
+kind: ServiceAccount
+metadata:
+  name: my-app # kpt-set: ${app}
+  annotations:
+    config.kubernetes.io/path: sa.yaml
+`,
+			expected: []string{
+				`setter "app" has conflicting values: "my-app" in deployment.yaml (metadata.name), sa.yaml (metadata.name); "my-service" in service.yaml (metadata.name)`,
+			},
+		},
+		{
+			name: "conflicting interpolated values",
+			input: `apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: my-app
+  annotations:
+    config.kubernetes.io/path: deployment.yaml
+spec:
+  template:
+    spec:
+      containers:
+        - name: app
+          image: nginx:1.16.1 # kpt-set: ${image}:${tag}
+        - name: sidecar
+          image: nginx:1.17.0 # kpt-set: ${image}:${tag}
+`,
+			expected: []string{
+				`setter "tag" has conflicting values: "1.16.1" in deployment.yaml (spec.template.spec.containers[0].image); "1.17.0" in deployment.yaml (spec.template.spec.containers[1].image)`,
+			},
+		},
+		{
+			name: "ambiguous interpolated values",
+			input: `apiVersion: container.cnrm.cloud.google.com/v1beta1
+kind: ContainerCluster
+metadata:
+  name: example-us-east4 # kpt-set: ${cluster-name}
+  annotations:
+    config.kubernetes.io/path: cluster.yaml
+    cnrm.cloud.google.com/project-id: platform-project-id # kpt-set: ${platform-project-id}
+spec:
+  subnetworkRef:
+    name: platform-project-id-example-us-east4 # kpt-set: ${platform-project-id}-${cluster-name}
+`,
+		},
+		{
+			name: "conflicting array and map values",
+			input: `apiVersion: v1
+kind: MyKind
+metadata:
+  name: foo
+  annotations:
+    config.kubernetes.io/path: foo.yaml
+envs: # kpt-set: ${envs}
+  - dev
+selector: # kpt-set: ${selector}
+  app: foo
+---
+apiVersion: v1
+kind: MyKind
+metadata:
+  name: bar
+  annotations:
+    config.kubernetes.io/path: bar.yaml
+envs: [dev, prod] # kpt-set: ${envs}
+selector: # kpt-set: ${selector}
+  app: foo
+`,
+			expected: []string{
+				`setter "envs" has conflicting values: "[dev]" in foo.yaml (envs); "[dev, prod]" in bar.yaml (envs)`,
+			},
+		},
+	}
+	for i := range tests {
+		test := tests[i]
+		t.Run(test.name, func(t *testing.T) {
+			require := require.New(t)
+			nodes, err := (&kio.ByteReader{
+				Reader:                strings.NewReader(test.input),
+				OmitReaderAnnotations: true,
+			}).Read()
+			require.NoError(err)
+			ls := New()
+			_, err = ls.Filter(nodes)
+			require.NoError(err)
+			var actual []string
+			for _, c := range ls.Conflicts() {
+				actual = append(actual, c.String())
+			}
+			require.Equal(test.expected, actual)
+		})
+	}
+}
@@ -16,6 +16,10 @@ import (
 
 const SetterCommentIdentifier = "# kpt-set: "
 
+// EmbeddedSettersAnnotation opts in a resource to list the setters tagged inside
+// the YAML or JSON documents embedded in its string field values
+const EmbeddedSettersAnnotation = "kpt.dev/embedded-setters"
+
 // ListSetters lists setters identified by the setter comments
 type ListSetters struct {
 	// ScalarSetters holds the discovered scalar setters
@@ -24,11 +28,22 @@ type ListSetters struct {
 	// ArraySetters holds the discovered array setters
 	ArraySetters map[string]*ArraySetter
 
+	// MapSetters holds the discovered map setters
+	MapSetters map[string]*MapSetter
+
 	// Warnings holds recoverable error info that occurred during setter discovery
 	Warnings []*WarnSetterDiscovery
 
 	// filePath file path of resource
 	filePath string
+
+	// parseEmbedded is true if the setters in the embedded documents of the
+	// current resource must be listed
+	parseEmbedded bool
+
+	// embeddedPath is the path of the string field if the setters are being
+	// listed in an embedded document
+	embeddedPath string
 }
 
 // ScalarSetter stores name, value and count of the scalar setter
@@ -44,6 +59,9 @@ type ScalarSetter struct {
 
 	// Count is the number of fields parameterized by the setter
 	Count int
+
+	// Fields are the fields parameterized by the setter
+	Fields []Field
 }
 
 // ArraySetter stores name, values and count of the array setter
@@ -56,6 +74,42 @@ type ArraySetter struct {
 
 	// Count is the number of fields parameterized by the setter
 	Count int
+
+	// Fields are the fields parameterized by the setter
+	Fields []Field
+}
+
+// MapSetter stores name, value and count of the map setter
+type MapSetter struct {
+	// Name is the name of the setter
+	Name string
+
+	// Value is the map value of the field parameterized by the setter in flow style
+	Value string
+
+	// Count is the number of fields parameterized by the setter
+	Count int
+
+	// Fields are the fields parameterized by the setter
+	Fields []Field
+}
+
+// Field is a field parameterized by a setter
+type Field struct {
+	// FilePath is the file path of the resource
+	FilePath string
+
+	// FieldPath is the path of the field in the resource
+	FieldPath string
+
+	// Value is the value of the setter derived from the field
+	Value string
+
+	// pattern is the setter pattern in the comment of the scalar field
+	pattern string
+
+	// fieldValue is the value of the scalar field
+	fieldValue string
 }
 
 // Result represents results of setter discovery
@@ -81,6 +135,7 @@ func (e *WarnSetterDiscovery) Error() string {
 
 const (
 	ArraySetterType         string = "array"
+	MapSetterType           string = "map"
 	ScalarSetterDefaultType string = "str"
 )
 
@@ -181,9 +236,32 @@ func getArraySetterValues(sv string) ([]string, error) {
 	return setterVals, nil
 }
 
+// getMapSetterValue attempts to parse a map setter value wrapped as string
+// and returns the map in flow style e.g. {cpu: 100m, memory: 1Gi}
+func getMapSetterValue(sv string) (string, bool) {
+	rn, err := yaml.Parse(sv)
+	if err != nil || rn.YNode().Kind != yaml.MappingNode {
+		return "", false
+	}
+	return flowStyleString(rn.YNode()), true
+}
+
+// flowStyleString returns the single line flow style representation of the node
+func flowStyleString(node *yaml.Node) string {
+	n := *node
+	n.Style = yaml.FlowStyle
+	n.LineComment = ""
+	s, err := yaml.NewRNode(&n).String()
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(s)
+}
+
 func New() ListSetters {
 	ls := ListSetters{}
 	ls.ArraySetters = make(map[string]*ArraySetter)
+	ls.MapSetters = make(map[string]*MapSetter)
 	ls.ScalarSetters = make(map[string]*ScalarSetter)
 	return ls
 }
@@ -191,6 +269,10 @@ func New() ListSetters {
 //addKptfileSetters parses setters in fn config to ArraySetters or ScalarSetters
 func (ls *ListSetters) addKptfileSetters(s map[string]string) {
 	for setterName, setterValue := range s {
+		if mv, ok := getMapSetterValue(setterValue); ok {
+			ls.MapSetters[setterName] = &MapSetter{Name: setterName, Value: mv, Count: 0}
+			continue
+		}
 		v, err := getArraySetterValues(setterValue)
 		if err == nil {
 			ls.ArraySetters[setterName] = &ArraySetter{Name: setterName, Values: v, Count: 0}
@@ -206,6 +288,9 @@ func (ls *ListSetters) GetResults() []*Result {
 	for _, v := range ls.ArraySetters {
 		out = append(out, &Result{Name: v.Name, Value: fmt.Sprintf("[%s]", strings.Join(v.Values, ", ")), Count: v.Count, Type: ArraySetterType})
 	}
+	for _, v := range ls.MapSetters {
+		out = append(out, &Result{Name: v.Name, Value: v.Value, Count: v.Count, Type: MapSetterType})
+	}
 	for _, v := range ls.ScalarSetters {
 		out = append(out, &Result{Name: v.Name, Value: v.Value, Count: v.Count, Type: v.Type})
 	}
@@ -236,6 +321,7 @@ func (ls *ListSetters) Filter(nodes []*yaml.RNode) ([]*yaml.RNode, error) {
 			return nodes, err
 		}
 		ls.filePath = filePath
+		ls.parseEmbedded = nodes[i].GetAnnotations()[EmbeddedSettersAnnotation] == "true"
 		err = accept(ls, nodes[i])
 		if err != nil {
 			return nil, errors.Wrap(err)
@@ -247,8 +333,8 @@ func (ls *ListSetters) Filter(nodes []*yaml.RNode) ([]*yaml.RNode, error) {
 /*
 visitMapping takes input mapping node, and performs following steps
 checks if the key node of the input mapping node has line comment with SetterCommentIdentifier
-checks if the value node is of sequence node type
-if yes to both, adds to list of ArraySetters or updates count of corresponding ArraySetter
+checks if the value node is of sequence or mapping node type
+if yes to both, adds to list of ArraySetters or MapSetters, or updates count of corresponding setter
 */
 func (ls *ListSetters) visitMapping(object *yaml.RNode, path string) error {
 	return object.VisitFields(func(node *yaml.MapNode) error {
@@ -257,6 +343,10 @@ func (ls *ListSetters) visitMapping(object *yaml.RNode, path string) error {
 			return nil
 		}
 
+		if node.Value.YNode().Kind == yaml.MappingNode {
+			return ls.visitMapValue(node, path)
+		}
+
 		// return if it is not a sequence node
 		if node.Value.YNode().Kind != yaml.SequenceNode {
 			return nil
@@ -294,10 +384,35 @@ func (ls *ListSetters) visitMapping(object *yaml.RNode, path string) error {
 		} else {
 			ls.ArraySetters[setterName] = &ArraySetter{Name: setterName, Values: nodeValues, Count: 1}
 		}
+		ls.ArraySetters[setterName].Fields = append(ls.ArraySetters[setterName].Fields,
+			ls.field(path+"."+node.Key.YNode().Value, fmt.Sprintf("[%s]", strings.Join(nodeValues, ", "))))
 		return nil
 	})
 }
 
+// visitMapValue adds the setter parameterizing the mapping value of the field to
+// list of MapSetters or updates count of the corresponding MapSetter
+func (ls *ListSetters) visitMapValue(node *yaml.MapNode, path string) error {
+	linecomment := node.Key.YNode().LineComment
+	if node.Value.YNode().Style == yaml.FlowStyle {
+		linecomment = node.Value.YNode().LineComment
+	}
+	setterPattern := extractSetterPattern(linecomment)
+	if setterPattern == "" {
+		// the node is not tagged with setter pattern
+		return nil
+	}
+	setterName := clean(setterPattern)
+	if _, ok := ls.MapSetters[setterName]; ok {
+		ls.MapSetters[setterName].Count++
+	} else {
+		ls.MapSetters[setterName] = &MapSetter{Name: setterName, Value: flowStyleString(node.Value.YNode()), Count: 1}
+	}
+	ls.MapSetters[setterName].Fields = append(ls.MapSetters[setterName].Fields,
+		ls.field(path+"."+node.Key.YNode().Value, flowStyleString(node.Value.YNode())))
+	return nil
+}
+
 /*
 visitScalar accepts the input scalar node and performs following steps,
 checks if the line comment of input scalar node has prefix SetterCommentIdentifier
@@ -313,6 +428,12 @@ func (ls *ListSetters) visitScalar(object *yaml.RNode, path string) error {
 		return nil
 	}
 
+	if ls.parseEmbedded {
+		if err := ls.visitEmbedded(object, path); err != nil {
+			return err
+		}
+	}
+
 	linecomment := object.YNode().LineComment
 
 	// perform a direct set of the field if it matches
@@ -338,11 +459,47 @@ func (ls *ListSetters) visitScalar(object *yaml.RNode, path string) error {
 		} else {
 			ls.ScalarSetters[setterName] = &ScalarSetter{Name: setterName, Value: setterValue, Type: valueType, Count: 1}
 		}
+		field := ls.field(path, setterValue)
+		field.pattern, field.fieldValue = setterPattern, object.YNode().Value
+		ls.ScalarSetters[setterName].Fields = append(ls.ScalarSetters[setterName].Fields, field)
 
 	}
 	return nil
 }
 
+// visitEmbedded parses the string value of the scalar node as a YAML or JSON
+// document and lists the setters tagged with setter comments in the document
+func (ls *ListSetters) visitEmbedded(object *yaml.RNode, path string) error {
+	value := object.YNode().Value
+	if !strings.Contains(value, SetterCommentIdentifier) {
+		return nil
+	}
+	doc, err := yaml.Parse(value)
+	if err != nil || (doc.YNode().Kind != yaml.MappingNode && doc.YNode().Kind != yaml.SequenceNode) {
+		// not an embedded document
+		return nil
+	}
+	// embedded documents can't be nested
+	ls.parseEmbedded = false
+	ls.embeddedPath = strings.TrimPrefix(path, ".")
+	defer func() {
+		ls.parseEmbedded = true
+		ls.embeddedPath = ""
+	}()
+	return accept(ls, doc)
+}
+
+// field returns the Field in the input path of the current resource with the
+// value of the setter in the field, the path of the fields in embedded documents
+// is prefixed by the path of the string field e.g. data.app.yaml:replicas
+func (ls *ListSetters) field(path, value string) Field {
+	fieldPath := strings.TrimPrefix(path, ".")
+	if ls.embeddedPath != "" {
+		fieldPath = fmt.Sprintf("%s:%s", ls.embeddedPath, fieldPath)
+	}
+	return Field{FilePath: ls.filePath, FieldPath: fieldPath, Value: value}
+}
+
 // extractSetterPattern extracts the setter pattern from the line comment of the
 // yaml RNode. If the the line comment doesn't contain SetterCommentIdentifier
 // prefix, then it returns empty string
@@ -400,7 +557,11 @@ func currentSetterValues(pattern, value string) map[string]string {
 			// and expect users to provide all values
 			return make(map[string]string)
 		}
-		res[clean(urs[i])] = setterValues[i]
+		// derive the setter value by reversing the transforms in the setter reference
+		r := parseSetterRef(urs[i])
+		if v, ok := r.reverse(setterValues[i]); ok {
+			res[r.name] = v
+		}
 	}
 	return res
 }
@@ -460,6 +460,68 @@ metadata:
 				{Name: "replicas", Value: "3", Count: 3, Type: "int"}},
 			warnings: []*WarnSetterDiscovery{{"unable to find Kptfile, please include --include-meta-resources flag if a Kptfile is present"}},
 		},
+		{
+			name: "Map setters",
+			resourceMap: map[string]string{"test.yaml": `apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: my-app
+spec:
+  template:
+    spec:
+      containers:
+        - name: nginx
+          resources: # kpt-set: ${resources}
+            limits:
+              cpu: 500m
+              memory: 1Gi
+        - name: sidecar
+          resources: # kpt-set: ${resources}
+            limits:
+              cpu: 500m
+              memory: 1Gi
+      nodeSelector: {disktype: ssd} # kpt-set: ${node-selector}
+`, "Kptfile": `apiVersion: kpt.dev/v1
+kind: Kptfile
+metadata:
+  name: test
+pipeline:
+  mutators:
+    - image: gcr.io/kpt-fn/apply-setters:unstable
+      configMap:
+        resources: |
+          limits:
+            cpu: 500m
+            memory: 1Gi
+        labels: "{}"
+`},
+			expectedResult: []*Result{
+				{Name: "resources", Value: "{limits: {cpu: 500m, memory: 1Gi}}", Count: 2, Type: "map"},
+				{Name: "labels", Value: "{}", Count: 0, Type: "map"},
+				{Name: "node-selector", Value: "{disktype: ssd}", Count: 1, Type: "map"}},
+		},
+		{
+			name: "Scalar with transforms",
+			resourceMap: map[string]string{"test.yaml": `apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: my-app # kpt-set: ${app|lower|trunc:63}
+  namespace: dev # kpt-set: ${env|default:dev}
+spec:
+  template:
+    spec:
+      containers:
+        - name: nginx
+          resources:
+            limits:
+              cpu: "500" # kpt-set: ${cpu|quote}
+`},
+			expectedResult: []*Result{
+				{Name: "app", Value: "my-app", Count: 1, Type: "str"},
+				{Name: "env", Value: "dev", Count: 1, Type: "str"},
+				{Name: "cpu", Value: "500", Count: 1, Type: "str"}},
+			warnings: []*WarnSetterDiscovery{{"unable to find Kptfile, please include --include-meta-resources flag if a Kptfile is present"}},
+		},
 		{
 			name: "ambiguous setter value picks first value",
 			resourceMap: map[string]string{"test.yaml": `apiVersion: container.cnrm.cloud.google.com/v1beta1
@@ -477,6 +539,37 @@ spec:
 				{Name: "platform-project-id", Value: "platform-project-id", Count: 2, Type: "str"}},
 			warnings: []*WarnSetterDiscovery{{"unable to find Kptfile, please include --include-meta-resources flag if a Kptfile is present"}},
 		},
+		{
+			name: "embedded documents",
+			resourceMap: map[string]string{"test.yaml": `apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: my-app-config # kpt-set: ${app}-config
+  annotations:
+    kpt.dev/embedded-setters: "true"
+data:
+  app.yaml: |
+    replicas: 3 # kpt-set: ${replicas}
+    name: my-app # kpt-set: ${app}
+  values.json: |
+    {
+      "level": "debug" # kpt-set: ${level}
+    }
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: other-config
+data:
+  app.yaml: |
+    env: dev # kpt-set: ${env}
+`},
+			expectedResult: []*Result{
+				{Name: "app", Value: "my-app", Count: 2, Type: "str"},
+				{Name: "replicas", Value: "3", Count: 1, Type: "int"},
+				{Name: "level", Value: "debug", Count: 1, Type: "str"}},
+			warnings: []*WarnSetterDiscovery{{"unable to find Kptfile, please include --include-meta-resources flag if a Kptfile is present"}},
+		},
 	}
 	for _, test := range tests {
 		t.Run(test.name, func(t *testing.T) {
@@ -563,6 +656,33 @@ func TestCurrentSetterValues(t *testing.T) {
 				"app-image-tag":        "1.0.0",
 			},
 		},
+		{
+			name:    "setter values from pattern with transforms",
+			value:   "my-app-dev:1.0.0",
+			pattern: `${app|lower|trunc:63}-${env|default:dev}:${tag|quote}`,
+			expected: map[string]string{
+				"app": "my-app",
+				"env": "dev",
+				"tag": "1.0.0",
+			},
+		},
+		{
+			name:    "setter values from pattern with non-ASCII trunc",
+			value:   "café-dev",
+			pattern: `${app|trunc:4}-${env}`,
+			expected: map[string]string{
+				"app": "café",
+				"env": "dev",
+			},
+		},
+		{
+			name:    "setter values from pattern with transforms not reversible",
+			value:   "My-App-dev",
+			pattern: `${app|lower}-${env}`,
+			expected: map[string]string{
+				"env": "dev",
+			},
+		},
 		{
 			name:     "setter values from pattern unresolved",
 			value:    "foo-dev-bar-us-east-1-baz",
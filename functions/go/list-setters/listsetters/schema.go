@@ -0,0 +1,162 @@
+package listsetters
+
+import (
+	"sort"
+	"strconv"
+
+	"sigs.k8s.io/kustomize/kyaml/errors"
+	"sigs.k8s.io/kustomize/kyaml/kio/filters"
+	"sigs.k8s.io/kustomize/kyaml/kio/kioutil"
+	"sigs.k8s.io/kustomize/kyaml/yaml"
+)
+
+const (
+	// SchemaAPIVersion is the apiVersion of the SettersSchema resource
+	SchemaAPIVersion = "fn.kpt.dev/v1alpha1"
+
+	// SchemaKind is the kind of the resource holding the schema of the setters
+	SchemaKind = "SettersSchema"
+
+	// SchemaName is the name of the SettersSchema resource
+	SchemaName = "setters-schema"
+
+	// FieldsExtension is the schema extension listing the fields parameterized by a setter
+	FieldsExtension = "x-kpt-fields"
+)
+
+// Schema returns the JSON Schema, which is also a valid OpenAPI v3 schema, of
+// an object with a property for each setter. Each property declares the type
+// inferred for the setter, the current value of the setter as the default value
+// and the fields parameterized by the setter using the x-kpt-fields extension.
+//
+// e.g. for the setter replicas used in a Deployment
+//
+//	type: object
+//	properties:
+//	  replicas:
+//	    type: integer
+//	    default: 3
+//	    x-kpt-fields:
+//	      - file: deployment.yaml
+//	        path: spec.replicas
+func (ls *ListSetters) Schema() map[string]interface{} {
+	properties := make(map[string]interface{})
+	for name, s := range ls.ScalarSetters {
+		schemaType := schemaType(s.Type)
+		p := map[string]interface{}{"type": schemaType}
+		if v, ok := typedValue(s.Value, schemaType); ok {
+			p["default"] = v
+		}
+		properties[name] = withFields(p, s.Fields)
+	}
+	for name, s := range ls.ArraySetters {
+		values := make([]interface{}, len(s.Values))
+		for i := range s.Values {
+			values[i] = s.Values[i]
+		}
+		properties[name] = withFields(map[string]interface{}{
+			"type":    "array",
+			"items":   map[string]interface{}{"type": "string"},
+			"default": values,
+		}, s.Fields)
+	}
+	for name, s := range ls.MapSetters {
+		p := map[string]interface{}{"type": "object"}
+		var value map[string]interface{}
+		if err := yaml.Unmarshal([]byte(s.Value), &value); err == nil && value != nil {
+			p["default"] = value
+		}
+		properties[name] = withFields(p, s.Fields)
+	}
+	return map[string]interface{}{
+		"type":       "object",
+		"properties": properties,
+	}
+}
+
+// SchemaResource returns the local config SettersSchema resource in the input
+// file path with the Schema of the setters
+func (ls *ListSetters) SchemaResource(path string) (*yaml.RNode, error) {
+	rn, err := yaml.FromMap(map[string]interface{}{
+		"apiVersion": SchemaAPIVersion,
+		"kind":       SchemaKind,
+		"metadata": map[string]interface{}{
+			"name": SchemaName,
+			"annotations": map[string]interface{}{
+				filters.LocalConfigAnnotation: "true",
+				kioutil.PathAnnotation:        path,
+			},
+		},
+		"schema": ls.Schema(),
+	})
+	if err != nil {
+		return nil, errors.WrapPrefixf(err, "failed to create %s", SchemaKind)
+	}
+	return rn, nil
+}
+
+// AddSchemaResource adds the SettersSchema resource in the input file path to
+// the nodes, the resource is replaced if it already exists in the file path
+func (ls *ListSetters) AddSchemaResource(nodes []*yaml.RNode, path string) ([]*yaml.RNode, error) {
+	rn, err := ls.SchemaResource(path)
+	if err != nil {
+		return nodes, err
+	}
+	for i := range nodes {
+		if nodes[i].GetKind() == SchemaKind && nodes[i].GetAnnotations()[kioutil.PathAnnotation] == path {
+			nodes[i] = rn
+			return nodes, nil
+		}
+	}
+	return append(nodes, rn), nil
+}
+
+// schemaType returns the JSON Schema type for the yaml tag of the scalar setter value
+func schemaType(valueType string) string {
+	switch valueType {
+	case "int":
+		return "integer"
+	case "float":
+		return "number"
+	case "bool":
+		return "boolean"
+	}
+	return "string"
+}
+
+// typedValue parses the value as the input JSON Schema type
+func typedValue(value, schemaType string) (interface{}, bool) {
+	switch schemaType {
+	case "integer":
+		v, err := strconv.ParseInt(value, 10, 64)
+		return v, err == nil
+	case "number":
+		v, err := strconv.ParseFloat(value, 64)
+		return v, err == nil
+	case "boolean":
+		v, err := strconv.ParseBool(value)
+		return v, err == nil
+	}
+	return value, true
+}
+
+// withFields adds the fields parameterized by the setter to the schema property
+func withFields(property map[string]interface{}, fields []Field) map[string]interface{} {
+	if len(fields) == 0 {
+		return property
+	}
+	sorted := make([]Field, len(fields))
+	copy(sorted, fields)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		if sorted[i].FilePath != sorted[j].FilePath {
+			return sorted[i].FilePath < sorted[j].FilePath
+		}
+		return sorted[i].FieldPath < sorted[j].FieldPath
+	})
+	var items []interface{}
+	for _, f := range sorted {
+		items = append(items, map[string]interface{}{"file": f.FilePath, "path": f.FieldPath})
+	}
+	property[FieldsExtension] = items
+	return property
+}
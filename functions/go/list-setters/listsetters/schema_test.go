@@ -0,0 +1,162 @@
+package listsetters
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"sigs.k8s.io/kustomize/kyaml/kio"
+)
+
+func TestSchemaResource(t *testing.T) {
+	var tests = []struct {
+		name     string
+		input    string
+		expected string
+	}{
+		{
+			name: "setters of all types",
+			input: `apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: my-app # kpt-set: ${app}
+  annotations:
+    config.kubernetes.io/path: deployment.yaml
+spec:
+  replicas: 3 # kpt-set: ${replicas}
+  paused: false # kpt-set: ${paused}
+  template:
+    spec:
+      nodeSelector: # kpt-set: ${node-selector}
+        disktype: ssd
+      containers:
+        - name: my-app # kpt-set: ${app}
+          args: # kpt-set: ${args}
+            - --debug
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: my-app-config # kpt-set: ${app}-config
+  annotations:
+    config.kubernetes.io/path: config.yaml
+    kpt.dev/embedded-setters: "true"
+data:
+  app.yaml: |
+    ratio: 0.5 # kpt-set: ${ratio}
+`,
+			expected: `apiVersion: fn.kpt.dev/v1alpha1
+kind: SettersSchema
+metadata:
+  annotations:
+    config.kubernetes.io/local-config: "true"
+    config.kubernetes.io/path: setters-schema.yaml
+  name: setters-schema
+schema:
+  properties:
+    app:
+      default: my-app
+      type: string
+      x-kpt-fields:
+      - file: config.yaml
+        path: metadata.name
+      - file: deployment.yaml
+        path: metadata.name
+      - file: deployment.yaml
+        path: spec.template.spec.containers[0].name
+    args:
+      default:
+      - --debug
+      items:
+        type: string
+      type: array
+      x-kpt-fields:
+      - file: deployment.yaml
+        path: spec.template.spec.containers[0].args
+    node-selector:
+      default:
+        disktype: ssd
+      type: object
+      x-kpt-fields:
+      - file: deployment.yaml
+        path: spec.template.spec.nodeSelector
+    paused:
+      default: false
+      type: boolean
+      x-kpt-fields:
+      - file: deployment.yaml
+        path: spec.paused
+    ratio:
+      default: 0.5
+      type: number
+      x-kpt-fields:
+      - file: config.yaml
+        path: data.app.yaml:ratio
+    replicas:
+      default: 3
+      type: integer
+      x-kpt-fields:
+      - file: deployment.yaml
+        path: spec.replicas
+  type: object
+`,
+		},
+		{
+			name: "no setters",
+			input: `apiVersion: v1
+kind: Service
+metadata:
+  name: my-app
+`,
+			expected: `apiVersion: fn.kpt.dev/v1alpha1
+kind: SettersSchema
+metadata:
+  annotations:
+    config.kubernetes.io/local-config: "true"
+    config.kubernetes.io/path: setters-schema.yaml
+  name: setters-schema
+schema:
+  properties: {}
+  type: object
+`,
+		},
+	}
+	for i := range tests {
+		test := tests[i]
+		t.Run(test.name, func(t *testing.T) {
+			require := require.New(t)
+			nodes, err := (&kio.ByteReader{
+				Reader:                strings.NewReader(test.input),
+				OmitReaderAnnotations: true,
+			}).Read()
+			require.NoError(err)
+			ls := New()
+			_, err = ls.Filter(nodes)
+			require.NoError(err)
+
+			out, err := ls.AddSchemaResource(nodes, "setters-schema.yaml")
+			require.NoError(err)
+			require.Len(out, len(nodes)+1)
+			require.Equal(test.expected, out[len(nodes)].MustString())
+
+			// the schema resource is replaced if it already exists
+			out, err = ls.AddSchemaResource(out, "setters-schema.yaml")
+			require.NoError(err)
+			require.Len(out, len(nodes)+1)
+		})
+	}
+}
+
+func TestSchemaType(t *testing.T) {
+	for valueType, expected := range map[string]string{
+		"int":   "integer",
+		"float": "number",
+		"bool":  "boolean",
+		"str":   "string",
+		"null":  "string",
+	} {
+		require.Equal(t, expected, schemaType(valueType))
+	}
+	_, ok := typedValue("abc", "integer")
+	require.False(t, ok)
+}
@@ -40,6 +40,10 @@ func (lsp *ListSettersProcessor) Process(resourceList *framework.ResourceList) e
 	return nil
 }
 
+// SchemaPathKey is the functionConfig key for the file path to export the
+// schema of the setters to
+const SchemaPathKey = "schema-path"
+
 func run(resourceList *framework.ResourceList) ([]framework.ResultItem, error) {
 	ls := listsetters.New()
 	_, err := ls.Filter(resourceList.Items)
@@ -50,6 +54,20 @@ func run(resourceList *framework.ResourceList) ([]framework.ResultItem, error) {
 	if err != nil {
 		return nil, err
 	}
+	if resourceList.FunctionConfig == nil {
+		return resultItems, nil
+	}
+	if schemaPath := resourceList.FunctionConfig.GetDataMap()[SchemaPathKey]; schemaPath != "" {
+		resourceList.Items, err = ls.AddSchemaResource(resourceList.Items, schemaPath)
+		if err != nil {
+			return nil, err
+		}
+		resultItems = append(resultItems, framework.ResultItem{
+			Message:  fmt.Sprintf("Exported the schema of the setters to %s", schemaPath),
+			Severity: framework.Info,
+			File:     framework.File{Path: schemaPath},
+		})
+	}
 	return resultItems, nil
 }
 
@@ -66,6 +84,21 @@ func resultsToItems(sr listsetters.ListSetters) ([]framework.ResultItem, error)
 			Message: r.String(),
 		})
 	}
+	for _, c := range sr.Conflicts() {
+		item := framework.ResultItem{
+			Message:  c.String(),
+			Severity: framework.Warning,
+		}
+		// point to the first field which differs from the listed value
+		for _, f := range c.Fields {
+			if f.Value != c.Value {
+				item.Field = framework.Field{Path: f.FieldPath}
+				item.File = framework.File{Path: f.FilePath}
+				break
+			}
+		}
+		items = append(items, item)
+	}
 	return items, nil
 }
 
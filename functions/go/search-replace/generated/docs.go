@@ -37,12 +37,23 @@ Matchers:
   Match by file path expression. Input must be OS-agnostic Slash(/) separated file path
   relative to the directory on which the function is invoked. Please note that the
   file path expressions are not regular expressions.
+  
+  by-kind, by-group, by-name, by-namespace
+  Match the fields of the resources with the kind, API group, name or namespace.
+  The resources are selected before their fields are matched, so the mutators
+  never touch the other resources which happen to have matching fields.
+  
+  by-labels, by-annotations
+  Match the fields of the resources with all the labels or annotations, which are
+  provided as comma separated key=value pairs e.g. app=nginx,tier=web.
 
 Mutators:
 
   put-value
   Set or update the value of the matching fields. Input can be a pattern for which
-  the numbered capture groups are resolved using --by-value-regex input.
+  the numbered and named capture groups are resolved using --by-value-regex input,
+  e.g. $1, ${1} or ${name}. The results report both the old and the new values of
+  the mutated fields.
   
   put-comment
   Set or update the line comment for matching fields. Input can be a pattern for
@@ -68,6 +79,33 @@ Alternatively, data can be passed as key-value pairs in the CLI
 
   $ kpt fn eval --image gcr.io/kpt-fn/search-replace:unstable -- 'by-path=metadata.name' 'put-value=the-deployment'
 
+Multiple rules:
+
+The typed ` + "`" + `SearchReplace` + "`" + ` functionConfig holds an ordered list of ` + "`" + `rules` + "`" + `, so
+that multiple search and replace operations are performed in a single
+invocation. Each rule has its own matchers and mutators, which have the same
+semantics as the ConfigMap inputs, and an optional ` + "`" + `selector` + "`" + ` which selects the
+resources by ` + "`" + `group` + "`" + `, ` + "`" + `version` + "`" + `, ` + "`" + `kind` + "`" + `, ` + "`" + `name` + "`" + `, ` + "`" + `namespace` + "`" + `, ` + "`" + `labels` + "`" + ` and
+` + "`" + `annotations` + "`" + `. The rules are performed in order on each resource, so a rule
+sees the mutations of the preceding rules. The results are tagged with the
+` + "`" + `name` + "`" + ` of the rule, which defaults to ` + "`" + `rule-<n>` + "`" + ` for the nth rule. A rule or
+selector with an unknown field is rejected, e.g. the matchers of a rule are
+spelled ` + "`" + `byPath` + "`" + ` rather than ` + "`" + `by-path` + "`" + `.
+
+  apiVersion: fn.kpt.dev/v1alpha1
+  kind: SearchReplace
+  metadata:
+    name: search-replace-fn-config
+  rules:
+    - name: registry
+      byValueRegex: gcr.io/(.*)
+      putValue: us-docker.pkg.dev/my-project/$1
+      selector:
+        group: apps
+    - name: namespace
+      byPath: metadata.namespace
+      putValue: my-namespace
+
 ### Field path patterns
 
 ` + "`" + `by-path` + "`" + ` matcher supports the following patterns:
@@ -76,6 +114,8 @@ Alternatively, data can be passed as key-value pairs in the CLI
 | ------------- | --------------------------- |
 | ` + "`" + `*` + "`" + `           | matches exactly one field   |
 | ` + "`" + `**` + "`" + `          | matches zero or more fields |
+| ` + "`" + `[k=v]` + "`" + `       | matches the elements of a list of objects with the field ` + "`" + `k` + "`" + ` set to ` + "`" + `v` + "`" + ` |
+| ` + "`" + `[=v]` + "`" + `        | matches the elements of a list of scalars set to ` + "`" + `v` + "`" + ` |
 
   a.b.c
   
@@ -132,6 +172,27 @@ Alternatively, data can be passed as key-value pairs in the CLI
     - c: thing2 # MATCHES
       f: thingamabob
 
+  a.b[name=foo].c
+  
+  a:
+    b:
+    - name: bar
+      c: thing0
+    - name: foo
+      c: thing1 # MATCHES
+
+  a.b[=foo]
+  
+  a:
+    b:
+    - bar
+    - foo # MATCHES
+
+Predicates can be combined with the other patterns, e.g.
+` + "`" + `spec.**.containers[name=nginx].env[name=LOG_LEVEL].value` + "`" + `. When ` + "`" + `put-value` + "`" + ` is
+used with a field path without wildcards, the list element matching each
+predicate is added if it does not exist.
+
 ### File path patterns
 
 ` + "`" + `by-file-path` + "`" + ` matcher supports the following special terms in the patterns:
@@ -206,6 +267,12 @@ var SearchReplaceExamples = `
   # Set namespaces for all resources to "bookstore", even namespace is not set on a resource:
   $ kpt fn eval --image gcr.io/kpt-fn/search-replace:unstable -- by-path='metadata.namespace' put-value='bookstore'
 
+  # Set the replicas of the Deployments only, leaving the custom resources with the same field untouched:
+  $ kpt fn eval --image gcr.io/kpt-fn/search-replace:unstable -- by-kind=Deployment by-group=apps by-path='spec.replicas' put-value=3
+
+  # Set the image of the container named "nginx" in all workloads:
+  $ kpt fn eval --image gcr.io/kpt-fn/search-replace:unstable -- by-path='spec.**.containers[name=nginx].image' put-value='nginx:1.8.0'
+
   # Update the setter value "project-id" to value "new-project" in all "setters.yaml" files in the current directory tree:
   kpt fn eval --image gcr.io/kpt-fn/search-replace:unstable --include-meta-resources -- \
   by-value=project-id by-file-path='**/setters.yaml' put-value=new-project
@@ -220,6 +287,15 @@ var SearchReplaceExamples = `
     name: my-project-id-foo
     namespace: my-project-id-bar
 
+  # Move all images, annotations and env vars referencing gcr.io to Artifact Registry
+  $ kpt fn eval --image gcr.io/kpt-fn/search-replace:unstable -- by-value-regex='gcr.io/(.*)' put-value='us-docker.pkg.dev/my-project/$1'
+  image: gcr.io/nginx:1.7.1
+  ...
+  image: us-docker.pkg.dev/my-project/nginx:1.7.1
+
+  # Named capture groups can be referenced by name
+  $ kpt fn eval --image gcr.io/kpt-fn/search-replace:unstable -- by-value-regex='(?P<name>.*):1.7.1' put-value='${name}:1.8.0'
+
 Create setters examples:
 
   # Put the setter pattern as a line comment for matching fields.
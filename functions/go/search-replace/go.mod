@@ -3,6 +3,7 @@ module github.com/GoogleContainerTools/kpt-functions-catalog/functions/go/search
 go 1.19
 
 require (
+	github.com/GoogleContainerTools/kpt-functions-catalog/thirdparty/kyaml/selector v0.0.0-00010101000000-000000000000
 	github.com/bmatcuk/doublestar/v4 v4.0.2
 	github.com/stretchr/testify v1.6.1
 	sigs.k8s.io/kustomize/kyaml v0.10.21
@@ -29,3 +30,5 @@ require (
 	gopkg.in/yaml.v3 v3.0.0-20210107192922-496545a6307b // indirect
 	k8s.io/kube-openapi v0.0.0-20210421082810-95288971da7e // indirect
 )
+
+replace github.com/GoogleContainerTools/kpt-functions-catalog/thirdparty/kyaml/selector => ../../../thirdparty/kyaml/selector
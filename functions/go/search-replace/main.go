@@ -43,45 +43,56 @@ func (srp *SearchReplaceProcessor) Process(resourceList *framework.ResourceList)
 
 // run resolves the function params from input ResourceList and runs the function on resources
 func run(resourceList *framework.ResourceList) ([]framework.ResultItem, error) {
-	sr, err := getSearchReplaceParams(resourceList.FunctionConfig)
+	rules, err := getSearchReplaceParams(resourceList.FunctionConfig)
 	if err != nil {
 		return nil, err
 	}
 
-	_, err = sr.Filter(resourceList.Items)
+	_, err = rules.Filter(resourceList.Items)
 	if err != nil {
 		return nil, err
 	}
 
-	return searchResultsToItems(sr), nil
+	return searchResultsToItems(rules), nil
 }
 
-// getSearchReplaceParams retrieve the search parameters from input config
-func getSearchReplaceParams(fc *kyaml.RNode) (searchreplace.SearchReplace, error) {
-	var fcd searchreplace.SearchReplace
-	if err := searchreplace.Decode(fc, &fcd); err != nil {
-		return fcd, err
-	}
-	return fcd, nil
+// getSearchReplaceParams retrieve the search rules from input config
+func getSearchReplaceParams(fc *kyaml.RNode) (searchreplace.Rules, error) {
+	return searchreplace.DecodeRules(fc)
 }
 
 // searchResultsToItems converts the Search and Replace results to
-// equivalent items([]framework.Item)
-func searchResultsToItems(sr searchreplace.SearchReplace) []framework.ResultItem {
+// equivalent items([]framework.Item), the messages are prefixed with the
+// names of the rules in the typed functionConfig
+func searchResultsToItems(rules searchreplace.Rules) []framework.ResultItem {
 	var items []framework.ResultItem
-	if len(sr.Results) == 0 {
+	if len(rules.Results()) == 0 {
 		items = append(items, framework.ResultItem{
 			Message: "no matches",
 		})
 		return items
 	}
+	for _, sr := range rules {
+		items = append(items, ruleResultsToItems(sr)...)
+	}
+	return items
+}
+
+// ruleResultsToItems converts the results of a single search and replace rule
+func ruleResultsToItems(sr *searchreplace.SearchReplace) []framework.ResultItem {
+	var items []framework.ResultItem
 	for _, res := range sr.Results {
 		var message string
-		if sr.PutComment != "" || sr.PutValue != "" {
+		if res.OldValue != "" && res.OldValue != res.Value {
+			message = fmt.Sprintf("Mutated field value from %q to %q", res.OldValue, res.Value)
+		} else if sr.PutComment != "" || sr.PutValue != "" {
 			message = fmt.Sprintf("Mutated field value to %q", res.Value)
 		} else {
 			message = fmt.Sprintf("Matched field value %q", res.Value)
 		}
+		if res.Rule != "" {
+			message = fmt.Sprintf("[%s] %s", res.Rule, message)
+		}
 
 		items = append(items, framework.ResultItem{
 			Message: message,
@@ -1,7 +1,11 @@
 package searchreplace
 
 import (
+	"fmt"
 	"strings"
+
+	"github.com/GoogleContainerTools/kpt-functions-catalog/thirdparty/kyaml/selector"
+	"sigs.k8s.io/kustomize/kyaml/yaml"
 )
 
 // pathMatch checks if the traversed yaml path matches with the user input path
@@ -12,93 +16,147 @@ func (sr *SearchReplace) pathMatch(yamlPath string) bool {
 	}
 
 	// split elements of input by-path
-	patternElems := strings.Split(sr.ByPath, PathDelimiter)
+	patternElems := splitPath(sr.ByPath)
 
 	// split elements of traversed yamlPath
-	yamlPathElems := strings.Split(strings.TrimPrefix(yamlPath, PathDelimiter), PathDelimiter)
+	yamlPathElems := splitPath(strings.TrimPrefix(yamlPath, PathDelimiter))
 
 	// match input by-path with traversed path
-	return backTrackMatch(yamlPathElems, patternElems)
+	return selector.BackTrackMatch(yamlPathElems, patternElems, func(i int, pattern string) bool {
+		return sr.predicateMatch(yamlPathElems, i, pattern)
+	})
 }
 
-// backTrackMatch matches the traversed yamlPathElems with input(from by-path) patternElems
-// * matches any element, ** matches 0 or more elements, array elements are split and matched
-// refer to pathparser_test.go
-func backTrackMatch(yamlPathElems, patternElems []string) bool {
-	// this is a dynamic programming problem
-	// aim is to check if path array matches pattern array as per above rules
-	yamlPathElemsLen, patternElemsLen := len(yamlPathElems), len(patternElems)
-
-	// initialize a 2d boolean matrix to memorize results
-	// dp[i][j] stores the result, if yamlPath subarray of length i matches
-	// pattern subarray of length j
-	dp := make([][]bool, yamlPathElemsLen+1)
-	for i := range dp {
-		dp[i] = make([]bool, patternElemsLen+1)
-	}
-	dp[0][0] = true
+// splitPath splits the path into elements separated by PathDelimiter, the
+// delimiters within the predicates are not split e.g. a[name=b.c].d is split
+// into [a[name=b.c], d]
+func splitPath(path string) []string {
+	var elems []string
+	depth, start := 0, 0
+	for i, c := range path {
+		switch {
+		case c == '[':
+			depth++
+		case c == ']' && depth > 0:
+			depth--
+		case string(c) == PathDelimiter && depth == 0:
+			elems = append(elems, path[start:i])
+			start = i + 1
+		}
+	}
+	return append(elems, path[start:])
+}
 
-	// edge case 1: when pattern is empty, yamlPath of length grater than 0 doesn't match
-	for i := 1; i < yamlPathElemsLen+1; i++ {
-		dp[i][0] = false
+// parsePredicate parses the predicate of a path element e.g. containers[name=nginx]
+// returns containers, name and nginx. The field is empty for the predicates
+// matching the scalar elements e.g. args[=--debug]
+func parsePredicate(pattern string) (name, field, value string, ok bool) {
+	i := strings.Index(pattern, "[")
+	if i < 0 || !strings.HasSuffix(pattern, "]") {
+		return "", "", "", false
+	}
+	predicate := pattern[i+1 : len(pattern)-1]
+	j := strings.Index(predicate, "=")
+	if j < 0 {
+		return "", "", "", false
 	}
+	return pattern[:i], predicate[:j], predicate[j+1:], true
+}
 
-	// edge case 2: if yamlPath is empty, carry forward the previous result if the pattern element
-	// is `**` as it matches 0 or more elements.
-	for j := 1; j < patternElemsLen+1; j++ {
-		if patternElems[j-1] == "**" {
-			dp[0][j] = dp[0][j-1]
+// predicateMatch matches the element at index i of the traversed yamlPathElems
+// with the pattern element, the predicates are matched against the sequence
+// element at the traversed path e.g. containers[0] matches containers[name=nginx]
+// if the name of the first container is nginx
+func (sr *SearchReplace) predicateMatch(yamlPathElems []string, i int, pattern string) bool {
+	name, field, value, ok := parsePredicate(pattern)
+	if !ok {
+		return selector.ElementMatch(yamlPathElems[i], pattern)
+	}
+	elem := yamlPathElems[i]
+	j := strings.Index(elem, "[")
+	if j < 0 || (name != "*" && name != elem[:j]) {
+		return false
+	}
+	node, found := sr.elements[strings.Join(yamlPathElems[:i+1], PathDelimiter)]
+	if !found {
+		return false
+	}
+	if field == "" {
+		return node.Kind == yaml.ScalarNode && node.Value == value
+	}
+	if node.Kind != yaml.MappingNode {
+		return false
+	}
+	for k := 0; k+1 < len(node.Content); k += 2 {
+		if node.Content[k].Value == field {
+			return node.Content[k+1].Kind == yaml.ScalarNode && node.Content[k+1].Value == value
 		}
 	}
+	return false
+}
 
-	// fill rest of the matrix
-	for i := 1; i < yamlPathElemsLen+1; i++ {
-		for j := 1; j < patternElemsLen+1; j++ {
-			if patternElems[j-1] == "**" {
-				// `**` matches multiple elements, so carry forward the result from immediate
-				// neighbors, dp[i-1][j] match empty, dp[i][j-1] match multiple elements
-				dp[i][j] = dp[i][j-1] || dp[i-1][j]
-			} else if patternElems[j-1] == "*" || elementMatch(yamlPathElems[i-1], patternElems[j-1]) {
-				// if there is element match or `*` then get the result from previous diagonal element
-				dp[i][j] = dp[i-1][j-1]
-			}
+// sequenceElements returns the sequence elements in the node by their
+// traversed paths e.g. spec.containers[0], to match the path predicates
+func sequenceElements(node *yaml.Node, path string, elements map[string]*yaml.Node) {
+	switch node.Kind {
+	case yaml.DocumentNode:
+		for _, c := range node.Content {
+			sequenceElements(c, path, elements)
+		}
+	case yaml.MappingNode:
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			sequenceElements(node.Content[i+1], path+PathDelimiter+node.Content[i].Value, elements)
+		}
+	case yaml.SequenceNode:
+		for i, c := range node.Content {
+			p := path + fmt.Sprintf("[%d]", i)
+			elements[strings.TrimPrefix(p, PathDelimiter)] = c
+			sequenceElements(c, p, elements)
 		}
 	}
+}
 
-	/*Example matrix for yamlPath = [a,a,b,c,e,b] and pattern [a,*,b,**,b]
-		  a	a	b	c	e	b
-		a	T	F	F	F	F	F
-	  * F	T	F	F	F	F
-		b	F	F	T	F	F	F
-	 ** F	F	T	T	T	T
-		b	F	F	F	F	F	T
-	*/
+// hasPredicates returns true if any of the elements in the path has a predicate
+func hasPredicates(path string) bool {
+	for _, elem := range splitPath(path) {
+		if _, _, _, ok := parsePredicate(elem); ok {
+			return true
+		}
+	}
+	return false
+}
 
-	return dp[yamlPathElemsLen][patternElemsLen]
+// hasIndices returns true if any of the elements in the path is a sequence
+// element addressed by index, or if the last element is a sequence element
+func hasIndices(path string) bool {
+	elems := splitPath(path)
+	for _, elem := range elems {
+		if _, _, _, ok := parsePredicate(elem); !ok && strings.Contains(elem, "[") {
+			return true
+		}
+	}
+	return strings.Contains(elems[len(elems)-1], "[")
 }
 
-// elementMatch matches single element with pattern for single element
-func elementMatch(elem, pattern string) bool {
-	// scalar field case `metadata` matches `metadata`
-	if elem == pattern {
-		return true
-	}
-	// array element e.g. a[*], *[*] and *[b] matches a[b]
-	if strings.Contains(elem, "[") {
-		elemParts := strings.Split(elem, "[")
-		patternParts := strings.Split(pattern, "[")
-		if patternParts[0] != "*" && elemParts[0] != patternParts[0] {
-			return false
+// kyamlPath converts the path to the kyaml path elements in which the predicates
+// are separate elements e.g. containers[name=nginx].image is converted to
+// [containers, [name=nginx], image]
+func kyamlPath(path string) []string {
+	var elems []string
+	for _, elem := range splitPath(path) {
+		if i := strings.Index(elem, "["); i > 0 {
+			elems = append(elems, elem[:i], elem[i:])
+			continue
 		}
-		return len(patternParts) > 1 && (patternParts[1] == "*]" || elemParts[1] == patternParts[1])
+		elems = append(elems, elem)
 	}
-	return false
+	return elems
 }
 
 // isAbsPath checks if input path is absolute and not a path expression
-// only supported path format is e.g. foo.bar.baz
+// only supported path format is e.g. foo.bar.baz or foo.bar[name=baz].qux
 func isAbsPath(path string) bool {
-	pathElem := strings.Split(path, PathDelimiter)
+	pathElem := splitPath(path)
 	if len(pathElem) == 0 {
 		return false
 	}
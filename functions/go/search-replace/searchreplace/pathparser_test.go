@@ -84,3 +84,14 @@ func TestPathMatch(t *testing.T) {
 		})
 	}
 }
+
+func TestSplitPath(t *testing.T) {
+	assert.Equal(t, []string{"a", "b[name=c.d]", "e"}, splitPath("a.b[name=c.d].e"))
+	assert.Equal(t, []string{"a", "b[0]"}, splitPath("a.b[0]"))
+	assert.Equal(t, []string{"a", "b", "[name=c]", "e"}, kyamlPath("a.b[name=c].e"))
+	assert.True(t, hasPredicates("a.b[name=c].e"))
+	assert.False(t, hasPredicates("a.b[*].e"))
+	assert.True(t, hasIndices("a.b[0].e"))
+	assert.True(t, hasIndices("a.b[name=c]"))
+	assert.False(t, hasIndices("a.b[name=c].e"))
+}
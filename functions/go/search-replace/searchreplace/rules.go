@@ -0,0 +1,159 @@
+package searchreplace
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+
+	"sigs.k8s.io/kustomize/kyaml/errors"
+	"sigs.k8s.io/kustomize/kyaml/yaml"
+)
+
+const (
+	// FnConfigAPIVersion is the apiVersion of the typed functionConfig
+	FnConfigAPIVersion = "fn.kpt.dev/v1alpha1"
+
+	// FnConfigKind is the kind of the typed functionConfig
+	FnConfigKind = "SearchReplace"
+)
+
+// Rule is a search and replace operation in the typed SearchReplace functionConfig,
+// the matchers and mutators have the same semantics as the ConfigMap inputs
+type Rule struct {
+	// Name identifies the results of the rule, defaults to rule-<n> for the nth rule
+	Name string `json:"name,omitempty" yaml:"name,omitempty"`
+
+	ByValue      string `json:"byValue,omitempty" yaml:"byValue,omitempty"`
+	ByValueRegex string `json:"byValueRegex,omitempty" yaml:"byValueRegex,omitempty"`
+	ByPath       string `json:"byPath,omitempty" yaml:"byPath,omitempty"`
+	ByFilePath   string `json:"byFilePath,omitempty" yaml:"byFilePath,omitempty"`
+
+	// Selector selects the resources on which the rule is performed
+	Selector *Selector `json:"selector,omitempty" yaml:"selector,omitempty"`
+
+	PutValue   string `json:"putValue,omitempty" yaml:"putValue,omitempty"`
+	PutComment string `json:"putComment,omitempty" yaml:"putComment,omitempty"`
+}
+
+// fnConfig holds the fields of the typed SearchReplace functionConfig
+type fnConfig struct {
+	Rules []Rule `yaml:"rules"`
+}
+
+// Rules are the search and replace operations which are performed in order
+// on each resource in a single pass over the resources
+type Rules []*SearchReplace
+
+// Filter performs all the search and replace operations on the input nodes,
+// the rules are performed in order on each node so that a rule sees the
+// mutations of the preceding rules
+func (rules Rules) Filter(nodes []*yaml.RNode) ([]*yaml.RNode, error) {
+	for _, sr := range rules {
+		if err := sr.init(); err != nil {
+			return nodes, sr.wrapErr(err)
+		}
+	}
+	for _, object := range nodes {
+		for _, sr := range rules {
+			if _, err := sr.Perform(object); err != nil {
+				return nodes, sr.wrapErr(err)
+			}
+		}
+	}
+	return nodes, nil
+}
+
+// Results returns the results of all the rules in order
+func (rules Rules) Results() []SearchResult {
+	var out []SearchResult
+	for _, sr := range rules {
+		out = append(out, sr.Results...)
+	}
+	return out
+}
+
+// DecodeRules decodes the input functionConfig into Rules, the ConfigMap
+// functionConfig is decoded into a single unnamed rule
+func DecodeRules(rn *yaml.RNode) (Rules, error) {
+	if rn.GetKind() != FnConfigKind {
+		sr := &SearchReplace{}
+		if err := Decode(rn, sr); err != nil {
+			return nil, err
+		}
+		return Rules{sr}, nil
+	}
+	var fc fnConfig
+	if err := yaml.Unmarshal([]byte(rn.MustString()), &fc); err != nil {
+		return nil, errors.WrapPrefixf(err, "failed to decode %s", FnConfigKind)
+	}
+	if len(fc.Rules) == 0 {
+		return nil, errors.Errorf("at least one rule must be provided in %s", FnConfigKind)
+	}
+	ruleNodes, err := rn.Pipe(yaml.Lookup("rules"))
+	if err != nil {
+		return nil, errors.Wrap(err)
+	}
+	ruleElements, err := ruleNodes.Elements()
+	if err != nil {
+		return nil, errors.Wrap(err)
+	}
+	var rules Rules
+	names := make(map[string]bool)
+	for i, r := range fc.Rules {
+		name := r.Name
+		if name == "" {
+			name = fmt.Sprintf("rule-%d", i+1)
+		}
+		if names[name] {
+			return nil, errors.Errorf("duplicate rule name %q in %s", name, FnConfigKind)
+		}
+		if err := checkRuleFields(ruleElements[i]); err != nil {
+			return nil, errors.WrapPrefixf(err, "rule %q", name)
+		}
+		names[name] = true
+		rules = append(rules, &SearchReplace{
+			Name:         name,
+			ByValue:      r.ByValue,
+			ByValueRegex: r.ByValueRegex,
+			ByPath:       r.ByPath,
+			ByFilePath:   r.ByFilePath,
+			Selector:     r.Selector,
+			PutValue:     r.PutValue,
+			PutComment:   r.PutComment,
+		})
+	}
+	return rules, nil
+}
+
+// checkRuleFields returns an error for the first unknown field of the rule or
+// its selector, so that a misspelled matcher doesn't silently match everything
+func checkRuleFields(rule *yaml.RNode) error {
+	if err := checkFields(rule, Rule{}); err != nil {
+		return err
+	}
+	selector := rule.Field("selector")
+	if selector == nil || selector.Value.YNode().Kind != yaml.MappingNode {
+		return nil
+	}
+	return errors.WrapPrefixf(checkFields(selector.Value, Selector{}), "selector")
+}
+
+// checkFields returns an error if the mapping node has a field which is not
+// one of the yaml fields of the input struct
+func checkFields(node *yaml.RNode, v interface{}) error {
+	known := make(map[string]bool)
+	t := reflect.TypeOf(v)
+	for i := 0; i < t.NumField(); i++ {
+		known[strings.Split(t.Field(i).Tag.Get("yaml"), ",")[0]] = true
+	}
+	fields, err := node.Fields()
+	if err != nil {
+		return errors.Wrap(err)
+	}
+	for _, f := range fields {
+		if !known[f] {
+			return errors.Errorf("unknown field %q", f)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,193 @@
+package searchreplace
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"sigs.k8s.io/kustomize/kyaml/kio"
+	kyaml "sigs.k8s.io/kustomize/kyaml/yaml"
+)
+
+func TestRules(t *testing.T) {
+	input := `apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: nginx
+  annotations:
+    config.kubernetes.io/path: deployment.yaml
+spec:
+  template:
+    spec:
+      containers:
+        - name: nginx
+          image: gcr.io/nginx:1.7.1
+---
+apiVersion: example.com/v1
+kind: MyResource
+metadata:
+  name: nginx
+  annotations:
+    config.kubernetes.io/path: my-resource.yaml
+spec:
+  image: gcr.io/nginx:1.7.1
+`
+	var tests = []struct {
+		name              string
+		config            string
+		expectedResources string
+		expectedResults   []SearchResult
+		errMsg            string
+	}{
+		{
+			name: "rules are performed in order",
+			config: `apiVersion: fn.kpt.dev/v1alpha1
+kind: SearchReplace
+metadata:
+  name: search-replace-fn-config
+rules:
+  - name: registry
+    byValueRegex: gcr.io/(.*)
+    putValue: us-docker.pkg.dev/my-project/$1
+    selector:
+      kind: Deployment
+  - byValue: us-docker.pkg.dev/my-project/nginx:1.7.1
+    putComment: 'kpt-set: ${image}'
+`,
+			expectedResources: `apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: nginx
+  annotations:
+    config.kubernetes.io/path: deployment.yaml
+spec:
+  template:
+    spec:
+      containers:
+        - name: nginx
+          image: us-docker.pkg.dev/my-project/nginx:1.7.1 # kpt-set: ${image}
+---
+apiVersion: example.com/v1
+kind: MyResource
+metadata:
+  name: nginx
+  annotations:
+    config.kubernetes.io/path: my-resource.yaml
+spec:
+  image: gcr.io/nginx:1.7.1
+`,
+			expectedResults: []SearchResult{
+				{
+					Rule:      "registry",
+					FilePath:  "deployment.yaml",
+					FieldPath: "spec.template.spec.containers[0].image",
+					Value:     "us-docker.pkg.dev/my-project/nginx:1.7.1",
+					OldValue:  "gcr.io/nginx:1.7.1",
+				},
+				{
+					Rule:      "rule-2",
+					FilePath:  "deployment.yaml",
+					FieldPath: "spec.template.spec.containers[0].image",
+					Value:     "us-docker.pkg.dev/my-project/nginx:1.7.1 # kpt-set: ${image}",
+				},
+			},
+		},
+		{
+			name: "rule error",
+			config: `apiVersion: fn.kpt.dev/v1alpha1
+kind: SearchReplace
+metadata:
+  name: search-replace-fn-config
+rules:
+  - name: invalid
+    byValue: nginx
+    byValueRegex: nginx
+`,
+			errMsg: `rule "invalid": only one of ["by-value", "by-value-regex"] can be provided`,
+		},
+		{
+			name: "duplicate rule names",
+			config: `apiVersion: fn.kpt.dev/v1alpha1
+kind: SearchReplace
+metadata:
+  name: search-replace-fn-config
+rules:
+  - byValue: nginx
+  - name: rule-1
+    byValue: nginx
+`,
+			errMsg: `duplicate rule name "rule-1" in SearchReplace`,
+		},
+		{
+			name: "unknown rule field",
+			config: `apiVersion: fn.kpt.dev/v1alpha1
+kind: SearchReplace
+metadata:
+  name: search-replace-fn-config
+rules:
+  - name: registry
+    byValue: nginx
+  - by-paht: spec.**.image
+    putValue: nginx:1.8.0
+`,
+			errMsg: `rule "rule-2": unknown field "by-paht"`,
+		},
+		{
+			name: "unknown selector field",
+			config: `apiVersion: fn.kpt.dev/v1alpha1
+kind: SearchReplace
+metadata:
+  name: search-replace-fn-config
+rules:
+  - name: registry
+    byValue: gcr.io/nginx:1.7.1
+    putValue: nginx:1.8.0
+    selector:
+      knd: Deployment
+`,
+			errMsg: `rule "registry": selector: unknown field "knd"`,
+		},
+		{
+			name: "no rules",
+			config: `apiVersion: fn.kpt.dev/v1alpha1
+kind: SearchReplace
+metadata:
+  name: search-replace-fn-config
+`,
+			errMsg: "at least one rule must be provided in SearchReplace",
+		},
+	}
+	for i := range tests {
+		test := tests[i]
+		t.Run(test.name, func(t *testing.T) {
+			nodes, err := (&kio.ByteReader{
+				Reader:                strings.NewReader(input),
+				OmitReaderAnnotations: true,
+			}).Read()
+			if !assert.NoError(t, err) {
+				t.FailNow()
+			}
+			rules, err := DecodeRules(kyaml.MustParse(test.config))
+			if err == nil {
+				_, err = rules.Filter(nodes)
+			}
+			if test.errMsg != "" {
+				if !assert.Error(t, err) {
+					t.FailNow()
+				}
+				assert.Contains(t, err.Error(), test.errMsg)
+				return
+			}
+			if !assert.NoError(t, err) {
+				t.FailNow()
+			}
+			var out bytes.Buffer
+			if !assert.NoError(t, kio.ByteWriter{Writer: &out}.Write(nodes)) {
+				t.FailNow()
+			}
+			assert.Equal(t, test.expectedResources, out.String())
+			assert.Equal(t, test.expectedResults, rules.Results())
+		})
+	}
+}
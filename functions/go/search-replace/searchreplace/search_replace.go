@@ -3,6 +3,7 @@ package searchreplace
 import (
 	"fmt"
 	"regexp"
+	"strconv"
 	"strings"
 
 	"github.com/bmatcuk/doublestar/v4"
@@ -17,6 +18,12 @@ const (
 	ByValueRegex  = "by-value-regex"
 	ByPath        = "by-path"
 	ByFilePath    = "by-file-path"
+	ByKind        = "by-kind"
+	ByGroup       = "by-group"
+	ByName        = "by-name"
+	ByNamespace   = "by-namespace"
+	ByLabels      = "by-labels"
+	ByAnnotations = "by-annotations"
 	PutValue      = "put-value"
 	PutComment    = "put-comment"
 	PathDelimiter = "."
@@ -24,12 +31,16 @@ const (
 
 // matchers returns the list of supported matchers
 func matchers() []string {
-	return []string{ByValue, ByFilePath, ByValueRegex, ByPath, PutValue, PutComment}
+	return []string{ByValue, ByFilePath, ByValueRegex, ByPath, ByKind, ByGroup, ByName,
+		ByNamespace, ByLabels, ByAnnotations, PutValue, PutComment}
 }
 
 // SearchReplace struct holds the input parameters and results for
 // Search and Replace operations on resource configs
 type SearchReplace struct {
+	// Name is the name of the rule in the typed functionConfig
+	Name string
+
 	// ByValue is the value of the field to be matched
 	ByValue string
 
@@ -42,11 +53,15 @@ type SearchReplace struct {
 	// ByFilePath is the filepath of the resource to be matched
 	ByFilePath string
 
+	// Selector selects the resources to be matched
+	Selector *Selector
+
 	// Count is the number of matches
 	Count int
 
 	// PutValue is the value to be put at to field
-	// filtered by path and/or value
+	// filtered by path and/or value, it may reference the capture groups
+	// of ByValueRegex by number or name e.g. $1, ${1} or ${name}
 	PutValue string
 
 	// PutComment is the comment to be added at to field
@@ -60,10 +75,17 @@ type SearchReplace struct {
 
 	// filePath file path of resource
 	filePath string
+
+	// elements are the sequence elements of the resource by their paths,
+	// used to match the predicates in ByPath e.g. containers[name=nginx]
+	elements map[string]*yaml.Node
 }
 
 // SearchResult holds result of search and replace operation
 type SearchResult struct {
+	// Rule is the name of the rule which produced the result
+	Rule string
+
 	// FilePath is the file path of the matching field
 	FilePath string
 
@@ -72,23 +94,18 @@ type SearchResult struct {
 
 	// Value of the matching field
 	Value string
+
+	// OldValue is the value of the matching field before it is mutated
+	// by put-value, empty if the field did not exist or is not mutated
+	OldValue string
 }
 
 // Filter performs the search and replace operation on all input nodes
 func (sr *SearchReplace) Filter(nodes []*yaml.RNode) ([]*yaml.RNode, error) {
-	if err := sr.validateMatchers(); err != nil {
+	if err := sr.init(); err != nil {
 		return nodes, err
 	}
 
-	// compile regex once so that it can be used everywhere
-	if sr.ByValueRegex != "" {
-		re, err := regexp.Compile(sr.ByValueRegex)
-		if err != nil {
-			return nodes, errors.Wrap(err)
-		}
-		sr.regex = re
-	}
-
 	// perform search/replace on all nodes
 	for _, object := range nodes {
 		_, err := sr.Perform(object)
@@ -99,6 +116,30 @@ func (sr *SearchReplace) Filter(nodes []*yaml.RNode) ([]*yaml.RNode, error) {
 	return nodes, nil
 }
 
+// init validates the matchers and compiles the regex once so that
+// it can be used everywhere
+func (sr *SearchReplace) init() error {
+	if err := sr.validateMatchers(); err != nil {
+		return err
+	}
+	if sr.ByValueRegex != "" {
+		re, err := regexp.Compile(sr.ByValueRegex)
+		if err != nil {
+			return errors.Wrap(err)
+		}
+		sr.regex = re
+	}
+	return nil
+}
+
+// wrapErr prefixes the error with the rule name if any
+func (sr *SearchReplace) wrapErr(err error) error {
+	if sr.Name == "" {
+		return err
+	}
+	return errors.WrapPrefixf(err, "rule %q", sr.Name)
+}
+
 // Perform parses input node and performs search and replace operation on the node
 func (sr *SearchReplace) Perform(object *yaml.RNode) (*yaml.RNode, error) {
 	// get the filepath from the annotations to pass it to child methods
@@ -117,17 +158,29 @@ func (sr *SearchReplace) Perform(object *yaml.RNode) (*yaml.RNode, error) {
 		}
 	}
 
-	sr.filePath = filePath
-	if err != nil {
-		return object, err
+	if sr.Selector != nil {
+		match, err := sr.Selector.matches(object)
+		if err != nil {
+			return object, err
+		}
+		if !match {
+			return object, nil
+		}
 	}
 
+	sr.filePath = filePath
+
 	// check if value should be put by path and process it directly without needing
 	// to traverse all elements of the node
 	if sr.shouldPutValueByPath() {
 		return object, sr.putValueByPath(object)
 	}
 
+	if hasPredicates(sr.ByPath) {
+		sr.elements = make(map[string]*yaml.Node)
+		sequenceElements(object.YNode(), "", sr.elements)
+	}
+
 	// traverse the node to perform search/put operation
 	err = accept(sr, object)
 	return object, err
@@ -186,6 +239,7 @@ func (sr *SearchReplace) visitMapping(object *yaml.RNode, path string) error {
 			// change to folded style as it looks clean with comment in key node
 			node.Value.YNode().Style = yaml.FoldedStyle
 			res := SearchResult{
+				Rule:      sr.Name,
 				FilePath:  sr.filePath,
 				FieldPath: sr.ByPath + fmt.Sprintf(" # %s", sr.PutComment),
 				Value:     strings.TrimSpace(val),
@@ -232,10 +286,12 @@ func (sr *SearchReplace) matchAndReplace(node *yaml.Node, path string) error {
 	// increment the matched count
 	sr.Count++
 
+	oldValue := node.Value
+
 	// put comment if put-comment is provided as input
 	if sr.PutComment != "" {
 		var err error
-		node.LineComment, err = resolvePattern(node.Value, sr.ByValueRegex, sr.PutComment)
+		node.LineComment, err = resolvePattern(node.Value, sr.regex, sr.PutComment)
 		if err != nil {
 			return err
 		}
@@ -246,7 +302,7 @@ func (sr *SearchReplace) matchAndReplace(node *yaml.Node, path string) error {
 		// TODO: pmarupaka Check if the new value honors the openAPI schema and/or
 		// current field type, throw error if it doesn't
 		var err error
-		node.Value, err = resolvePattern(node.Value, sr.ByValueRegex, sr.PutValue)
+		node.Value, err = resolvePattern(node.Value, sr.regex, sr.PutValue)
 		if err != nil {
 			return err
 		}
@@ -262,10 +318,14 @@ func (sr *SearchReplace) matchAndReplace(node *yaml.Node, path string) error {
 			return err
 		}
 		res := SearchResult{
+			Rule:      sr.Name,
 			FilePath:  sr.filePath,
 			FieldPath: strings.TrimPrefix(path, PathDelimiter),
 			Value:     strings.TrimSpace(nodeVal),
 		}
+		if sr.PutValue != "" {
+			res.OldValue = oldValue
+		}
 		sr.Results = append(sr.Results, res)
 	}
 
@@ -297,12 +357,18 @@ func (sr *SearchReplace) searchCriteriaMatch(node *yaml.Node, path string) bool
 
 // putValueByPath puts the value in the user specified sr.ByPath
 func (sr *SearchReplace) putValueByPath(object *yaml.RNode) error {
-	path := strings.Split(sr.ByPath, PathDelimiter)
+	// the predicates are separate path elements in kyaml e.g. [name=nginx]
+	path := kyamlPath(sr.ByPath)
 	// lookup(or create) node for n-1 path elements
 	node, err := object.Pipe(yaml.LookupCreate(yaml.MappingNode, path[:len(path)-1]...))
 	if err != nil {
 		return errors.Wrap(err)
 	}
+	// record the current value of the field if it exists
+	var oldValue string
+	if field := node.Field(path[len(path)-1]); field != nil && field.Value.YNode().Kind == yaml.ScalarNode {
+		oldValue = field.Value.YNode().Value
+	}
 	// set the last path element key with the input value
 	sn := yaml.NewScalarRNode(sr.PutValue)
 	// When encoding, if this tag is unset the value type will be
@@ -313,9 +379,11 @@ func (sr *SearchReplace) putValueByPath(object *yaml.RNode) error {
 		return errors.Wrap(err)
 	}
 	res := SearchResult{
+		Rule:      sr.Name,
 		FilePath:  sr.filePath,
 		FieldPath: sr.ByPath,
 		Value:     sr.PutValue,
+		OldValue:  oldValue,
 	}
 	sr.Results = append(sr.Results, res)
 	sr.Count++
@@ -324,39 +392,51 @@ func (sr *SearchReplace) putValueByPath(object *yaml.RNode) error {
 
 // shouldPutValueByPath returns true if only absolute path and literal are provided,
 // so that the value can be directly put without needing to traverse the entire node,
-// handles the case of adding non-existent field-value to node
+// handles the case of adding non-existent field-value to node. The sequence elements
+// can only be addressed by predicates e.g. containers[name=nginx].image
 func (sr *SearchReplace) shouldPutValueByPath() bool {
 	return isAbsPath(sr.ByPath) &&
-		!strings.Contains(sr.ByPath, "[") && // TODO: pmarupaka Support appending value for arrays
+		!hasIndices(sr.ByPath) && // TODO: pmarupaka Support appending value for arrays
 		sr.ByValue == "" &&
 		sr.ByValueRegex == "" &&
 		sr.PutValue != ""
 }
 
-// resolvePattern takes the field value of a node, valueRegex provided by
-// user from by-value-regex, patternRegex provided by user from put-value/put-comment,
-// and makes best effort to derive the corresponding capture groups and resolve the pattern
+// captureGroupRef matches the references to the capture groups of by-value-regex
+// in put-value/put-comment e.g. $1, ${1} or ${name}
+var captureGroupRef = regexp.MustCompile(`\$(?:\{(\w+)\}|([0-9]+))`)
+
+// resolvePattern takes the field value of a node, the compiled valueRegex provided
+// by user from by-value-regex, pattern provided by user from put-value/put-comment,
+// and makes best effort to derive the corresponding capture groups and resolve the pattern.
+// The numbered capture groups must be resolved, the named references which are not
+// capture groups of valueRegex are left as is e.g. setter references ${project}
 // refer to tests for expected behavior
-func resolvePattern(fieldValue, valueRegex, patternRegex string) (string, error) {
-	if valueRegex == "" {
-		return patternRegex, nil
+func resolvePattern(fieldValue string, valueRegex *regexp.Regexp, pattern string) (string, error) {
+	if valueRegex == nil {
+		return pattern, nil
 	}
-	r, err := regexp.Compile(valueRegex)
-	if err != nil {
-		return "", errors.Errorf("failed to compile input pattern %q: %s", valueRegex, err.Error())
-	}
-	captureGroup := r.FindStringSubmatch(fieldValue)
-	res := patternRegex
-	for i, val := range captureGroup {
-		if i == 0 {
-			continue
+	captureGroup := valueRegex.FindStringSubmatch(fieldValue)
+	var unresolved bool
+	res := captureGroupRef.ReplaceAllStringFunc(pattern, func(ref string) string {
+		m := captureGroupRef.FindStringSubmatch(ref)
+		name := m[1] + m[2]
+		i, err := strconv.Atoi(name)
+		if err != nil {
+			// named reference, resolve it only if it is a capture group of the regex
+			if i = valueRegex.SubexpIndex(name); i < 0 {
+				return ref
+			}
 		}
-		res = strings.ReplaceAll(res, fmt.Sprintf("${%d}", i), val)
-	}
+		if i < 1 || i >= len(captureGroup) {
+			unresolved = true
+			return ref
+		}
+		return captureGroup[i]
+	})
 
 	// make sure that all capture groups are resolved and throw error if they are not
-	re := regexp.MustCompile(`\$\{([0-9]+)\}`)
-	if re.Match([]byte(res)) {
+	if unresolved {
 		return "", errors.Errorf("unable to resolve capture groups")
 	}
 
@@ -392,9 +472,48 @@ func Decode(rn *yaml.RNode, fcd *SearchReplace) error {
 	fcd.PutValue = dm[PutValue]
 	fcd.PutComment = dm[PutComment]
 	fcd.ByFilePath = dm[ByFilePath]
+	return decodeSelector(dm, fcd)
+}
+
+// decodeSelector decodes the resource matchers into the Selector, the labels
+// and annotations are comma separated key=value pairs e.g. app=nginx,tier=web
+func decodeSelector(dm map[string]string, fcd *SearchReplace) error {
+	s := Selector{
+		Group:     dm[ByGroup],
+		Kind:      dm[ByKind],
+		Name:      dm[ByName],
+		Namespace: dm[ByNamespace],
+	}
+	var err error
+	if s.Labels, err = parseKeyValues(ByLabels, dm[ByLabels]); err != nil {
+		return err
+	}
+	if s.Annotations, err = parseKeyValues(ByAnnotations, dm[ByAnnotations]); err != nil {
+		return err
+	}
+	if s.Group != "" || s.Kind != "" || s.Name != "" || s.Namespace != "" ||
+		len(s.Labels) > 0 || len(s.Annotations) > 0 {
+		fcd.Selector = &s
+	}
 	return nil
 }
 
+// parseKeyValues parses the comma separated key=value pairs of the matcher
+func parseKeyValues(matcher, input string) (map[string]string, error) {
+	if input == "" {
+		return nil, nil
+	}
+	out := make(map[string]string)
+	for _, kv := range strings.Split(input, ",") {
+		parts := strings.SplitN(strings.TrimSpace(kv), "=", 2)
+		if len(parts) != 2 || parts[0] == "" {
+			return nil, errors.Errorf("invalid %s %q, must be comma separated key=value pairs", matcher, input)
+		}
+		out[parts[0]] = parts[1]
+	}
+	return out, nil
+}
+
 // validateMatcherNames validates the input matcher names
 func validateMatcherNames(m map[string]string) error {
 	matcherSet := sets.String{}
@@ -105,9 +105,77 @@ func TestDecode(t *testing.T) {
 	if !assert.Error(t, err) {
 		t.FailNow()
 	}
-	expected := `invalid matcher "put-values", must be one of ["by-value" "by-file-path" "by-value-regex" "by-path" "put-value" "put-comment"]`
+	expected := `invalid matcher "put-values", must be one of ["by-value" "by-file-path" "by-value-regex" "by-path" "by-kind" "by-group" "by-name" "by-namespace" "by-labels" "by-annotations" "put-value" "put-comment"]`
 	if !assert.Equal(t, expected, err.Error()) {
 		t.FailNow()
 	}
 
 }
+
+func TestSearchResultOldValue(t *testing.T) {
+	nodes, err := (&kio.ByteReader{Reader: strings.NewReader(`apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: nginx
+  annotations:
+    config.kubernetes.io/path: deployment.yaml
+spec:
+  template:
+    spec:
+      containers:
+        - name: nginx
+          image: gcr.io/nginx:1.7.1
+`)}).Read()
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+
+	sr := &SearchReplace{ByValueRegex: `gcr.io/(.*)`, PutValue: "us-docker.pkg.dev/my-project/$1"}
+	_, err = sr.Filter(nodes)
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+	assert.Equal(t, []SearchResult{{
+		FilePath:  "deployment.yaml",
+		FieldPath: "spec.template.spec.containers[0].image",
+		Value:     "us-docker.pkg.dev/my-project/nginx:1.7.1",
+		OldValue:  "gcr.io/nginx:1.7.1",
+	}}, sr.Results)
+
+	sr = &SearchReplace{ByPath: "metadata.name", PutValue: "the-deployment"}
+	_, err = sr.Filter(nodes)
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+	assert.Equal(t, []SearchResult{{
+		FilePath:  "deployment.yaml",
+		FieldPath: "metadata.name",
+		Value:     "the-deployment",
+		OldValue:  "nginx",
+	}}, sr.Results)
+}
+
+func TestDecodeSelector(t *testing.T) {
+	rn, err := kyaml.Parse(`data:
+  by-kind: Deployment
+  by-labels: app=nginx,tier=web`)
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+	sr := &SearchReplace{}
+	if !assert.NoError(t, Decode(rn, sr)) {
+		t.FailNow()
+	}
+	assert.Equal(t, &Selector{Kind: "Deployment", Labels: map[string]string{"app": "nginx", "tier": "web"}}, sr.Selector)
+
+	rn, err = kyaml.Parse(`data:
+  by-annotations: example.com/owner`)
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+	err = Decode(rn, &SearchReplace{})
+	if !assert.Error(t, err) {
+		t.FailNow()
+	}
+	assert.Equal(t, `invalid by-annotations "example.com/owner", must be comma separated key=value pairs`, err.Error())
+}
@@ -700,6 +700,216 @@ kind: Deployment
 metadata:
   name: foo1-prod-bar1-us-central-1-baz1
   namespace: foo2-prod-bar2-us-central-1-baz2
+ `,
+	},
+	{
+		name: "put value by regex numbered and named capture groups",
+		config: `
+data:
+  by-value-regex: gcr.io/(?P<image>.*)
+  put-value: us-docker.pkg.dev/${project}/$1-${image}
+`,
+		input: `
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: nginx-deployment
+  annotations:
+    image: gcr.io/nginx:1.7.1
+spec:
+  template:
+    spec:
+      containers:
+        - name: nginx
+          image: gcr.io/nginx:1.7.1
+          env:
+            - name: SIDECAR_IMAGE
+              value: gcr.io/proxy:1.0.0
+ `,
+		out: `${filePath}
+fieldPath: metadata.annotations.image
+value: us-docker.pkg.dev/${project}/nginx:1.7.1-nginx:1.7.1
+
+${filePath}
+fieldPath: spec.template.spec.containers[0].image
+value: us-docker.pkg.dev/${project}/nginx:1.7.1-nginx:1.7.1
+
+${filePath}
+fieldPath: spec.template.spec.containers[0].env[0].value
+value: us-docker.pkg.dev/${project}/proxy:1.0.0-proxy:1.0.0
+
+Mutated 3 field(s)
+`,
+		expectedResources: `
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: nginx-deployment
+  annotations:
+    image: us-docker.pkg.dev/${project}/nginx:1.7.1-nginx:1.7.1
+spec:
+  template:
+    spec:
+      containers:
+        - name: nginx
+          image: us-docker.pkg.dev/${project}/nginx:1.7.1-nginx:1.7.1
+          env:
+            - name: SIDECAR_IMAGE
+              value: us-docker.pkg.dev/${project}/proxy:1.0.0-proxy:1.0.0
+ `,
+	},
+	{
+		name: "put value by regex unresolved capture group error",
+		config: `
+data:
+  by-value-regex: gcr.io/(.*)
+  put-value: us-docker.pkg.dev/$2
+`,
+		input: `
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: gcr.io/nginx
+ `,
+		expectedResources: `
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: gcr.io/nginx
+ `,
+		errMsg: "unable to resolve capture groups",
+	},
+	{
+		name: "put value by path and kind",
+		config: `
+data:
+  by-path: spec.replicas
+  by-kind: Deployment
+  by-group: apps
+  put-value: "3"
+`,
+		input: `
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: nginx-deployment
+spec:
+  replicas: 1
+---
+apiVersion: example.com/v1
+kind: MyResource
+metadata:
+  name: nginx-deployment
+spec:
+  replicas: 1
+ `,
+		out: `${filePath}
+fieldPath: spec.replicas
+value: 3
+
+Mutated 1 field(s)
+`,
+		expectedResources: `
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: nginx-deployment
+spec:
+  replicas: 3
+---
+apiVersion: example.com/v1
+kind: MyResource
+metadata:
+  name: nginx-deployment
+spec:
+  replicas: 1
+ `,
+	},
+	{
+		name: "search by value, name, namespace, labels and annotations",
+		config: `
+data:
+  by-value: nginx
+  by-name: nginx
+  by-namespace: default
+  by-labels: app=nginx, tier=web
+  by-annotations: example.com/owner=team-a
+`,
+		input: `
+apiVersion: v1
+kind: Service
+metadata:
+  name: nginx
+  namespace: default
+  labels:
+    app: nginx
+    tier: web
+  annotations:
+    example.com/owner: team-a
+---
+apiVersion: v1
+kind: Service
+metadata:
+  name: nginx
+  namespace: default
+  labels:
+    app: nginx
+  annotations:
+    example.com/owner: team-a
+---
+apiVersion: v1
+kind: Service
+metadata:
+  name: nginx
+  namespace: other
+  labels:
+    app: nginx
+    tier: web
+  annotations:
+    example.com/owner: team-a
+ `,
+		out: `${filePath}
+fieldPath: metadata.name
+value: nginx
+
+${filePath}
+fieldPath: metadata.labels.app
+value: nginx
+
+Matched 2 field(s)
+`,
+		expectedResources: `
+apiVersion: v1
+kind: Service
+metadata:
+  name: nginx
+  namespace: default
+  labels:
+    app: nginx
+    tier: web
+  annotations:
+    example.com/owner: team-a
+---
+apiVersion: v1
+kind: Service
+metadata:
+  name: nginx
+  namespace: default
+  labels:
+    app: nginx
+  annotations:
+    example.com/owner: team-a
+---
+apiVersion: v1
+kind: Service
+metadata:
+  name: nginx
+  namespace: other
+  labels:
+    app: nginx
+    tier: web
+  annotations:
+    example.com/owner: team-a
  `,
 	},
 	{
@@ -776,4 +986,223 @@ spec:
 		out: `Mutated 0 field(s)
 `,
 	},
+	{
+		name: "search by path with predicates",
+		config: `
+data:
+  by-path: spec.template.spec.containers[name=nginx].env[name=LOG_LEVEL].value
+`,
+		input: `
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: nginx-deployment
+spec:
+  template:
+    spec:
+      containers:
+        - name: sidecar
+          env:
+            - name: LOG_LEVEL
+              value: info
+        - name: nginx
+          env:
+            - name: PORT
+              value: "80"
+            - name: LOG_LEVEL
+              value: info
+ `,
+		out: `${filePath}
+fieldPath: spec.template.spec.containers[1].env[1].value
+value: info
+
+Matched 1 field(s)
+`,
+		expectedResources: `
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: nginx-deployment
+spec:
+  template:
+    spec:
+      containers:
+        - name: sidecar
+          env:
+            - name: LOG_LEVEL
+              value: info
+        - name: nginx
+          env:
+            - name: PORT
+              value: "80"
+            - name: LOG_LEVEL
+              value: info
+ `,
+	},
+	{
+		name: "search by path with wildcards and scalar predicate",
+		config: `
+data:
+  by-path: '**.args[=--debug]'
+`,
+		input: `
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: nginx-deployment
+spec:
+  template:
+    spec:
+      containers:
+        - name: nginx
+          args:
+            - --port=80
+            - --debug
+ `,
+		out: `${filePath}
+fieldPath: spec.template.spec.containers[0].args[1]
+value: --debug
+
+Matched 1 field(s)
+`,
+		expectedResources: `
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: nginx-deployment
+spec:
+  template:
+    spec:
+      containers:
+        - name: nginx
+          args:
+            - --port=80
+            - --debug
+ `,
+	},
+	{
+		name: "put value by path with predicates",
+		config: `
+data:
+  by-path: spec.template.spec.containers[name=nginx].image
+  put-value: nginx:1.8.0
+`,
+		input: `
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: nginx-deployment
+spec:
+  template:
+    spec:
+      containers:
+        - name: sidecar
+          image: sidecar:1.0.0
+        - name: nginx
+          image: nginx:1.7.1
+ `,
+		out: `${filePath}
+fieldPath: spec.template.spec.containers[name=nginx].image
+value: nginx:1.8.0
+
+Mutated 1 field(s)
+`,
+		expectedResources: `
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: nginx-deployment
+spec:
+  template:
+    spec:
+      containers:
+        - name: sidecar
+          image: sidecar:1.0.0
+        - name: nginx
+          image: nginx:1.8.0
+ `,
+	},
+	{
+		name: "put value by path with predicates adds non-existing element",
+		config: `
+data:
+  by-path: spec.template.spec.containers[name=nginx].env[name=LOG_LEVEL].value
+  put-value: debug
+`,
+		input: `
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: nginx-deployment
+spec:
+  template:
+    spec:
+      containers:
+        - name: nginx
+          image: nginx:1.7.1
+ `,
+		out: `${filePath}
+fieldPath: spec.template.spec.containers[name=nginx].env[name=LOG_LEVEL].value
+value: debug
+
+Mutated 1 field(s)
+`,
+		expectedResources: `
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: nginx-deployment
+spec:
+  template:
+    spec:
+      containers:
+        - name: nginx
+          image: nginx:1.7.1
+          env:
+            - name: LOG_LEVEL
+              value: debug
+ `,
+	},
+	{
+		name: "put value by path with wildcards and predicates",
+		config: `
+data:
+  by-path: spec.**.containers[name=nginx].image
+  put-value: nginx:1.8.0
+`,
+		input: `
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: nginx-deployment
+spec:
+  template:
+    spec:
+      containers:
+        - name: sidecar
+          image: sidecar:1.0.0
+        - name: nginx
+          image: nginx:1.7.1
+ `,
+		out: `${filePath}
+fieldPath: spec.template.spec.containers[1].image
+value: nginx:1.8.0
+
+Mutated 1 field(s)
+`,
+		expectedResources: `
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: nginx-deployment
+spec:
+  template:
+    spec:
+      containers:
+        - name: sidecar
+          image: sidecar:1.0.0
+        - name: nginx
+          image: nginx:1.8.0
+ `,
+	},
 }
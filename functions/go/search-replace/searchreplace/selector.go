@@ -0,0 +1,61 @@
+package searchreplace
+
+import (
+	"strings"
+
+	"sigs.k8s.io/kustomize/kyaml/errors"
+	"sigs.k8s.io/kustomize/kyaml/yaml"
+)
+
+// Selector selects the resources on which the search and replace operation
+// is performed. All the non-empty criteria must match.
+type Selector struct {
+	// Group is the API group of the resource e.g. apps
+	Group string `json:"group,omitempty" yaml:"group,omitempty"`
+
+	// Version is the API version of the resource e.g. v1
+	Version string `json:"version,omitempty" yaml:"version,omitempty"`
+
+	// Kind is the kind of the resource e.g. Deployment
+	Kind string `json:"kind,omitempty" yaml:"kind,omitempty"`
+
+	// Name is the name of the resource
+	Name string `json:"name,omitempty" yaml:"name,omitempty"`
+
+	// Namespace is the namespace of the resource
+	Namespace string `json:"namespace,omitempty" yaml:"namespace,omitempty"`
+
+	// Labels are the labels which the resource must have
+	Labels map[string]string `json:"labels,omitempty" yaml:"labels,omitempty"`
+
+	// Annotations are the annotations which the resource must have
+	Annotations map[string]string `json:"annotations,omitempty" yaml:"annotations,omitempty"`
+}
+
+// matches returns true if the resource matches all the criteria of the selector
+func (s Selector) matches(node *yaml.RNode) (bool, error) {
+	meta, err := node.GetMeta()
+	if err != nil {
+		return false, errors.Wrap(err)
+	}
+	group, version := "", meta.APIVersion
+	if i := strings.LastIndex(meta.APIVersion, "/"); i >= 0 {
+		group, version = meta.APIVersion[:i], meta.APIVersion[i+1:]
+	}
+	if (s.Group != "" && s.Group != group) || (s.Version != "" && s.Version != version) ||
+		(s.Kind != "" && s.Kind != meta.Kind) || (s.Name != "" && s.Name != meta.Name) ||
+		(s.Namespace != "" && s.Namespace != meta.Namespace) {
+		return false, nil
+	}
+	for k, v := range s.Labels {
+		if meta.Labels[k] != v {
+			return false, nil
+		}
+	}
+	for k, v := range s.Annotations {
+		if meta.Annotations[k] != v {
+			return false, nil
+		}
+	}
+	return true, nil
+}
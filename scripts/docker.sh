@@ -45,7 +45,11 @@ function docker_build {
       build_args+=(--build-arg "FILENAME=${translated_name}_run.js")
       override_dockerfile="${function_dir}/build/${translated_name}.Dockerfile"
       ;;
-    *) override_dockerfile="${function_dir}"/Dockerfile ;;
+    *)
+      override_dockerfile="${function_dir}"/Dockerfile
+      # the thirdparty modules are replaced by their local copies in go.mod
+      build_args+=(--build-context "thirdparty=${repo_base}/thirdparty")
+      ;;
   esac
 
   dockerfile="${repo_base}/build/docker/${lang}/Dockerfile"
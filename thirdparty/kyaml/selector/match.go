@@ -0,0 +1,80 @@
+package selector
+
+import (
+	"strings"
+)
+
+// BackTrackMatch matches the traversed yamlPathElems with input patternElems, match
+// reports whether the yamlPathElems element at index i matches a pattern element
+// * matches any element, ** matches 0 or more elements, array elements are split and matched
+// e.g. [a,a,b,c,e,b] matches [a,*,b,**,b]
+func BackTrackMatch(yamlPathElems, patternElems []string, match func(i int, pattern string) bool) bool {
+	// this is a dynamic programming problem
+	// aim is to check if path array matches pattern array as per above rules
+	yamlPathElemsLen, patternElemsLen := len(yamlPathElems), len(patternElems)
+
+	// initialize a 2d boolean matrix to memorize results
+	// dp[i][j] stores the result, if yamlPath subarray of length i matches
+	// pattern subarray of length j
+	dp := make([][]bool, yamlPathElemsLen+1)
+	for i := range dp {
+		dp[i] = make([]bool, patternElemsLen+1)
+	}
+	dp[0][0] = true
+
+	// edge case 1: when pattern is empty, yamlPath of length grater than 0 doesn't match
+	for i := 1; i < yamlPathElemsLen+1; i++ {
+		dp[i][0] = false
+	}
+
+	// edge case 2: if yamlPath is empty, carry forward the previous result if the pattern element
+	// is `**` as it matches 0 or more elements.
+	for j := 1; j < patternElemsLen+1; j++ {
+		if patternElems[j-1] == "**" {
+			dp[0][j] = dp[0][j-1]
+		}
+	}
+
+	// fill rest of the matrix
+	for i := 1; i < yamlPathElemsLen+1; i++ {
+		for j := 1; j < patternElemsLen+1; j++ {
+			if patternElems[j-1] == "**" {
+				// `**` matches multiple elements, so carry forward the result from immediate
+				// neighbors, dp[i-1][j] match empty, dp[i][j-1] match multiple elements
+				dp[i][j] = dp[i][j-1] || dp[i-1][j]
+			} else if patternElems[j-1] == "*" || match(i-1, patternElems[j-1]) {
+				// if there is element match or `*` then get the result from previous diagonal element
+				dp[i][j] = dp[i-1][j-1]
+			}
+		}
+	}
+
+	/*Example matrix for yamlPath = [a,a,b,c,e,b] and pattern [a,*,b,**,b]
+		  a	a	b	c	e	b
+		a	T	F	F	F	F	F
+	  * F	T	F	F	F	F
+		b	F	F	T	F	F	F
+	 ** F	F	T	T	T	T
+		b	F	F	F	F	F	T
+	*/
+
+	return dp[yamlPathElemsLen][patternElemsLen]
+}
+
+// ElementMatch matches single element with pattern for single element
+func ElementMatch(elem, pattern string) bool {
+	// scalar field case `metadata` matches `metadata`
+	if elem == pattern {
+		return true
+	}
+	// array element e.g. a[*], *[*] and *[b] matches a[b]
+	if strings.Contains(elem, "[") {
+		elemParts := strings.Split(elem, "[")
+		patternParts := strings.Split(pattern, "[")
+		if patternParts[0] != "*" && elemParts[0] != patternParts[0] {
+			return false
+		}
+		return len(patternParts) > 1 && (patternParts[1] == "*]" || elemParts[1] == patternParts[1])
+	}
+	return false
+}
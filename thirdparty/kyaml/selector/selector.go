@@ -0,0 +1,111 @@
+// Package selector selects the resources of a package and the fields within
+// them, it is shared by the functions which take selectors e.g. apply-setters
+package selector
+
+import (
+	"strings"
+
+	"github.com/bmatcuk/doublestar/v4"
+	"sigs.k8s.io/kustomize/kyaml/errors"
+	"sigs.k8s.io/kustomize/kyaml/yaml"
+)
+
+// Selector selects the resources and the fields within them.
+// All the non-empty criteria must match.
+type Selector struct {
+	// Group is the API group of the resource e.g. apps
+	Group string `json:"group,omitempty" yaml:"group,omitempty"`
+
+	// Version is the API version of the resource e.g. v1
+	Version string `json:"version,omitempty" yaml:"version,omitempty"`
+
+	// Kind is the kind of the resource e.g. Deployment
+	Kind string `json:"kind,omitempty" yaml:"kind,omitempty"`
+
+	// Name is the name of the resource
+	Name string `json:"name,omitempty" yaml:"name,omitempty"`
+
+	// Namespace is the namespace of the resource
+	Namespace string `json:"namespace,omitempty" yaml:"namespace,omitempty"`
+
+	// Labels are the labels which the resource must have
+	Labels map[string]string `json:"labels,omitempty" yaml:"labels,omitempty"`
+
+	// Path is the glob pattern matching the file path of the resource
+	// e.g. overlays/**/*.yaml
+	Path string `json:"path,omitempty" yaml:"path,omitempty"`
+
+	// FieldPath is the pattern matching the path of the fields within the resource,
+	// * matches any element and ** matches 0 or more elements
+	// e.g. spec.**.containers[*].image
+	FieldPath string `json:"fieldPath,omitempty" yaml:"fieldPath,omitempty"`
+}
+
+// MatchesResource returns true if the resource in the input file path
+// matches all the resource criteria of the selector
+func (s Selector) MatchesResource(node *yaml.RNode, filePath string) (bool, error) {
+	meta, err := node.GetMeta()
+	if err != nil {
+		return false, errors.Wrap(err)
+	}
+	group, version := "", meta.APIVersion
+	if i := strings.LastIndex(meta.APIVersion, "/"); i >= 0 {
+		group, version = meta.APIVersion[:i], meta.APIVersion[i+1:]
+	}
+	if (s.Group != "" && s.Group != group) || (s.Version != "" && s.Version != version) ||
+		(s.Kind != "" && s.Kind != meta.Kind) || (s.Name != "" && s.Name != meta.Name) ||
+		(s.Namespace != "" && s.Namespace != meta.Namespace) {
+		return false, nil
+	}
+	for k, v := range s.Labels {
+		if meta.Labels[k] != v {
+			return false, nil
+		}
+	}
+	if s.Path != "" {
+		match, err := doublestar.Match(s.Path, filePath)
+		if err != nil {
+			return false, errors.WrapPrefixf(err, "invalid path pattern %q", s.Path)
+		}
+		return match, nil
+	}
+	return true, nil
+}
+
+// MatchesField returns true if the field path matches the field path pattern
+// of the selector e.g. .spec.replicas matches spec.*
+func (s Selector) MatchesField(path string) bool {
+	if s.FieldPath == "" {
+		return true
+	}
+	elems := strings.Split(strings.TrimPrefix(path, "."), ".")
+	return BackTrackMatch(elems, strings.Split(s.FieldPath, "."), func(i int, pattern string) bool {
+		return ElementMatch(elems[i], pattern)
+	})
+}
+
+// MatchingResource returns the selectors matching the resource in the input
+// file path, to be used for the fields of the resource
+func MatchingResource(selectors []Selector, node *yaml.RNode, filePath string) ([]Selector, error) {
+	var matched []Selector
+	for _, s := range selectors {
+		match, err := s.MatchesResource(node, filePath)
+		if err != nil {
+			return nil, err
+		}
+		if match {
+			matched = append(matched, s)
+		}
+	}
+	return matched, nil
+}
+
+// AnyMatchesField returns true if any of the selectors matches the field path
+func AnyMatchesField(selectors []Selector, path string) bool {
+	for _, s := range selectors {
+		if s.MatchesField(path) {
+			return true
+		}
+	}
+	return false
+}
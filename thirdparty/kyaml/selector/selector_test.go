@@ -0,0 +1,65 @@
+package selector
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"sigs.k8s.io/kustomize/kyaml/yaml"
+)
+
+func TestMatchesResource(t *testing.T) {
+	node := yaml.MustParse(`apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: nginx
+  namespace: default
+  labels:
+    tier: web
+`)
+	var tests = []struct {
+		name     string
+		selector Selector
+		filePath string
+		expected bool
+	}{
+		{name: "empty", selector: Selector{}, expected: true},
+		{name: "group version kind", selector: Selector{Group: "apps", Version: "v1", Kind: "Deployment"}, expected: true},
+		{name: "other group", selector: Selector{Group: "batch"}, expected: false},
+		{name: "name namespace", selector: Selector{Name: "nginx", Namespace: "default"}, expected: true},
+		{name: "other namespace", selector: Selector{Namespace: "prod"}, expected: false},
+		{name: "labels", selector: Selector{Labels: map[string]string{"tier": "web"}}, expected: true},
+		{name: "other labels", selector: Selector{Labels: map[string]string{"tier": "db"}}, expected: false},
+		{name: "path", selector: Selector{Path: "app/**/*.yaml"}, filePath: "app/base/deployment.yaml", expected: true},
+		{name: "other path", selector: Selector{Path: "db/*.yaml"}, filePath: "app/deployment.yaml", expected: false},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			match, err := test.selector.MatchesResource(node, test.filePath)
+			assert.NoError(t, err)
+			assert.Equal(t, test.expected, match)
+		})
+	}
+	_, err := Selector{Path: "app/[*.yaml"}.MatchesResource(node, "app/deployment.yaml")
+	assert.Error(t, err)
+}
+
+func TestMatchesField(t *testing.T) {
+	var tests = []struct {
+		path     string
+		pattern  string
+		expected bool
+	}{
+		{path: ".spec.replicas", pattern: "", expected: true},
+		{path: "spec.replicas", pattern: "spec.replicas", expected: true},
+		{path: "spec.replicas", pattern: "spec.*", expected: true},
+		{path: "spec.template.spec.containers[0].image", pattern: "spec.**.containers[*].image", expected: true},
+		{path: "spec.template.spec.containers[0].image", pattern: "**.image", expected: true},
+		{path: "spec.template.spec.containers[1].image", pattern: "spec.**.containers[0].image", expected: false},
+		{path: "metadata.namespace", pattern: "spec.**", expected: false},
+		{path: "spec.replicas", pattern: "spec", expected: false},
+	}
+	for _, test := range tests {
+		assert.Equal(t, test.expected, Selector{FieldPath: test.pattern}.MatchesField(test.path),
+			"path %q pattern %q", test.path, test.pattern)
+	}
+}